@@ -0,0 +1,112 @@
+// Command breachimport loads a breached-password corpus into the database for
+// the k-anonymity range API (GET /api/breach/range/:prefix).
+//
+// The corpus is a sorted list of SHA-1 hashes, optionally with ":count", such
+// as the Pwned Passwords "ordered by hash" download:
+//
+//	go run ./cmd/breachimport -file pwned-passwords-sha1-ordered-by-hash.txt
+//	xzcat corpus.txt.xz | go run ./cmd/breachimport -replace
+//
+// It uses the same PSVAULT_ environment as the server. Imports merge into the
+// existing corpus; -replace empties it first.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/lib/breach"
+	"github.com/Sameer16536/psvault/internal/logger"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+)
+
+func main() {
+	file := flag.String("file", "-", "corpus to import, or - for stdin")
+	replace := flag.Bool("replace", false, "remove the existing corpus before importing")
+	batchSize := flag.Int("batch", 50000, "hashes per transaction")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		panic("failed to load config: " + err.Error())
+	}
+	log := logger.NewLoggerWithService(cfg.Observability, nil)
+
+	if *batchSize < 1 {
+		log.Fatal().Msg("-batch must be at least 1")
+	}
+
+	in := os.Stdin
+	if *file != "-" {
+		if in, err = os.Open(*file); err != nil {
+			log.Fatal().Err(err).Msg("failed to open corpus")
+		}
+		defer in.Close()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if cfg.Primary.Env != "local" {
+		if err := database.Migrate(ctx, &log, cfg); err != nil {
+			log.Fatal().Err(err).Msg("failed to migrate database")
+		}
+	}
+	db, err := database.New(cfg, &log, nil)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to database")
+	}
+	defer db.Close()
+	repo := repository.NewBreachRepository(&server.Server{Config: cfg, Logger: &log, DB: db})
+
+	if *replace {
+		if err := repo.Truncate(ctx); err != nil {
+			log.Fatal().Err(err).Msg("failed to remove existing corpus")
+		}
+		log.Info().Msg("removed existing corpus")
+	}
+
+	start := time.Now()
+	reader := breach.NewReader(in)
+	batch := make([]breach.Entry, 0, *batchSize)
+	imported := 0
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := repo.Import(ctx, batch); err != nil {
+			log.Fatal().Err(err).Int("line", reader.Line()).Msg("failed to import batch")
+		}
+		imported += len(batch)
+		batch = batch[:0]
+		log.Info().Int("imported", imported).Dur("elapsed", time.Since(start)).Msg("imported batch")
+	}
+	for {
+		e, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to read corpus")
+		}
+		batch = append(batch, e)
+		if len(batch) == *batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	total, err := repo.Count(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to count corpus")
+	}
+	log.Info().Int("imported", imported).Int64("corpus_size", total).Dur("elapsed", time.Since(start)).Msg("import complete")
+}
@@ -1,81 +1,23 @@
+// Command psvault is the psvault server and command-line client.
+//
+//	psvault serve                       run the API server
+//	psvault login --client-id ID        sign the client commands in as a service account
+//	psvault secret get "Prod DB" --field password
+//	psvault run --vault prod -- ./deploy.sh
+//	psvault migrate status              operator commands use the server config
+//
+// Run psvault help for every command.
 package main
 
 import (
 	"context"
-	"errors"
-	"net/http"
 	"os"
-	"os/signal"
-	"time"
 
-	"github.com/Sameer16536/psvault/internal/config"
-	"github.com/Sameer16536/psvault/internal/database"
-	"github.com/Sameer16536/psvault/internal/handler"
-	"github.com/Sameer16536/psvault/internal/logger"
-	"github.com/Sameer16536/psvault/internal/repository"
-	"github.com/Sameer16536/psvault/internal/router"
-	"github.com/Sameer16536/psvault/internal/server"
-	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/Sameer16536/psvault/internal/admin"
+	"github.com/Sameer16536/psvault/internal/cli"
 )
 
-const DefaultContextTimeout = 30
-
 func main() {
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		panic("failed to load config: " + err.Error())
-	}
-
-	// Initialize New Relic logger service
-	loggerService := logger.NewLoggerService(cfg.Observability)
-	defer loggerService.Shutdown()
-
-	log := logger.NewLoggerWithService(cfg.Observability, loggerService)
-
-	if cfg.Primary.Env != "local" {
-		if err := database.Migrate(context.Background(), &log, cfg); err != nil {
-			log.Fatal().Err(err).Msg("failed to migrate database")
-		}
-	}
-
-	// Initialize server
-	srv, err := server.New(cfg, &log, loggerService)
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to initialize server")
-	}
-
-	// Initialize repositories, services, and handlers
-	repos := repository.NewRepositories(srv)
-	services, serviceErr := service.NewServices(srv, repos)
-	if serviceErr != nil {
-		log.Fatal().Err(serviceErr).Msg("could not create services")
-	}
-	handlers := handler.NewHandlers(srv, services)
-
-	// Initialize router
-	r := router.NewRouter(srv, handlers, services)
-
-	// Setup HTTP server
-	srv.SetupHTTPServer(r)
-
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-
-	// Start server
-	go func() {
-		if err = srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatal().Err(err).Msg("failed to start server")
-		}
-	}()
-
-	// Wait for interrupt signal to gracefully shutdown the server
-	<-ctx.Done()
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout*time.Second)
-
-	if err = srv.Shutdown(ctx); err != nil {
-		log.Fatal().Err(err).Msg("server forced to shutdown")
-	}
-	stop()
-	cancel()
-
-	log.Info().Msg("server exited properly")
+	commands := cli.Merge([]*cli.Command{serveCommand()}, cli.Commands(), admin.Commands())
+	os.Exit(cli.Main(context.Background(), cli.DefaultEnv(), commands, os.Args[1:]))
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/handler"
+	"github.com/Sameer16536/psvault/internal/logger"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/router"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+)
+
+const DefaultContextTimeout = 30
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "serve",
+		Summary: "Run the API server and background jobs",
+		Run:     serve,
+	}
+}
+
+func serve(_ context.Context, _ *cli.Env, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("serve takes no arguments")
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Initialize New Relic logger service
+	loggerService := logger.NewLoggerService(cfg.Observability)
+	defer loggerService.Shutdown()
+
+	log := logger.NewLoggerWithService(cfg.Observability, loggerService)
+
+	if cfg.Primary.Env != "local" {
+		if err := database.Migrate(context.Background(), &log, cfg); err != nil {
+			log.Fatal().Err(err).Msg("failed to migrate database")
+		}
+	}
+
+	// Initialize server
+	srv, err := server.New(cfg, &log, loggerService)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize server")
+	}
+
+	// Initialize repositories, services, and handlers
+	repos := repository.NewRepositories(srv)
+	services, serviceErr := service.NewServices(srv, repos)
+	if serviceErr != nil {
+		log.Fatal().Err(serviceErr).Msg("could not create services")
+	}
+	handlers := handler.NewHandlers(srv, services)
+
+	// Start background jobs once services have registered their tasks
+	if err := srv.StartJobs(); err != nil {
+		log.Fatal().Err(err).Msg("failed to start background jobs")
+	}
+
+	// Initialize router
+	r, err := router.NewRouter(srv, handlers, services)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize router")
+	}
+
+	// Setup HTTP server
+	srv.SetupHTTPServer(r)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+
+	// Start server
+	go func() {
+		if err = srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("failed to start server")
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the server
+	<-ctx.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultContextTimeout*time.Second)
+
+	if err = srv.Shutdown(ctx); err != nil {
+		log.Fatal().Err(err).Msg("server forced to shutdown")
+	}
+	stop()
+	cancel()
+
+	log.Info().Msg("server exited properly")
+	return nil
+}
@@ -10,4 +10,4 @@ echo "Running migrations..."
 tern migrate -m ./internal/database/migrations --conn-string "$PSVAULT_DB_DSN"
 
 echo "Starting server..."
-exec ./server
+exec ./server serve
@@ -0,0 +1,113 @@
+// Package admin implements the operator commands of the psvault server
+// binary. They load the server configuration and wiring like psvault serve,
+// but never start the HTTP server or the job workers.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/rs/zerolog"
+)
+
+// shutdownTimeout bounds closing the connections an admin command opened
+const shutdownTimeout = 10 * time.Second
+
+// Commands returns the operator commands. They act across tenants, so their
+// repositories run without row-level security.
+func Commands() []*cli.Command {
+	commands := []*cli.Command{
+		migrateCommand(),
+		dbCommand(),
+		usersCommand(),
+		vaultCommand(),
+		auditCommand(),
+		jobsCommand(),
+		purgeExpiredCommand(),
+	}
+	for _, cmd := range commands {
+		unscoped(cmd)
+	}
+	return commands
+}
+
+// unscoped runs cmd and its subcommands with a repository.Unscoped context
+func unscoped(cmd *cli.Command) {
+	if run := cmd.Run; run != nil {
+		cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+			return run(repository.Unscoped(ctx), env, args)
+		}
+	}
+	for _, sub := range cmd.Subcommands {
+		unscoped(sub)
+	}
+}
+
+// newLogger logs warnings to stderr, keeping stdout for command output
+func newLogger(env *cli.Env) zerolog.Logger {
+	return zerolog.New(zerolog.ConsoleWriter{Out: env.Stderr, TimeFormat: time.DateTime}).
+		Level(zerolog.WarnLevel).
+		With().
+		Timestamp().
+		Logger()
+}
+
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return cfg, nil
+}
+
+// runtime is the server wiring an admin command works with
+type runtime struct {
+	env    *cli.Env
+	cfg    *config.Config
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+// open connects to the database, Redis and job queues with the server's
+// configuration
+func open(env *cli.Env) (*runtime, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	log := newLogger(env)
+	srv, err := server.New(cfg, &log, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime{env: env, cfg: cfg, server: srv, repos: repository.NewRepositories(srv)}, nil
+}
+
+func (rt *runtime) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := rt.server.Shutdown(ctx); err != nil {
+		fmt.Fprintf(rt.env.Stderr, "psvault: %v\n", err)
+	}
+	_ = rt.server.Redis.Close()
+}
+
+func printJSON(env *cli.Env, v any) error {
+	enc := json.NewEncoder(env.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// formatTime prints an optional timestamp for tables
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	since, err := parseSince("", now)
+	require.NoError(t, err)
+	assert.True(t, since.IsZero())
+
+	since, err = parseSince("48h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-48*time.Hour), since)
+
+	since, err = parseSince("2024-01-31", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), since)
+
+	for _, value := range []string{"-1h", "yesterday"} {
+		_, err = parseSince(value, now)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestCommandsRejectBadArguments(t *testing.T) {
+	// Malformed command lines fail before any connection is opened
+	for _, args := range [][]string{
+		{"migrate", "to"},
+		{"migrate", "to", "latest"},
+		{"migrate", "status", "extra"},
+		{"users", "list", "--limit", "0"},
+		{"jobs", "inspect", "--queue", "nope"},
+	} {
+		var stderr bytes.Buffer
+		env := &cli.Env{Stdout: &bytes.Buffer{}, Stderr: &stderr}
+		assert.Equal(t, 2, cli.Main(context.Background(), env, Commands(), args), args)
+		assert.Contains(t, stderr.String(), "Usage: psvault", args)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "3.0 MiB", formatBytes(3<<20))
+}
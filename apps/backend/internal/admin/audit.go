@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+)
+
+func auditCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "audit",
+		Summary: "Check the audit log",
+		Subcommands: []*cli.Command{
+			auditVerifyCommand(),
+		},
+	}
+}
+
+func auditVerifyCommand() *cli.Command {
+	cmd := &cli.Command{Name: "verify", Args: "[--since DURATION|DATE] [--json]", Summary: "Check the audit log for missing or inconsistent entries; exits 1 on failure"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "audit verify", cmd.Args)
+		sinceFlag := fs.String("since", "", "only check rows created since, e.g. 720h or 2024-01-31 (default everything)")
+		asJSON := fs.Bool("json", false, "print JSON")
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		since, err := parseSince(*sinceFlag, time.Now())
+		if err != nil {
+			return cli.UsageError(fs, "invalid --since: %v", err)
+		}
+		rt, err := open(env)
+		if err != nil {
+			return err
+		}
+		defer rt.Close()
+
+		checks, err := rt.repos.Admin.VerifyAudit(ctx, since)
+		if err != nil {
+			return fmt.Errorf("failed to verify audit log: %w", err)
+		}
+		failed := false
+		for _, check := range checks {
+			failed = failed || !check.Passed()
+		}
+		if *asJSON {
+			if err := printJSON(env, checks); err != nil {
+				return err
+			}
+		} else {
+			w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+			for _, check := range checks {
+				if check.Passed() {
+					fmt.Fprintf(w, "%s\tok\t\n", check.Name)
+					continue
+				}
+				fmt.Fprintf(w, "%s\tFAIL\t%d %s, e.g. %s\n", check.Name, check.Count, check.Description, strings.Join(check.Sample, ", "))
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+		if failed {
+			return &cli.ExitError{Code: 1}
+		}
+		return nil
+	}
+	return cmd
+}
+
+// parseSince accepts a duration before now or a date; empty means everything
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("negative duration %q", value)
+		}
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration nor a date", value)
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+	"github.com/Sameer16536/psvault/internal/database"
+)
+
+// checkTimeout bounds each db check probe
+const checkTimeout = 5 * time.Second
+
+func dbCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "db",
+		Summary: "Check database and Redis health",
+		Subcommands: []*cli.Command{
+			dbCheckCommand(),
+		},
+	}
+}
+
+func dbCheckCommand() *cli.Command {
+	cmd := &cli.Command{Name: "check", Summary: "Check connectivity and that the schema is up to date; exits 1 on failure"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "db check", cmd.Args)
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		rt, err := open(env)
+		if err != nil {
+			return err
+		}
+		defer rt.Close()
+
+		failed := false
+		w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		report := func(name string, err error, detail string) {
+			if err != nil {
+				failed = true
+				fmt.Fprintf(w, "%s\tFAIL\t%v\n", name, err)
+				return
+			}
+			fmt.Fprintf(w, "%s\tok\t%s\n", name, detail)
+		}
+
+		db := rt.cfg.Database
+		probe, cancel := context.WithTimeout(ctx, checkTimeout)
+		report("database", rt.server.DB.Pool.Ping(probe),
+			fmt.Sprintf("%s/%s", net.JoinHostPort(db.Host, strconv.Itoa(db.Port)), db.Name))
+		cancel()
+
+		status, err := database.GetMigrationStatus(ctx, rt.cfg)
+		switch {
+		case err != nil:
+			report("schema", err, "")
+		case status.Pending():
+			report("schema", fmt.Errorf("version %d of %d; run psvault migrate up", status.Current, status.Latest), "")
+		default:
+			report("schema", nil, fmt.Sprintf("version %d of %d", status.Current, status.Latest))
+		}
+
+		probe, cancel = context.WithTimeout(ctx, checkTimeout)
+		report("redis", rt.server.Redis.Ping(probe).Err(), rt.cfg.Redis.Address)
+		cancel()
+
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if failed {
+			return &cli.ExitError{Code: 1}
+		}
+		return nil
+	}
+	return cmd
+}
@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/hibiken/asynq"
+)
+
+// queueInfo is the state of one job queue
+type queueInfo struct {
+	Queue     string `json:"queue"`
+	Size      int    `json:"size"`
+	Pending   int    `json:"pending"`
+	Active    int    `json:"active"`
+	Scheduled int    `json:"scheduled"`
+	Retry     int    `json:"retry"`
+	Archived  int    `json:"archived"`
+	Processed int    `json:"processedToday"`
+	Failed    int    `json:"failedToday"`
+	Paused    bool   `json:"paused"`
+}
+
+func jobsCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "jobs",
+		Summary: "Inspect background job queues",
+		Subcommands: []*cli.Command{
+			jobsInspectCommand(),
+		},
+	}
+}
+
+func jobsInspectCommand() *cli.Command {
+	cmd := &cli.Command{Name: "inspect", Args: "[--queue NAME] [--json]", Summary: "Show task counts per queue"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "jobs inspect", cmd.Args)
+		queue := fs.String("queue", "", "only show this queue")
+		asJSON := fs.Bool("json", false, "print JSON")
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		queues := make([]string, 0, len(job.QueuePriorities))
+		for name := range job.QueuePriorities {
+			queues = append(queues, name)
+		}
+		slices.Sort(queues)
+		if *queue != "" {
+			if _, ok := job.QueuePriorities[*queue]; !ok {
+				return cli.UsageError(fs, "unknown queue %q", *queue)
+			}
+			queues = []string{*queue}
+		}
+		rt, err := open(env)
+		if err != nil {
+			return err
+		}
+		defer rt.Close()
+
+		inspector := rt.server.Job.Inspector()
+		defer inspector.Close()
+		infos := make([]queueInfo, 0, len(queues))
+		for _, name := range queues {
+			info, err := inspector.GetQueueInfo(name)
+			switch {
+			case errors.Is(err, asynq.ErrQueueNotFound):
+				// Queues only exist in Redis once a task has been enqueued
+				infos = append(infos, queueInfo{Queue: name})
+			case err != nil:
+				return fmt.Errorf("failed to inspect queue %s: %w", name, err)
+			default:
+				infos = append(infos, queueInfo{
+					Queue:     name,
+					Size:      info.Size,
+					Pending:   info.Pending,
+					Active:    info.Active,
+					Scheduled: info.Scheduled,
+					Retry:     info.Retry,
+					Archived:  info.Archived,
+					Processed: info.Processed,
+					Failed:    info.Failed,
+					Paused:    info.Paused,
+				})
+			}
+		}
+		if *asJSON {
+			return printJSON(env, infos)
+		}
+		w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "QUEUE\tSIZE\tPENDING\tACTIVE\tSCHEDULED\tRETRY\tARCHIVED\tPROCESSED TODAY\tFAILED TODAY\tPAUSED")
+		for _, q := range infos {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%t\n", q.Queue, q.Size, q.Pending, q.Active,
+				q.Scheduled, q.Retry, q.Archived, q.Processed, q.Failed, q.Paused)
+		}
+		return w.Flush()
+	}
+	return cmd
+}
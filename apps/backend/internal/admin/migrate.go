@@ -0,0 +1,161 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+	"github.com/Sameer16536/psvault/internal/database"
+)
+
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "migrate",
+		Summary: "Apply, roll back and inspect database migrations",
+		Subcommands: []*cli.Command{
+			migrateUpCommand(),
+			migrateDownCommand(),
+			migrateToCommand(),
+			migrateStatusCommand(),
+		},
+	}
+}
+
+func migrateUpCommand() *cli.Command {
+	cmd := &cli.Command{Name: "up", Summary: "Apply every pending migration"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "migrate up", cmd.Args)
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		return migrate(ctx, env, func(*database.MigrationStatus) (int32, error) { return -1, nil }, false)
+	}
+	return cmd
+}
+
+func migrateDownCommand() *cli.Command {
+	cmd := &cli.Command{Name: "down", Args: "--yes", Summary: "Roll back the latest applied migration"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "migrate down", cmd.Args)
+		yes := fs.Bool("yes", false, "confirm rolling back, which can drop data")
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		target := func(status *database.MigrationStatus) (int32, error) {
+			if status.Current == 0 {
+				return 0, errors.New("no migrations are applied")
+			}
+			return status.Current - 1, nil
+		}
+		return migrate(ctx, env, target, *yes)
+	}
+	return cmd
+}
+
+func migrateToCommand() *cli.Command {
+	cmd := &cli.Command{Name: "to", Args: "VERSION [--yes]", Summary: "Migrate up or down to a version"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "migrate to", cmd.Args)
+		yes := fs.Bool("yes", false, "confirm rolling back, which can drop data")
+		positional, err := cli.ParseArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return cli.UsageError(fs, "expected a version")
+		}
+		version, err := strconv.ParseInt(positional[0], 10, 32)
+		if err != nil || version < 0 {
+			return cli.UsageError(fs, "invalid version %q", positional[0])
+		}
+		return migrate(ctx, env, func(*database.MigrationStatus) (int32, error) { return int32(version), nil }, *yes)
+	}
+	return cmd
+}
+
+func migrateStatusCommand() *cli.Command {
+	cmd := &cli.Command{Name: "status", Args: "[--json]", Summary: "List migrations and whether they are applied"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "migrate status", cmd.Args)
+		asJSON := fs.Bool("json", false, "print JSON")
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		status, err := database.GetMigrationStatus(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(env, status)
+		}
+		w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, m := range status.Migrations {
+			applied := "no"
+			if m.Applied {
+				applied = "yes"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", m.Version, m.Name, applied)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.Stdout, "\nSchema version %d of %d\n", status.Current, status.Latest)
+		return nil
+	}
+	return cmd
+}
+
+// migrate moves the schema to the version target picks from the current
+// status. Rolling back requires confirm since down migrations drop data.
+func migrate(ctx context.Context, env *cli.Env, target func(*database.MigrationStatus) (int32, error), confirm bool) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	status, err := database.GetMigrationStatus(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	version, err := target(status)
+	if err != nil {
+		return err
+	}
+	if version < 0 {
+		version = status.Latest
+	}
+	if version < status.Current && !confirm {
+		return fmt.Errorf("rolling back from version %d to %d can drop data; pass --yes to confirm", status.Current, version)
+	}
+	from := status.Current
+	log := newLogger(env)
+	if err := database.MigrateTo(ctx, &log, cfg, version); err != nil {
+		return err
+	}
+	if from == version {
+		fmt.Fprintf(env.Stdout, "Schema is at version %d\n", version)
+	} else {
+		fmt.Fprintf(env.Stdout, "Migrated schema from version %d to %d\n", from, version)
+	}
+	return nil
+}
+
+// parseNoArgs parses flags for commands that take no positional arguments
+func parseNoArgs(fs *flag.FlagSet, args []string) error {
+	positional, err := cli.ParseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return cli.UsageError(fs, "unexpected argument %q", positional[0])
+	}
+	return nil
+}
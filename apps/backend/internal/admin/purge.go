@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+)
+
+func purgeExpiredCommand() *cli.Command {
+	cmd := &cli.Command{Name: "purge-expired", Summary: "Purge expired share links and service account tokens"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "purge-expired", cmd.Args)
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		rt, err := open(env)
+		if err != nil {
+			return err
+		}
+		defer rt.Close()
+
+		purged, deleted, err := rt.repos.Share.SweepExpired(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to sweep expired shares: %w", err)
+		}
+		tokens, err := rt.repos.ServiceAccount.DeleteExpiredTokens(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to delete expired tokens: %w", err)
+		}
+		fmt.Fprintf(env.Stdout, "Purged %d expired shares, deleted %d purged shares and %d expired service account tokens\n",
+			purged, deleted, tokens)
+		return nil
+	}
+	return cmd
+}
@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+)
+
+func usersCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "users",
+		Summary: "Inspect users",
+		Subcommands: []*cli.Command{
+			usersListCommand(),
+		},
+	}
+}
+
+func usersListCommand() *cli.Command {
+	cmd := &cli.Command{Name: "list", Args: "[--limit N] [--json]", Summary: "List users by most recent activity"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "users list", cmd.Args)
+		limit := fs.Int("limit", 100, "maximum users to list")
+		asJSON := fs.Bool("json", false, "print JSON")
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		if *limit < 1 {
+			return cli.UsageError(fs, "--limit must be positive")
+		}
+		rt, err := open(env)
+		if err != nil {
+			return err
+		}
+		defer rt.Close()
+
+		users, err := rt.repos.Admin.ListUsers(ctx, *limit)
+		if err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+		if *asJSON {
+			return printJSON(env, users)
+		}
+		w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCLERK ID\tEMAIL\tVAULTS\tDEVICES\tLAST ACTIVE")
+		for _, u := range users {
+			email := "-"
+			if u.Email != nil {
+				email = *u.Email
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", u.ID, u.ExternalAuthID, email, u.Vaults, u.Devices, formatTime(u.LastActiveAt))
+		}
+		return w.Flush()
+	}
+	return cmd
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/Sameer16536/psvault/internal/cli"
+	"github.com/Sameer16536/psvault/internal/model/admin"
+)
+
+// vaultCommand adds operator subcommands to the client's vault group
+func vaultCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "vault",
+		Summary: "List and create vaults",
+		Subcommands: []*cli.Command{
+			vaultStatsCommand(),
+		},
+	}
+}
+
+func vaultStatsCommand() *cli.Command {
+	cmd := &cli.Command{Name: "stats", Args: "[--json]", Summary: "Count secrets, members and attachments of every vault (server)"}
+	cmd.Run = func(ctx context.Context, env *cli.Env, args []string) error {
+		fs := cli.NewFlagSet(env, "vault stats", cmd.Args)
+		asJSON := fs.Bool("json", false, "print JSON")
+		if err := parseNoArgs(fs, args); err != nil {
+			return err
+		}
+		rt, err := open(env)
+		if err != nil {
+			return err
+		}
+		defer rt.Close()
+
+		stats, err := rt.repos.Admin.VaultStats(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to collect vault stats: %w", err)
+		}
+		if *asJSON {
+			return printJSON(env, stats)
+		}
+		var total admin.VaultStats
+		w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tOWNER\tSECRETS\tMEMBERS\tATTACHMENTS\tSIZE\tLAST ACTIVITY")
+		for _, s := range stats {
+			owner := s.UserID
+			if s.OrgID != nil {
+				owner = *s.OrgID
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\n", s.ID, s.Name, owner, s.Secrets, s.Members,
+				s.Attachments, formatBytes(s.AttachmentBytes), formatTime(s.LastActivityAt))
+			total.Secrets += s.Secrets
+			total.Members += s.Members
+			total.Attachments += s.Attachments
+			total.AttachmentBytes += s.AttachmentBytes
+		}
+		fmt.Fprintf(w, "%d vaults\t\t\t%d\t%d\t%d\t%s\t\n", len(stats), total.Secrets, total.Members,
+			total.Attachments, formatBytes(total.AttachmentBytes))
+		return w.Flush()
+	}
+	return cmd
+}
+
+// formatBytes prints a size with a binary unit, e.g. 1.5 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,186 @@
+// Package cli implements the psvault command-line client. It talks to a
+// psvault server through pkg/client, signed in as a service account, and
+// encrypts and decrypts secrets locally with the web app's scheme (see
+// lib/vaultcrypto), so plaintext never leaves the machine.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Env is the process environment a command runs in
+type Env struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Getenv func(string) string
+	// ConfigDir holds the stored login
+	ConfigDir string
+
+	stdin *bufio.Reader
+}
+
+// DefaultEnv returns the environment of the current process. The login is
+// stored under the user's config directory unless PSVAULT_CONFIG_DIR is set.
+func DefaultEnv() *Env {
+	dir := os.Getenv("PSVAULT_CONFIG_DIR")
+	if dir == "" {
+		if base, err := os.UserConfigDir(); err == nil {
+			dir = filepath.Join(base, "psvault")
+		}
+	}
+	return &Env{
+		Stdin:     os.Stdin,
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+		Getenv:    os.Getenv,
+		ConfigDir: dir,
+	}
+}
+
+// Command is a psvault subcommand, or a group of them when Subcommands is set
+type Command struct {
+	Name        string
+	Args        string
+	Summary     string
+	Subcommands []*Command
+	Run         func(ctx context.Context, env *Env, args []string) error
+}
+
+// ExitError ends the process with Code without printing anything, e.g. to
+// pass on the exit status of a command started by psvault run
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// errUsage reports a malformed command line; the usage has already been printed
+var errUsage = errors.New("usage")
+
+// Commands returns the client commands; the server binary adds its own
+func Commands() []*Command {
+	return []*Command{
+		loginCommand(),
+		logoutCommand(),
+		vaultCommand(),
+		secretCommand(),
+		runCommand(),
+	}
+}
+
+// Merge combines command lists in order. Groups that share a name are
+// merged, so the server binary can add e.g. vault stats to the client's
+// vault group.
+func Merge(lists ...[]*Command) []*Command {
+	var merged []*Command
+	groups := make(map[string]*Command)
+	for _, list := range lists {
+		for _, cmd := range list {
+			if group, ok := groups[cmd.Name]; ok && len(group.Subcommands) > 0 && len(cmd.Subcommands) > 0 {
+				group.Subcommands = append(group.Subcommands, cmd.Subcommands...)
+				continue
+			}
+			if len(cmd.Subcommands) > 0 {
+				// Copy the group so merging never changes the caller's commands
+				group := *cmd
+				group.Subcommands = append([]*Command(nil), cmd.Subcommands...)
+				cmd = &group
+				groups[cmd.Name] = cmd
+			}
+			merged = append(merged, cmd)
+		}
+	}
+	return merged
+}
+
+// Main runs the command named by args and returns the process exit code
+func Main(ctx context.Context, env *Env, commands []*Command, args []string) int {
+	err := dispatch(ctx, env, "psvault", commands, args)
+	var exit *ExitError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &exit):
+		return exit.Code
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(env.Stderr, "psvault: %v\n", describeError(err))
+		return 1
+	}
+}
+
+func dispatch(ctx context.Context, env *Env, prefix string, commands []*Command, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(env.Stderr, prefix, commands)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+	for _, cmd := range commands {
+		if cmd.Name != args[0] {
+			continue
+		}
+		if len(cmd.Subcommands) > 0 {
+			return dispatch(ctx, env, prefix+" "+cmd.Name, cmd.Subcommands, args[1:])
+		}
+		return cmd.Run(ctx, env, args[1:])
+	}
+	fmt.Fprintf(env.Stderr, "%s: unknown command %q\n\n", prefix, args[0])
+	printUsage(env.Stderr, prefix, commands)
+	return errUsage
+}
+
+func printUsage(w io.Writer, prefix string, commands []*Command) {
+	fmt.Fprintf(w, "Usage: %s <command> [arguments]\n\nCommands:\n", prefix)
+	defer fmt.Fprintf(w, "\nRun %s <command> -h for its arguments.\n", prefix)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.Name, cmd.Summary)
+	}
+}
+
+// NewFlagSet returns a flag set that prints usage for "psvault name args"
+func NewFlagSet(env *Env, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(env.Stderr, "Usage: psvault %s %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// ParseArgs parses flags wherever they appear among the positional arguments,
+// which the flag package alone only accepts before the first positional one
+func ParseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// UsageError prints a message and the usage of fs
+func UsageError(fs *flag.FlagSet, format string, a ...any) error {
+	fmt.Fprintf(fs.Output(), format+"\n", a...)
+	fs.Usage()
+	return errUsage
+}
@@ -0,0 +1,230 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/lib/vaultcrypto"
+	"github.com/Sameer16536/psvault/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testVaultID      = "550e8400-e29b-41d4-a716-446655440000"
+	testSecretID     = "660e8400-e29b-41d4-a716-446655440001"
+	testMasterPass   = "master"
+	testClientID     = "880e8400-e29b-41d4-a716-446655440003"
+	testClientSecret = "psv_sk_test"
+)
+
+// fakeServer serves one vault holding one secret and records created secrets
+type fakeServer struct {
+	t        *testing.T
+	vault    *client.Vault
+	secrets  []*client.Secret
+	created  []client.CreateSecretRequest
+	vaultKey []byte
+	// stepUp refuses to list secrets as the server does for stale user sessions
+	stepUp bool
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	vaultKey, err := vaultcrypto.GenerateVaultKey()
+	require.NoError(t, err)
+	encryptedKey, err := vaultcrypto.EncryptVaultKey(vaultKey, testMasterPass)
+	require.NoError(t, err)
+	payload, err := vaultcrypto.Encrypt([]byte(`{"username":"app","password":"s3cret"}`), vaultKey)
+	require.NoError(t, err)
+	return &fakeServer{
+		t:        t,
+		vaultKey: vaultKey,
+		vault:    &client.Vault{ID: testVaultID, Name: "Prod", EncryptedKey: encryptedKey},
+		secrets: []*client.Secret{{
+			ID:               testSecretID,
+			VaultID:          testVaultID,
+			Type:             "password",
+			EncryptedPayload: payload,
+			Metadata:         client.SecretMetadata{Title: "Prod DB"},
+		}},
+	}
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/api/auth/token" {
+		var req map[string]string
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		if req["client_id"] != testClientID || req["client_secret"] != testClientSecret {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]string{"message": "Invalid client credentials"})
+			return
+		}
+		writeJSON(w, map[string]any{"access_token": "test-token", "expires_in": 900})
+		return
+	}
+	assert.Equal(f.t, "Bearer test-token", r.Header.Get("Authorization"))
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/vaults":
+		writeJSON(w, []*client.Vault{f.vault})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/secrets/search":
+		var results []*client.Secret
+		for _, s := range f.secrets {
+			if strings.Contains(strings.ToLower(s.Metadata.Title), strings.ToLower(r.URL.Query().Get("title"))) {
+				results = append(results, s)
+			}
+		}
+		writeJSON(w, results)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/vaults/"+testVaultID+"/secrets":
+		if f.stepUp {
+			w.WriteHeader(http.StatusForbidden)
+			writeJSON(w, map[string]string{"code": "REAUTHENTICATION_REQUIRED", "message": "This action requires a recent sign-in"})
+			return
+		}
+		writeJSON(w, f.secrets)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/secrets":
+		var req client.CreateSecretRequest
+		require.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+		f.created = append(f.created, req)
+		writeJSON(w, client.Secret{ID: "770e8400-e29b-41d4-a716-446655440002", VaultID: req.VaultID, Metadata: req.Metadata})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		writeJSON(w, map[string]string{"message": "Not found"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func runCLI(t *testing.T, server *httptest.Server, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	vars := map[string]string{
+		"PSVAULT_URL":             server.URL,
+		"PSVAULT_CLIENT_ID":       testClientID,
+		"PSVAULT_CLIENT_SECRET":   testClientSecret,
+		"PSVAULT_MASTER_PASSWORD": testMasterPass,
+	}
+	env := &Env{
+		Stdin:     strings.NewReader(stdin),
+		Stdout:    &stdout,
+		Stderr:    &stderr,
+		Getenv:    func(k string) string { return vars[k] },
+		ConfigDir: t.TempDir(),
+	}
+	code := Main(context.Background(), env, Commands(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestSecretGet(t *testing.T) {
+	server := httptest.NewServer(newFakeServer(t))
+	defer server.Close()
+
+	code, stdout, stderr := runCLI(t, server, "", "secret", "get", "prod db", "--field", "password")
+	assert.Equal(t, 0, code, stderr)
+	assert.Equal(t, "s3cret\n", stdout)
+
+	code, _, stderr = runCLI(t, server, "", "secret", "get", "Prod DB")
+	assert.Equal(t, 0, code, stderr)
+
+	code, _, stderr = runCLI(t, server, "", "secret", "get", "Staging DB", "--vault", "prod")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `no secret titled "Staging DB" in Prod`)
+}
+
+func TestSecretSetCreatesEncryptedSecret(t *testing.T) {
+	fake := newFakeServer(t)
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	code, _, stderr := runCLI(t, server, "line one\nline two\n", "secret", "set", "Deploy key", "--type", "note", "note=-", "--tag", "ci")
+	require.Equal(t, 0, code, stderr)
+	require.Len(t, fake.created, 1)
+	req := fake.created[0]
+	assert.Equal(t, testVaultID, req.VaultID)
+	assert.Equal(t, client.SecretType("note"), req.Type)
+	assert.Equal(t, []string{"ci"}, req.Metadata.Tags)
+
+	plain, err := vaultcrypto.Decrypt(req.EncryptedPayload, fake.vaultKey)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"note":"line one\nline two"}`, string(plain))
+}
+
+func TestRunExplainsStepUp(t *testing.T) {
+	fake := newFakeServer(t)
+	fake.stepUp = true
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	code, _, stderr := runCLI(t, server, "", "run", "--vault", "Prod", "--", "true")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "requires a recent interactive sign-in")
+	assert.Contains(t, stderr, "psvault login --client-id")
+}
+
+func TestLoginRequiresServiceAccount(t *testing.T) {
+	server := httptest.NewServer(newFakeServer(t))
+	defer server.Close()
+
+	// A Clerk session token is refused before it is stored
+	code, _, stderr := runCLI(t, server, "", "login", "--server", server.URL, "--client-id", testClientID, "--client-secret", "eyJhbGciOiJSUzI1NiJ9.session")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "expected a service account client secret")
+
+	code, _, stderr = runCLI(t, server, "", "login", "--server", server.URL, "--client-secret", testClientSecret)
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "expected --client-id")
+
+	code, _, stderr = runCLI(t, server, testClientSecret+"\n", "login", "--server", server.URL, "--client-id", testClientID)
+	assert.Equal(t, 0, code, stderr)
+	assert.Contains(t, stderr, "Logged in to "+server.URL+" (1 vaults)")
+}
+
+func TestSecretEnv(t *testing.T) {
+	fake := newFakeServer(t)
+	keys := newKeyring(&Env{Getenv: func(string) string { return testMasterPass }})
+
+	vars, err := secretEnv(keys, fake.vault, fake.secrets, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PROD_DB_PASSWORD=s3cret", "PROD_DB_USERNAME=app"}, vars)
+
+	vars, err = secretEnv(keys, fake.vault, fake.secrets, []string{"DATABASE_PASSWORD=prod db#password"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DATABASE_PASSWORD=s3cret"}, vars)
+
+	_, err = secretEnv(keys, fake.vault, fake.secrets, []string{"DATABASE=Prod DB"})
+	assert.ErrorContains(t, err, "has fields password, username; pick one")
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "PROD_DB_PASSWORD", envName("Prod DB_password"))
+	assert.Equal(t, "AWS_KEY_API_KEY", envName("  aws-key! _apiKey"))
+	assert.Equal(t, "_1PASSWORD_TOKEN", envName("1password token"))
+}
+
+func TestParseArgsInterleavesFlags(t *testing.T) {
+	fs := NewFlagSet(&Env{Stderr: &bytes.Buffer{}}, "secret get", "")
+	vaultRef := fs.String("vault", "", "")
+	positional, err := ParseArgs(fs, []string{"one", "--vault", "prod", "two"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, positional)
+	assert.Equal(t, "prod", *vaultRef)
+}
+
+func TestMergeCombinesGroups(t *testing.T) {
+	stats := &Command{Name: "stats"}
+	admin := []*Command{{Name: "vault", Subcommands: []*Command{stats}}, {Name: "migrate"}}
+	merged := Merge(Commands(), admin)
+
+	var names []string
+	for _, cmd := range merged {
+		names = append(names, cmd.Name)
+	}
+	assert.Equal(t, []string{"login", "logout", "vault", "secret", "run", "migrate"}, names)
+	assert.Contains(t, merged[2].Subcommands, stats)
+}
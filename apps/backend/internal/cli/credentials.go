@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model/serviceaccount"
+	"github.com/Sameer16536/psvault/pkg/client"
+	"golang.org/x/term"
+)
+
+const (
+	defaultServer   = "http://localhost:8080"
+	credentialsFile = "credentials.json"
+	userAgent       = "psvault-cli"
+)
+
+// credentials is the stored login. The CLI only signs in as a service
+// account: user sessions are short-lived and bulk reads such as psvault run
+// need a recent interactive sign-in, which a terminal cannot provide.
+type credentials struct {
+	Server       string `json:"server"`
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// validate checks that the credentials are a service account's
+func (c *credentials) validate() error {
+	if !strings.HasPrefix(c.ClientSecret, serviceaccount.ClientSecretPrefix) {
+		return fmt.Errorf("expected a service account client secret (%s...); session tokens are not supported", serviceaccount.ClientSecretPrefix)
+	}
+	if c.ClientID == "" {
+		return errors.New("a client secret needs its client ID")
+	}
+	return nil
+}
+
+// loadCredentials reads the stored login, overridden by PSVAULT_URL,
+// PSVAULT_CLIENT_ID and PSVAULT_CLIENT_SECRET
+func (e *Env) loadCredentials() (*credentials, error) {
+	creds := &credentials{}
+	data, err := os.ReadFile(e.credentialsPath())
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, creds); err != nil {
+			return nil, fmt.Errorf("invalid login in %s: %w", e.credentialsPath(), err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+	if v := e.Getenv("PSVAULT_URL"); v != "" {
+		creds.Server = v
+	}
+	if v := e.Getenv("PSVAULT_CLIENT_SECRET"); v != "" {
+		creds.ClientID, creds.ClientSecret = e.Getenv("PSVAULT_CLIENT_ID"), v
+	}
+	if creds.Server == "" {
+		creds.Server = defaultServer
+	}
+	return creds, nil
+}
+
+func (e *Env) saveCredentials(creds *credentials) error {
+	if err := os.MkdirAll(e.ConfigDir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(e.credentialsPath(), data, 0o600)
+}
+
+func (e *Env) credentialsPath() string {
+	return filepath.Join(e.ConfigDir, credentialsFile)
+}
+
+// client returns an API client for the stored login
+func (e *Env) client() (*client.Client, error) {
+	creds, err := e.loadCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if creds.ClientSecret == "" {
+		return nil, errors.New("not logged in; run psvault login or set PSVAULT_CLIENT_ID and PSVAULT_CLIENT_SECRET")
+	}
+	if err := creds.validate(); err != nil {
+		return nil, fmt.Errorf("invalid login: %w", err)
+	}
+	return newClient(creds)
+}
+
+func newClient(creds *credentials) (*client.Client, error) {
+	return client.New(creds.Server,
+		client.WithAuth(client.ClientCredentials(creds.ClientID, creds.ClientSecret)),
+		client.WithHTTPClient(&http.Client{Timeout: 30 * time.Second}),
+		client.WithUserAgent(userAgent),
+	)
+}
+
+// describeError rewrites API errors for the terminal
+func describeError(err error) error {
+	if client.IsReauthenticationRequired(err) {
+		return errors.New("the server requires a recent interactive sign-in for this request, which psvault cannot do; log in with a service account (psvault login --client-id ID)")
+	}
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) && error(apiErr) == err {
+		msg := apiErr.Message
+		if msg == "" {
+			msg = http.StatusText(apiErr.StatusCode)
+		}
+		return fmt.Errorf("%s (HTTP %d)", msg, apiErr.StatusCode)
+	}
+	return err
+}
+
+// readSecret reads a line without echo when stdin is a terminal
+func (e *Env) readSecret(prompt string) (string, error) {
+	if f, ok := e.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprint(e.Stderr, prompt)
+		value, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(e.Stderr)
+		return string(value), err
+	}
+	line, err := e.stdinReader().ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", fmt.Errorf("failed to read %s: %w", strings.TrimSuffix(strings.ToLower(prompt), ": "), err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// stdinReader buffers stdin once so successive reads do not lose input
+func (e *Env) stdinReader() *bufio.Reader {
+	if e.stdin == nil {
+		e.stdin = bufio.NewReader(e.Stdin)
+	}
+	return e.stdin
+}
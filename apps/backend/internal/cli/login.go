@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func loginCommand() *Command {
+	cmd := &Command{
+		Name:    "login",
+		Args:    "--client-id ID [--client-secret SECRET] [--server URL]",
+		Summary: "Store service account credentials",
+	}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, cmd.Name, cmd.Args)
+		server := fs.String("server", "", "server URL (default "+defaultServer+")")
+		clientID := fs.String("client-id", "", "service account client ID")
+		clientSecret := fs.String("client-secret", "", "service account client secret; read from stdin when omitted")
+		positional, err := ParseArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(positional) > 0 {
+			return UsageError(fs, "unexpected argument %q", positional[0])
+		}
+		if *clientID == "" {
+			return UsageError(fs, "expected --client-id; psvault signs in as a service account")
+		}
+
+		creds, err := env.loadCredentials()
+		if err != nil {
+			return err
+		}
+		if *server != "" {
+			creds.Server = *server
+		}
+		creds.ClientID, creds.ClientSecret = *clientID, *clientSecret
+		if creds.ClientSecret == "" {
+			if creds.ClientSecret, err = env.readSecret("Client secret: "); err != nil {
+				return err
+			}
+		}
+		creds.ClientSecret = strings.TrimSpace(creds.ClientSecret)
+		if err := creds.validate(); err != nil {
+			return UsageError(fs, "%v", err)
+		}
+
+		// Check the credentials before storing them
+		c, err := newClient(creds)
+		if err != nil {
+			return err
+		}
+		vaults, err := c.Vaults.List(ctx)
+		if err != nil {
+			return fmt.Errorf("login failed: %w", describeError(err))
+		}
+		if err := env.saveCredentials(creds); err != nil {
+			return fmt.Errorf("failed to store login: %w", err)
+		}
+		fmt.Fprintf(env.Stderr, "Logged in to %s (%d vaults)\n", creds.Server, len(vaults))
+		return nil
+	}
+	return cmd
+}
+
+func logoutCommand() *Command {
+	return &Command{
+		Name:    "logout",
+		Summary: "Remove the stored login",
+		Run: func(ctx context.Context, env *Env, args []string) error {
+			if err := os.Remove(env.credentialsPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			fmt.Fprintln(env.Stderr, "Logged out")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"unicode"
+
+	"github.com/Sameer16536/psvault/pkg/client"
+)
+
+func runCommand() *Command {
+	cmd := &Command{
+		Name:    "run",
+		Args:    "[--vault VAULT] [--env NAME=SECRET[#FIELD]]... -- COMMAND [ARGS]",
+		Summary: "Run a command with secrets in its environment",
+	}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, cmd.Name, cmd.Args)
+		vaultRef := fs.String("vault", "", "vault ID or name")
+		var mappings stringList
+		fs.Var(&mappings, "env", "set NAME to a secret field, repeatable; without it every field of every secret in the vault is set")
+
+		// Everything after -- is the command
+		split := len(args)
+		for i, arg := range args {
+			if arg == "--" {
+				split = i
+				break
+			}
+		}
+		if split == len(args) || split == len(args)-1 {
+			return UsageError(fs, "expected -- COMMAND")
+		}
+		positional, err := ParseArgs(fs, args[:split])
+		if err != nil {
+			return err
+		}
+		if len(positional) > 0 {
+			return UsageError(fs, "unexpected argument %q; put the command after --", positional[0])
+		}
+		command := args[split+1:]
+
+		c, err := env.client()
+		if err != nil {
+			return err
+		}
+		v, err := resolveVault(ctx, env, c, *vaultRef)
+		if err != nil {
+			return err
+		}
+		// Listing a vault's secrets reveals them all at once, which the
+		// server only allows user sessions after a recent sign-in
+		secrets, err := c.Secrets.List(ctx, v.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read the secrets of %s: %w", v.Name, err)
+		}
+		vars, err := secretEnv(newKeyring(env), v, secrets, mappings)
+		if err != nil {
+			return err
+		}
+		return runWithEnv(ctx, env, command, vars)
+	}
+	return cmd
+}
+
+// secretEnv builds the variables psvault run sets. Each mapping is
+// NAME=SECRET[#FIELD], with SECRET an ID or title; without mappings every
+// field becomes TITLE_FIELD, e.g. "Prod DB" {"password"} is PROD_DB_PASSWORD.
+func secretEnv(keys *keyring, v *client.Vault, secrets []*client.Secret, mappings []string) ([]string, error) {
+	var vars []string
+	if len(mappings) == 0 {
+		for _, sec := range secrets {
+			payload, err := decryptPayload(keys, v, sec)
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range fieldNames(payload) {
+				value, err := fieldValue(sec, payload, field)
+				if err != nil {
+					return nil, err
+				}
+				vars = append(vars, envName(sec.Metadata.Title+"_"+field)+"="+value)
+			}
+		}
+		sort.Strings(vars)
+		return vars, nil
+	}
+
+	for _, mapping := range mappings {
+		name, ref, ok := strings.Cut(mapping, "=")
+		if !ok || name == "" || ref == "" {
+			return nil, fmt.Errorf("invalid --env %q: expected NAME=SECRET[#FIELD]", mapping)
+		}
+		ref, field, _ := strings.Cut(ref, "#")
+		var matches []*client.Secret
+		for _, sec := range secrets {
+			if sec.ID == ref || strings.EqualFold(sec.Metadata.Title, ref) {
+				matches = append(matches, sec)
+			}
+		}
+		switch {
+		case len(matches) == 0:
+			return nil, fmt.Errorf("no secret %q in %s", ref, v.Name)
+		case len(matches) > 1:
+			return nil, fmt.Errorf("more than one secret titled %q in %s; use its ID", ref, v.Name)
+		}
+		payload, err := decryptPayload(keys, v, matches[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := fieldValue(matches[0], payload, field)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, name+"="+value)
+	}
+	return vars, nil
+}
+
+// envName turns a title and field into an environment variable name,
+// splitting camelCase field names such as apiKey
+func envName(s string) string {
+	var b strings.Builder
+	underscore, lower := false, false
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if lower && !underscore {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			underscore, lower = false, false
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(unicode.ToUpper(r))
+			underscore, lower = false, r >= 'a'
+		case !underscore && b.Len() > 0:
+			b.WriteByte('_')
+			underscore, lower = true, false
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// runWithEnv runs command with vars added to the environment, forwarding
+// signals to it and passing on its exit status
+func runWithEnv(ctx context.Context, env *Env, command []string, vars []string) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(), vars...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = env.Stdin, env.Stdout, env.Stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+	go func() {
+		for sig := range signals {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			code = 1
+		}
+		return &ExitError{Code: code}
+	}
+	return err
+}
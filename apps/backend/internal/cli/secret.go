@@ -0,0 +1,386 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Sameer16536/psvault/internal/lib/vaultcrypto"
+	"github.com/Sameer16536/psvault/pkg/client"
+	"github.com/google/uuid"
+)
+
+func secretCommand() *Command {
+	return &Command{
+		Name:    "secret",
+		Summary: "Read and write secrets",
+		Subcommands: []*Command{
+			secretGetCommand(),
+			secretSetCommand(),
+			secretRemoveCommand(),
+			secretSearchCommand(),
+		},
+	}
+}
+
+func secretGetCommand() *Command {
+	cmd := &Command{Name: "get", Args: "SECRET [--vault VAULT] [--field NAME]", Summary: "Print a decrypted secret"}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, "secret get", cmd.Args)
+		vaultRef := fs.String("vault", "", "vault ID or name")
+		field := fs.String("field", "", "print only this field")
+		positional, err := ParseArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return UsageError(fs, "expected a secret ID or title")
+		}
+		c, err := env.client()
+		if err != nil {
+			return err
+		}
+		v, sec, err := findSecret(ctx, env, c, *vaultRef, positional[0])
+		if err != nil {
+			return err
+		}
+		payload, err := decryptPayload(newKeyring(env), v, sec)
+		if err != nil {
+			return err
+		}
+		if *field == "" {
+			return printJSON(env, payload)
+		}
+		value, err := fieldValue(sec, payload, *field)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(env.Stdout, value)
+		return nil
+	}
+	return cmd
+}
+
+func secretSetCommand() *Command {
+	cmd := &Command{
+		Name:    "set",
+		Args:    "SECRET FIELD=VALUE... [--vault VAULT] [--type TYPE] [--domain DOMAIN] [--tag TAG]...",
+		Summary: "Create a secret or update its fields (VALUE - reads stdin)",
+	}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, "secret set", cmd.Args)
+		vaultRef := fs.String("vault", "", "vault ID or name")
+		secretType := fs.String("type", "password", "type of a new secret")
+		domain := fs.String("domain", "", "domain shown with the secret")
+		var tags stringList
+		fs.Var(&tags, "tag", "tag, repeatable; replaces the secret's tags")
+		positional, err := ParseArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(positional) < 2 {
+			return UsageError(fs, "expected a secret and at least one FIELD=VALUE")
+		}
+		fields, err := parseFields(env, positional[1:])
+		if err != nil {
+			return UsageError(fs, "%v", err)
+		}
+		c, err := env.client()
+		if err != nil {
+			return err
+		}
+		v, existing, err := findSecret(ctx, env, c, *vaultRef, positional[0])
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		keys := newKeyring(env)
+
+		if existing == nil {
+			if _, err := uuid.Parse(positional[0]); err == nil {
+				return fmt.Errorf("secret %s not found", positional[0])
+			}
+			if v == nil {
+				// The vault itself was not found
+				return err
+			}
+			meta := client.SecretMetadata{Title: positional[0], Tags: tags}
+			if *domain != "" {
+				meta.Domain = domain
+			}
+			payload, err := encryptPayload(keys, v, fields)
+			if err != nil {
+				return err
+			}
+			created, err := c.Secrets.Create(ctx, &client.CreateSecretRequest{
+				VaultID:           v.ID,
+				Type:              client.SecretType(*secretType),
+				EncryptedPayload:  payload,
+				EncryptionVersion: encryptionVersion(v),
+				Metadata:          meta,
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(env.Stderr, "Created secret %s in %s\n", created.Metadata.Title, v.Name)
+			fmt.Fprintln(env.Stdout, created.ID)
+			return nil
+		}
+
+		// Fields not given keep their values
+		current, err := decryptPayload(keys, v, existing)
+		if err != nil {
+			return err
+		}
+		for name, value := range fields {
+			current[name] = value
+		}
+		payload, err := encryptPayload(keys, v, current)
+		if err != nil {
+			return err
+		}
+		version := encryptionVersion(v)
+		req := &client.UpdateSecretRequest{EncryptedPayload: &payload, EncryptionVersion: &version}
+		if *domain != "" || tags != nil {
+			meta := existing.Metadata
+			if *domain != "" {
+				meta.Domain = domain
+			}
+			if tags != nil {
+				meta.Tags = tags
+			}
+			req.Metadata = &meta
+		}
+		if _, err := c.Secrets.Update(ctx, existing.ID, req); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.Stderr, "Updated secret %s in %s\n", existing.Metadata.Title, v.Name)
+		fmt.Fprintln(env.Stdout, existing.ID)
+		return nil
+	}
+	return cmd
+}
+
+func secretRemoveCommand() *Command {
+	cmd := &Command{Name: "rm", Args: "SECRET [--vault VAULT]", Summary: "Delete a secret"}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, "secret rm", cmd.Args)
+		vaultRef := fs.String("vault", "", "vault ID or name")
+		positional, err := ParseArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return UsageError(fs, "expected a secret ID or title")
+		}
+		c, err := env.client()
+		if err != nil {
+			return err
+		}
+		_, sec, err := findSecret(ctx, env, c, *vaultRef, positional[0])
+		if err != nil {
+			return err
+		}
+		if err := c.Secrets.Delete(ctx, sec.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(env.Stderr, "Deleted secret %s (%s)\n", sec.Metadata.Title, sec.ID)
+		return nil
+	}
+	return cmd
+}
+
+func secretSearchCommand() *Command {
+	cmd := &Command{Name: "search", Args: "[QUERY] [--vault VAULT] [--json]", Summary: "Search secrets without decrypting them"}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, "secret search", cmd.Args)
+		vaultRef := fs.String("vault", "", "vault ID or name")
+		asJSON := fs.Bool("json", false, "print JSON")
+		positional, err := ParseArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		c, err := env.client()
+		if err != nil {
+			return err
+		}
+		params := &client.SearchSecretsParams{Q: strings.Join(positional, " ")}
+		if *vaultRef != "" {
+			v, err := resolveVault(ctx, env, c, *vaultRef)
+			if err != nil {
+				return err
+			}
+			params.VaultID = v.ID
+		}
+		results, err := c.Secrets.Search(ctx, params)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			for _, r := range results {
+				r.EncryptedPayload = nil
+			}
+			return printJSON(env, results)
+		}
+		w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTITLE\tTYPE\tSTATUS\tVAULT")
+		for _, r := range results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.ID, r.Metadata.Title, r.Type, r.Status, r.VaultID)
+		}
+		return w.Flush()
+	}
+	return cmd
+}
+
+// findSecret resolves a secret by ID, or by title within the vault vaultRef.
+// The vault is returned even when no secret has the title.
+func findSecret(ctx context.Context, env *Env, c *client.Client, vaultRef, ref string) (*client.Vault, *client.Secret, error) {
+	if _, err := uuid.Parse(ref); err == nil {
+		sec, err := c.Secrets.Get(ctx, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		v, err := resolveVault(ctx, env, c, sec.VaultID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return v, sec, nil
+	}
+	v, err := resolveVault(ctx, env, c, vaultRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := c.Secrets.Search(ctx, &client.SearchSecretsParams{VaultID: v.ID, Title: ref})
+	if err != nil {
+		return nil, nil, err
+	}
+	var matches []*client.Secret
+	for _, r := range results {
+		if strings.EqualFold(r.Metadata.Title, ref) {
+			matches = append(matches, r)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return v, nil, &noSecretError{title: ref, vault: v.Name}
+	case 1:
+		return v, matches[0], nil
+	default:
+		return v, nil, fmt.Errorf("more than one secret titled %q in %s; use its ID", ref, v.Name)
+	}
+}
+
+// noSecretError reports that no secret in a vault has a title
+type noSecretError struct {
+	title string
+	vault string
+}
+
+func (e *noSecretError) Error() string {
+	return fmt.Sprintf("no secret titled %q in %s", e.title, e.vault)
+}
+
+func isNotFound(err error) bool {
+	var noSecret *noSecretError
+	return errors.As(err, &noSecret) || client.IsNotFound(err)
+}
+
+func decryptPayload(keys *keyring, v *client.Vault, sec *client.Secret) (map[string]any, error) {
+	key, err := keys.key(v)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := vaultcrypto.Decrypt(sec.EncryptedPayload, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret %s: %w", sec.Metadata.Title, err)
+	}
+	payload := make(map[string]any)
+	if err := json.Unmarshal(plain, &payload); err != nil {
+		return nil, fmt.Errorf("secret %s does not hold a JSON object: %w", sec.Metadata.Title, err)
+	}
+	return payload, nil
+}
+
+func encryptPayload(keys *keyring, v *client.Vault, fields map[string]any) ([]byte, error) {
+	key, err := keys.key(v)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	return vaultcrypto.Encrypt(plain, key)
+}
+
+// fieldValue returns one field of a payload as text. Without a name, the
+// payload must have exactly one field.
+func fieldValue(sec *client.Secret, payload map[string]any, name string) (string, error) {
+	if name == "" {
+		if len(payload) != 1 {
+			return "", fmt.Errorf("secret %s has fields %s; pick one", sec.Metadata.Title, strings.Join(fieldNames(payload), ", "))
+		}
+		for n := range payload {
+			name = n
+		}
+	}
+	value, ok := payload[name]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no field %q (fields: %s)", sec.Metadata.Title, name, strings.Join(fieldNames(payload), ", "))
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+func fieldNames(payload map[string]any) []string {
+	names := make([]string, 0, len(payload))
+	for n := range payload {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseFields parses FIELD=VALUE arguments; a VALUE of - is read from stdin
+func parseFields(env *Env, args []string) (map[string]any, error) {
+	fields := make(map[string]any, len(args))
+	readStdin := false
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected FIELD=VALUE, got %q", arg)
+		}
+		if value == "-" {
+			if readStdin {
+				return nil, errors.New("only one field can be read from stdin")
+			}
+			readStdin = true
+			data, err := io.ReadAll(env.stdinReader())
+			if err != nil {
+				return nil, err
+			}
+			value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
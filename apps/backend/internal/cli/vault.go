@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Sameer16536/psvault/internal/lib/vaultcrypto"
+	"github.com/Sameer16536/psvault/pkg/client"
+	"github.com/google/uuid"
+)
+
+func vaultCommand() *Command {
+	return &Command{
+		Name:    "vault",
+		Summary: "List and create vaults",
+		Subcommands: []*Command{
+			vaultListCommand(),
+			vaultCreateCommand(),
+		},
+	}
+}
+
+func vaultListCommand() *Command {
+	cmd := &Command{Name: "ls", Args: "[--json]", Summary: "List the vaults you can access"}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, "vault ls", cmd.Args)
+		asJSON := fs.Bool("json", false, "print JSON")
+		if _, err := ParseArgs(fs, args); err != nil {
+			return err
+		}
+		c, err := env.client()
+		if err != nil {
+			return err
+		}
+		vaults, err := c.Vaults.List(ctx)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(env, vaults)
+		}
+		w := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tDESCRIPTION")
+		for _, v := range vaults {
+			description := ""
+			if v.Description != nil {
+				description = *v.Description
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", v.ID, v.Name, description)
+		}
+		return w.Flush()
+	}
+	return cmd
+}
+
+func vaultCreateCommand() *Command {
+	cmd := &Command{Name: "create", Args: "NAME [--description TEXT]", Summary: "Create a vault protected by a new master password"}
+	cmd.Run = func(ctx context.Context, env *Env, args []string) error {
+		fs := NewFlagSet(env, "vault create", cmd.Args)
+		description := fs.String("description", "", "vault description")
+		positional, err := ParseArgs(fs, args)
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return UsageError(fs, "expected a vault name")
+		}
+		c, err := env.client()
+		if err != nil {
+			return err
+		}
+
+		password, err := env.newMasterPassword(positional[0])
+		if err != nil {
+			return err
+		}
+		vaultKey, err := vaultcrypto.GenerateVaultKey()
+		if err != nil {
+			return err
+		}
+		encryptedKey, err := vaultcrypto.EncryptVaultKey(vaultKey, password)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt vault key: %w", err)
+		}
+		req := &client.CreateVaultRequest{Name: positional[0], EncryptedKey: encryptedKey}
+		if *description != "" {
+			req.Description = description
+		}
+		created, err := c.Vaults.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(env.Stdout, created.ID)
+		return nil
+	}
+	return cmd
+}
+
+// resolveVault finds a vault by ID or name. An empty ref falls back to
+// PSVAULT_VAULT, then to the only vault the user can access.
+func resolveVault(ctx context.Context, env *Env, c *client.Client, ref string) (*client.Vault, error) {
+	if ref == "" {
+		ref = env.Getenv("PSVAULT_VAULT")
+	}
+	if _, err := uuid.Parse(ref); err == nil {
+		return c.Vaults.Get(ctx, ref)
+	}
+	vaults, err := c.Vaults.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		if len(vaults) == 1 {
+			return vaults[0], nil
+		}
+		return nil, errors.New("more than one vault: pass --vault or set PSVAULT_VAULT")
+	}
+	var matches []*client.Vault
+	for _, v := range vaults {
+		if strings.EqualFold(v.Name, ref) {
+			matches = append(matches, v)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no vault named %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("more than one vault named %q; use its ID", ref)
+	}
+}
+
+// keyring unlocks vault keys, asking for each vault's master password once
+type keyring struct {
+	env  *Env
+	keys map[string][]byte
+}
+
+func newKeyring(env *Env) *keyring {
+	return &keyring{env: env, keys: make(map[string][]byte)}
+}
+
+// key returns the decrypted key of v. The master password comes from
+// PSVAULT_MASTER_PASSWORD or a prompt.
+func (k *keyring) key(v *client.Vault) ([]byte, error) {
+	if key, ok := k.keys[v.ID]; ok {
+		return key, nil
+	}
+	if len(v.EncryptedKey) == 0 {
+		return nil, fmt.Errorf("vault %q has no encryption key", v.Name)
+	}
+	password := k.env.Getenv("PSVAULT_MASTER_PASSWORD")
+	if password == "" {
+		var err error
+		if password, err = k.env.readSecret(fmt.Sprintf("Master password for %s: ", v.Name)); err != nil {
+			return nil, err
+		}
+	}
+	key, err := vaultcrypto.DecryptVaultKey(v.EncryptedKey, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock vault %q: %w", v.Name, err)
+	}
+	k.keys[v.ID] = key
+	return key, nil
+}
+
+// encryptionVersion is the version new payloads of v are encrypted with
+func encryptionVersion(v *client.Vault) int {
+	if v.KeyEncryptionVersion != nil {
+		return *v.KeyEncryptionVersion
+	}
+	return 1
+}
+
+// newMasterPassword reads a new master password, confirming it when prompted
+func (e *Env) newMasterPassword(vaultName string) (string, error) {
+	if password := e.Getenv("PSVAULT_MASTER_PASSWORD"); password != "" {
+		return password, nil
+	}
+	password, err := e.readSecret(fmt.Sprintf("New master password for %s: ", vaultName))
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("the master password cannot be empty")
+	}
+	confirm, err := e.readSecret("Repeat master password: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm != password {
+		return "", errors.New("the master passwords do not match")
+	}
+	return password, nil
+}
+
+func printJSON(env *Env, v any) error {
+	enc := json.NewEncoder(env.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
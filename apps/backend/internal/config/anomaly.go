@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// AnomalyResponse is what the detector does when a rule fires
+type AnomalyResponse string
+
+const (
+	AnomalyResponseLog    AnomalyResponse = "log"
+	AnomalyResponseNotify AnomalyResponse = "notify"
+	AnomalyResponseReauth AnomalyResponse = "reauth"
+	AnomalyResponseLock   AnomalyResponse = "lock"
+)
+
+// AnomalyConfig tunes the secret-access anomaly detector.
+// Each rule has its own response; stronger responses also log.
+type AnomalyConfig struct {
+	Disabled bool `koanf:"disabled"`
+
+	// Reveal volume: more than RevealThreshold secret reveals within RevealWindow
+	RevealThreshold int             `koanf:"reveal_threshold"`
+	RevealWindow    time.Duration   `koanf:"reveal_window"`
+	RevealResponse  AnomalyResponse `koanf:"reveal_response"`
+
+	// New client: an IP/user-agent combination not seen for this principal before
+	NewClientResponse AnomalyResponse `koanf:"new_client_response"`
+
+	// Unusual hour: activity in a UTC hour holding less than HourMinShare of
+	// the principal's history, once HourMinSamples events have been seen
+	HourMinSamples int             `koanf:"hour_min_samples"`
+	HourMinShare   float64         `koanf:"hour_min_share"`
+	HourResponse   AnomalyResponse `koanf:"hour_response"`
+
+	// LockDuration is how long a lock response blocks the principal
+	LockDuration time.Duration `koanf:"lock_duration"`
+	// AlertCooldown suppresses repeat responses for the same rule and principal
+	AlertCooldown time.Duration `koanf:"alert_cooldown"`
+	// HistoryRetention is how long known clients and hour histograms are kept
+	HistoryRetention time.Duration `koanf:"history_retention"`
+}
+
+func DefaultAnomalyConfig() *AnomalyConfig {
+	return &AnomalyConfig{
+		RevealThreshold:   50,
+		RevealWindow:      5 * time.Minute,
+		RevealResponse:    AnomalyResponseLock,
+		NewClientResponse: AnomalyResponseNotify,
+		HourMinSamples:    200,
+		HourMinShare:      0.01,
+		HourResponse:      AnomalyResponseLog,
+		LockDuration:      30 * time.Minute,
+		AlertCooldown:     time.Hour,
+		HistoryRetention:  90 * 24 * time.Hour,
+	}
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *AnomalyConfig) applyDefaults() {
+	d := DefaultAnomalyConfig()
+	if c.RevealThreshold == 0 {
+		c.RevealThreshold = d.RevealThreshold
+	}
+	if c.RevealWindow == 0 {
+		c.RevealWindow = d.RevealWindow
+	}
+	if c.RevealResponse == "" {
+		c.RevealResponse = d.RevealResponse
+	}
+	if c.NewClientResponse == "" {
+		c.NewClientResponse = d.NewClientResponse
+	}
+	if c.HourMinSamples == 0 {
+		c.HourMinSamples = d.HourMinSamples
+	}
+	if c.HourMinShare == 0 {
+		c.HourMinShare = d.HourMinShare
+	}
+	if c.HourResponse == "" {
+		c.HourResponse = d.HourResponse
+	}
+	if c.LockDuration == 0 {
+		c.LockDuration = d.LockDuration
+	}
+	if c.AlertCooldown == 0 {
+		c.AlertCooldown = d.AlertCooldown
+	}
+	if c.HistoryRetention == 0 {
+		c.HistoryRetention = d.HistoryRetention
+	}
+}
+
+func (c *AnomalyConfig) Validate() error {
+	for name, r := range map[string]AnomalyResponse{
+		"reveal_response":     c.RevealResponse,
+		"new_client_response": c.NewClientResponse,
+		"hour_response":       c.HourResponse,
+	} {
+		switch r {
+		case AnomalyResponseLog, AnomalyResponseNotify, AnomalyResponseReauth, AnomalyResponseLock:
+		default:
+			return fmt.Errorf("invalid anomaly %s: %s (must be one of: log, notify, reauth, lock)", name, r)
+		}
+	}
+	if c.RevealThreshold < 1 {
+		return fmt.Errorf("anomaly reveal_threshold must be positive")
+	}
+	if c.HourMinShare < 0 || c.HourMinShare >= 1 {
+		return fmt.Errorf("anomaly hour_min_share must be in [0, 1)")
+	}
+	return nil
+}
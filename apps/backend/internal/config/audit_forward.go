@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// AuditForwardConfig sets up continuous forwarding of the audit log to a
+// SIEM. Forwarding is off unless a sink is selected.
+type AuditForwardConfig struct {
+	// Sink is "syslog" or "http"; empty disables forwarding
+	Sink   string              `koanf:"sink"`
+	Syslog SyslogForwardConfig `koanf:"syslog"`
+	HTTP   HTTPForwardConfig   `koanf:"http"`
+	// Checkpoint names the position kept in the database; a new name forwards
+	// the whole log again from the start
+	Checkpoint string `koanf:"checkpoint"`
+	// Interval is how often new entries are forwarded
+	Interval time.Duration `koanf:"interval"`
+	// BatchSize is how many entries are sent at a time
+	BatchSize int `koanf:"batch_size"`
+	// Delay holds back entries younger than this, so entries of transactions
+	// still open when the forwarder runs are not passed over
+	Delay time.Duration `koanf:"delay"`
+	// Timeout bounds each batch sent
+	Timeout time.Duration `koanf:"timeout"`
+}
+
+// SyslogForwardConfig addresses an RFC 5424 syslog collector
+type SyslogForwardConfig struct {
+	// Network is "udp", "tcp" or "tls"; UDP gives no delivery guarantee
+	Network string `koanf:"network"`
+	// Address is the collector's host:port
+	Address string `koanf:"address"`
+	// Facility is the syslog facility code, 13 (log audit) by default
+	Facility int `koanf:"facility"`
+	// Hostname is reported as the message origin, the machine's name by default
+	Hostname string `koanf:"hostname"`
+}
+
+// HTTPForwardConfig addresses a collector accepting batches of NDJSON over HTTP
+type HTTPForwardConfig struct {
+	URL string `koanf:"url"`
+	// Authorization is sent verbatim in the Authorization header, e.g. "Bearer <token>"
+	Authorization string `koanf:"authorization"`
+}
+
+func DefaultAuditForwardConfig() *AuditForwardConfig {
+	return &AuditForwardConfig{
+		Syslog: SyslogForwardConfig{
+			Network:  "udp",
+			Facility: 13,
+		},
+		Interval:  10 * time.Second,
+		BatchSize: 500,
+		Delay:     30 * time.Second,
+		Timeout:   10 * time.Second,
+	}
+}
+
+// Enabled reports whether a sink is selected
+func (c *AuditForwardConfig) Enabled() bool {
+	return c.Sink != ""
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *AuditForwardConfig) applyDefaults() {
+	d := DefaultAuditForwardConfig()
+	if c.Syslog.Network == "" {
+		c.Syslog.Network = d.Syslog.Network
+	}
+	if c.Syslog.Facility == 0 {
+		c.Syslog.Facility = d.Syslog.Facility
+	}
+	if c.Checkpoint == "" {
+		c.Checkpoint = c.Sink
+	}
+	if c.Interval == 0 {
+		c.Interval = d.Interval
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = d.BatchSize
+	}
+	if c.Delay == 0 {
+		c.Delay = d.Delay
+	}
+	if c.Timeout == 0 {
+		c.Timeout = d.Timeout
+	}
+}
+
+func (c *AuditForwardConfig) Validate() error {
+	switch c.Sink {
+	case "":
+		return nil
+	case "syslog":
+		switch c.Syslog.Network {
+		case "udp", "tcp", "tls":
+		default:
+			return fmt.Errorf("invalid audit_forward syslog.network: %s (must be one of: udp, tcp, tls)", c.Syslog.Network)
+		}
+		if c.Syslog.Address == "" {
+			return fmt.Errorf("audit_forward syslog.address is required for the syslog sink")
+		}
+		if c.Syslog.Facility < 1 || c.Syslog.Facility > 23 {
+			return fmt.Errorf("audit_forward syslog.facility must be between 1 and 23")
+		}
+	case "http":
+		u, err := url.Parse(c.HTTP.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("audit_forward http.url must be an http or https URL")
+		}
+	default:
+		return fmt.Errorf("invalid audit_forward sink: %s (must be one of: syslog, http)", c.Sink)
+	}
+	if c.Interval < time.Second {
+		return fmt.Errorf("audit_forward interval must be at least 1s")
+	}
+	if c.BatchSize < 1 || c.BatchSize > 5000 {
+		return fmt.Errorf("audit_forward batch_size must be between 1 and 5000")
+	}
+	if c.Delay < 0 {
+		return fmt.Errorf("audit_forward delay must not be negative")
+	}
+	if c.Timeout < time.Second || c.Timeout > time.Minute {
+		return fmt.Errorf("audit_forward timeout must be between 1s and 1m")
+	}
+	return nil
+}
@@ -19,6 +19,15 @@ type Config struct {
 	Redis         RedisConfig          `koanf:"redis" validate:"required"`
 	Integration   IntegrationConfig    `koanf:"integration" validate:"required"`
 	Observability *ObservabilityConfig `koanf:"observability"`
+	RateLimit     *RateLimitConfig     `koanf:"rate_limit"`
+	Anomaly       *AnomalyConfig       `koanf:"anomaly"`
+	StepUp        *StepUpConfig        `koanf:"step_up"`
+	Storage       *StorageConfig       `koanf:"storage"`
+	Health        *HealthConfig        `koanf:"health"`
+	Expiry        *ExpiryConfig        `koanf:"expiry"`
+	Outbox        *OutboxConfig        `koanf:"outbox"`
+	Webhook       *WebhookConfig       `koanf:"webhook"`
+	AuditForward  *AuditForwardConfig  `koanf:"audit_forward"`
 }
 
 type Primary struct {
@@ -97,5 +106,95 @@ func LoadConfig() (*Config, error) {
 		logger.Fatal().Err(err).Msg("invalid observability config")
 	}
 
+	// Set default rate limit config if not provided
+	if mainConfig.RateLimit == nil {
+		mainConfig.RateLimit = DefaultRateLimitConfig()
+	}
+	mainConfig.RateLimit.applyDefaults()
+
+	if err := mainConfig.RateLimit.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid rate limit config")
+	}
+
+	// Set default anomaly detection config if not provided
+	if mainConfig.Anomaly == nil {
+		mainConfig.Anomaly = DefaultAnomalyConfig()
+	}
+	mainConfig.Anomaly.applyDefaults()
+
+	if err := mainConfig.Anomaly.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid anomaly detection config")
+	}
+
+	// Set default step-up authentication config if not provided
+	if mainConfig.StepUp == nil {
+		mainConfig.StepUp = DefaultStepUpConfig()
+	}
+	mainConfig.StepUp.applyDefaults()
+
+	if err := mainConfig.StepUp.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid step-up authentication config")
+	}
+
+	// Set default attachment storage config if not provided
+	if mainConfig.Storage == nil {
+		mainConfig.Storage = DefaultStorageConfig()
+	}
+	mainConfig.Storage.applyDefaults()
+
+	if err := mainConfig.Storage.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid storage config")
+	}
+
+	// Set default password health policy if not provided
+	if mainConfig.Health == nil {
+		mainConfig.Health = DefaultHealthConfig()
+	}
+	mainConfig.Health.applyDefaults()
+
+	if err := mainConfig.Health.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid health config")
+	}
+
+	// Set default secret expiry config if not provided
+	if mainConfig.Expiry == nil {
+		mainConfig.Expiry = DefaultExpiryConfig()
+	}
+	mainConfig.Expiry.applyDefaults()
+
+	if err := mainConfig.Expiry.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid expiry config")
+	}
+
+	// Set default outbox relay config if not provided
+	if mainConfig.Outbox == nil {
+		mainConfig.Outbox = DefaultOutboxConfig()
+	}
+	mainConfig.Outbox.applyDefaults()
+
+	if err := mainConfig.Outbox.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid outbox config")
+	}
+
+	// Set default webhook delivery config if not provided
+	if mainConfig.Webhook == nil {
+		mainConfig.Webhook = DefaultWebhookConfig()
+	}
+	mainConfig.Webhook.applyDefaults()
+
+	if err := mainConfig.Webhook.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid webhook config")
+	}
+
+	// Set default audit forwarding config if not provided (forwarding stays off)
+	if mainConfig.AuditForward == nil {
+		mainConfig.AuditForward = DefaultAuditForwardConfig()
+	}
+	mainConfig.AuditForward.applyDefaults()
+
+	if err := mainConfig.AuditForward.Validate(); err != nil {
+		logger.Fatal().Err(err).Msg("invalid audit forward config")
+	}
+
 	return mainConfig, nil
 }
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpiryConfig tunes secret expiry status and the daily reminder digest
+type ExpiryConfig struct {
+	// WarningWindow is how far ahead an expiring secret is reported
+	WarningWindow time.Duration `koanf:"warning_window"`
+	// DigestSchedule is the cron spec (UTC) of the reminder digest email
+	DigestSchedule string `koanf:"digest_schedule"`
+	DigestDisabled bool   `koanf:"digest_disabled"`
+}
+
+func DefaultExpiryConfig() *ExpiryConfig {
+	return &ExpiryConfig{
+		WarningWindow:  30 * 24 * time.Hour,
+		DigestSchedule: "0 8 * * *",
+	}
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *ExpiryConfig) applyDefaults() {
+	d := DefaultExpiryConfig()
+	if c.WarningWindow == 0 {
+		c.WarningWindow = d.WarningWindow
+	}
+	if c.DigestSchedule == "" {
+		c.DigestSchedule = d.DigestSchedule
+	}
+}
+
+func (c *ExpiryConfig) Validate() error {
+	if c.WarningWindow < 24*time.Hour {
+		return fmt.Errorf("expiry warning_window must be at least 24h")
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// HealthConfig sets the policy used by vault password health reports
+type HealthConfig struct {
+	// RotationWindow is how long a secret may go without rotation before it is reported as old
+	RotationWindow time.Duration `koanf:"rotation_window"`
+	// WeakScore is the strength score (1-4) below which a secret is reported as weak
+	WeakScore int `koanf:"weak_score"`
+}
+
+func DefaultHealthConfig() *HealthConfig {
+	return &HealthConfig{
+		RotationWindow: 180 * 24 * time.Hour,
+		WeakScore:      3,
+	}
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *HealthConfig) applyDefaults() {
+	d := DefaultHealthConfig()
+	if c.RotationWindow == 0 {
+		c.RotationWindow = d.RotationWindow
+	}
+	if c.WeakScore == 0 {
+		c.WeakScore = d.WeakScore
+	}
+}
+
+func (c *HealthConfig) Validate() error {
+	if c.RotationWindow < 24*time.Hour {
+		return fmt.Errorf("health rotation_window must be at least 24h")
+	}
+	if c.WeakScore < 1 || c.WeakScore > 4 {
+		return fmt.Errorf("health weak_score must be between 1 and 4")
+	}
+	return nil
+}
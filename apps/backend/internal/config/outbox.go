@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// OutboxConfig tunes the relay publishing the transactional outbox
+type OutboxConfig struct {
+	// RelayInterval is how often the relay runs, and so roughly how long a
+	// message waits before it is published
+	RelayInterval time.Duration `koanf:"relay_interval"`
+	// BatchSize is how many messages the relay claims at a time
+	BatchSize int `koanf:"batch_size"`
+	// MaxAttempts is how often a message is tried before the relay gives up on it
+	MaxAttempts int `koanf:"max_attempts"`
+	// Retention is how long published messages are kept
+	Retention time.Duration `koanf:"retention"`
+}
+
+func DefaultOutboxConfig() *OutboxConfig {
+	return &OutboxConfig{
+		RelayInterval: 5 * time.Second,
+		BatchSize:     100,
+		MaxAttempts:   10,
+		Retention:     72 * time.Hour,
+	}
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *OutboxConfig) applyDefaults() {
+	d := DefaultOutboxConfig()
+	if c.RelayInterval == 0 {
+		c.RelayInterval = d.RelayInterval
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = d.BatchSize
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = d.MaxAttempts
+	}
+	if c.Retention == 0 {
+		c.Retention = d.Retention
+	}
+}
+
+func (c *OutboxConfig) Validate() error {
+	if c.RelayInterval < time.Second {
+		return fmt.Errorf("outbox relay_interval must be at least 1s")
+	}
+	if c.BatchSize < 1 || c.BatchSize > 1000 {
+		return fmt.Errorf("outbox batch_size must be between 1 and 1000")
+	}
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("outbox max_attempts must be at least 1")
+	}
+	if c.Retention < time.Hour {
+		return fmt.Errorf("outbox retention must be at least 1h")
+	}
+	return nil
+}
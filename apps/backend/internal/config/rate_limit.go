@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitConfig holds the limiter policies applied by route group.
+// Authenticated groups are limited per user, everything else per client IP.
+type RateLimitConfig struct {
+	// Disabled turns all limiting off, e.g. for local load testing
+	Disabled bool `koanf:"disabled"`
+	// Global applies to every request before authentication, keyed by IP
+	Global RateLimitPolicy `koanf:"global"`
+	// API applies to authenticated route groups, keyed by user
+	API RateLimitPolicy `koanf:"api"`
+	// Auth applies to unauthenticated credential exchanges, keyed by IP
+	Auth RateLimitPolicy `koanf:"auth"`
+	// ShareView applies to opening a public share link, keyed by IP
+	ShareView RateLimitPolicy `koanf:"share_view"`
+	// SecretReveal applies to reading a single decrypted-payload secret
+	SecretReveal RateLimitPolicy `koanf:"secret_reveal"`
+	// Search applies to secret search
+	Search RateLimitPolicy `koanf:"search"`
+}
+
+// RateLimitPolicy allows Requests per Window
+type RateLimitPolicy struct {
+	Requests int           `koanf:"requests"`
+	Window   time.Duration `koanf:"window"`
+}
+
+func DefaultRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		Global:       RateLimitPolicy{Requests: 1200, Window: time.Minute},
+		API:          RateLimitPolicy{Requests: 300, Window: time.Minute},
+		Auth:         RateLimitPolicy{Requests: 10, Window: time.Minute},
+		ShareView:    RateLimitPolicy{Requests: 30, Window: time.Minute},
+		SecretReveal: RateLimitPolicy{Requests: 60, Window: time.Minute},
+		Search:       RateLimitPolicy{Requests: 30, Window: time.Minute},
+	}
+}
+
+// applyDefaults fills policies left unset in the environment from the defaults
+func (c *RateLimitConfig) applyDefaults() {
+	d := DefaultRateLimitConfig()
+	for _, p := range []struct{ cur, def *RateLimitPolicy }{
+		{&c.Global, &d.Global},
+		{&c.API, &d.API},
+		{&c.Auth, &d.Auth},
+		{&c.ShareView, &d.ShareView},
+		{&c.SecretReveal, &d.SecretReveal},
+		{&c.Search, &d.Search},
+	} {
+		if p.cur.Requests == 0 {
+			p.cur.Requests = p.def.Requests
+		}
+		if p.cur.Window == 0 {
+			p.cur.Window = p.def.Window
+		}
+	}
+}
+
+func (c *RateLimitConfig) Validate() error {
+	for name, p := range map[string]RateLimitPolicy{
+		"global":        c.Global,
+		"api":           c.API,
+		"auth":          c.Auth,
+		"share_view":    c.ShareView,
+		"secret_reveal": c.SecretReveal,
+		"search":        c.Search,
+	} {
+		if p.Requests < 1 {
+			return fmt.Errorf("rate_limit.%s.requests must be positive", name)
+		}
+		if p.Window < time.Second {
+			return fmt.Errorf("rate_limit.%s.window must be at least 1s", name)
+		}
+	}
+	return nil
+}
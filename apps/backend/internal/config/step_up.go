@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// StepUpConfig sets how recently a user must have verified their identity
+// before sensitive routes are allowed.
+type StepUpConfig struct {
+	Disabled bool `koanf:"disabled"`
+	// Level is the Clerk verification level required: first_factor, second_factor or multi_factor.
+	// second_factor shuts users without a second factor out of sensitive routes;
+	// multi_factor lets them through on their first factor.
+	Level string `koanf:"level"`
+	// SensitiveMaxAge applies to destructive and credential-granting operations
+	SensitiveMaxAge time.Duration `koanf:"sensitive_max_age"`
+	// BulkRevealMaxAge applies to operations that return many encrypted secrets at once
+	BulkRevealMaxAge time.Duration `koanf:"bulk_reveal_max_age"`
+}
+
+func DefaultStepUpConfig() *StepUpConfig {
+	return &StepUpConfig{
+		Level:            "second_factor",
+		SensitiveMaxAge:  10 * time.Minute,
+		BulkRevealMaxAge: 12 * time.Hour,
+	}
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *StepUpConfig) applyDefaults() {
+	d := DefaultStepUpConfig()
+	if c.Level == "" {
+		c.Level = d.Level
+	}
+	if c.SensitiveMaxAge == 0 {
+		c.SensitiveMaxAge = d.SensitiveMaxAge
+	}
+	if c.BulkRevealMaxAge == 0 {
+		c.BulkRevealMaxAge = d.BulkRevealMaxAge
+	}
+}
+
+func (c *StepUpConfig) Validate() error {
+	switch c.Level {
+	case "first_factor", "second_factor", "multi_factor":
+	default:
+		return fmt.Errorf("invalid step_up level: %s (must be one of: first_factor, second_factor, multi_factor)", c.Level)
+	}
+	if c.SensitiveMaxAge < time.Minute || c.BulkRevealMaxAge < time.Minute {
+		return fmt.Errorf("step_up max ages must be at least 1m")
+	}
+	return nil
+}
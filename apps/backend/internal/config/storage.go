@@ -0,0 +1,67 @@
+package config
+
+import "fmt"
+
+// StorageConfig selects the blob store used for secret attachments
+type StorageConfig struct {
+	// Driver is "local" or "s3"
+	Driver    string          `koanf:"driver"`
+	LocalPath string          `koanf:"local_path"`
+	S3        S3StorageConfig `koanf:"s3"`
+	// UserQuotaBytes caps the total attachment size charged to one user
+	UserQuotaBytes int64 `koanf:"user_quota_bytes"`
+	// MaxAttachmentBytes caps a single attachment
+	MaxAttachmentBytes int64 `koanf:"max_attachment_bytes"`
+}
+
+// S3StorageConfig configures any S3-compatible endpoint (AWS S3, MinIO, R2, ...)
+type S3StorageConfig struct {
+	Endpoint  string `koanf:"endpoint"`
+	Region    string `koanf:"region"`
+	Bucket    string `koanf:"bucket"`
+	AccessKey string `koanf:"access_key"`
+	SecretKey string `koanf:"secret_key"`
+	UseSSL    bool   `koanf:"use_ssl"`
+}
+
+func DefaultStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Driver:             "local",
+		LocalPath:          "data/attachments",
+		UserQuotaBytes:     100 << 20,
+		MaxAttachmentBytes: 10 << 20,
+	}
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *StorageConfig) applyDefaults() {
+	d := DefaultStorageConfig()
+	if c.Driver == "" {
+		c.Driver = d.Driver
+	}
+	if c.LocalPath == "" {
+		c.LocalPath = d.LocalPath
+	}
+	if c.UserQuotaBytes == 0 {
+		c.UserQuotaBytes = d.UserQuotaBytes
+	}
+	if c.MaxAttachmentBytes == 0 {
+		c.MaxAttachmentBytes = d.MaxAttachmentBytes
+	}
+}
+
+func (c *StorageConfig) Validate() error {
+	switch c.Driver {
+	case "local":
+	case "s3":
+		if c.S3.Endpoint == "" || c.S3.Bucket == "" {
+			return fmt.Errorf("storage s3.endpoint and s3.bucket are required for the s3 driver")
+		}
+	default:
+		return fmt.Errorf("invalid storage driver: %s (must be one of: local, s3)", c.Driver)
+	}
+	if c.MaxAttachmentBytes > c.UserQuotaBytes {
+		return fmt.Errorf("storage max_attachment_bytes must not exceed user_quota_bytes")
+	}
+	return nil
+}
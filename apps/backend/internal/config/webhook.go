@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// WebhookConfig tunes outgoing webhook deliveries
+type WebhookConfig struct {
+	// Timeout bounds each delivery request
+	Timeout time.Duration `koanf:"timeout"`
+	// MaxAttempts is how often a delivery is tried, with exponential backoff, before it fails
+	MaxAttempts int `koanf:"max_attempts"`
+	// DisableAfter is how many failed attempts in a row disable a webhook
+	DisableAfter int `koanf:"disable_after"`
+	// AllowPrivateNetworks lets webhooks reach loopback, private and link-local
+	// addresses; only for development against a local receiver
+	AllowPrivateNetworks bool `koanf:"allow_private_networks"`
+}
+
+func DefaultWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		Timeout:      10 * time.Second,
+		MaxAttempts:  8,
+		DisableAfter: 20,
+	}
+}
+
+// applyDefaults fills settings left unset in the environment from the defaults
+func (c *WebhookConfig) applyDefaults() {
+	d := DefaultWebhookConfig()
+	if c.Timeout == 0 {
+		c.Timeout = d.Timeout
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = d.MaxAttempts
+	}
+	if c.DisableAfter == 0 {
+		c.DisableAfter = d.DisableAfter
+	}
+}
+
+func (c *WebhookConfig) Validate() error {
+	if c.Timeout < time.Second || c.Timeout > time.Minute {
+		return fmt.Errorf("webhook timeout must be between 1s and 1m")
+	}
+	if c.MaxAttempts < 1 || c.MaxAttempts > 25 {
+		return fmt.Errorf("webhook max_attempts must be between 1 and 25")
+	}
+	if c.DisableAfter < 1 {
+		return fmt.Errorf("webhook disable_after must be at least 1")
+	}
+	return nil
+}
@@ -133,4 +133,17 @@ BEFORE UPDATE ON devices
 FOR EACH ROW
 EXECUTE FUNCTION trigger_set_updated_at();
 
-
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS devices;
+DROP TABLE IF EXISTS audit_logs;
+-- Postgres cannot drop single enum values, so the actions later migrations add
+-- with ADD VALUE IF NOT EXISTS are left in place by their down migrations and
+-- only go away with the type here.
+DROP TYPE IF EXISTS audit_action;
+DROP TABLE IF EXISTS secret_metadata;
+DROP TABLE IF EXISTS secrets;
+DROP TYPE IF EXISTS secret_type;
+DROP TABLE IF EXISTS vault_keys;
+DROP TABLE IF EXISTS vaults;
+DROP TABLE IF EXISTS users;
@@ -45,3 +45,41 @@ CREATE INDEX IF NOT EXISTS idx_devices_device_fingerprint ON devices(device_fing
 CREATE INDEX IF NOT EXISTS idx_devices_last_seen_at ON devices(last_seen_at DESC);
 -- Unique constraint for user-device combination
 CREATE UNIQUE INDEX IF NOT EXISTS idx_devices_user_device_unique ON devices(user_id, device_fingerprint);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_devices_user_device_unique;
+DROP INDEX IF EXISTS idx_devices_last_seen_at;
+DROP INDEX IF EXISTS idx_devices_device_fingerprint;
+DROP INDEX IF EXISTS idx_devices_user_id;
+
+DROP INDEX IF EXISTS idx_audit_logs_vault_id_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_user_id_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_action;
+DROP INDEX IF EXISTS idx_audit_logs_secret_id;
+DROP INDEX IF EXISTS idx_audit_logs_vault_id;
+DROP INDEX IF EXISTS idx_audit_logs_user_id;
+
+DROP INDEX IF EXISTS idx_secret_metadata_title_domain;
+DROP INDEX IF EXISTS idx_secret_metadata_tags;
+DROP INDEX IF EXISTS idx_secret_metadata_domain;
+DROP INDEX IF EXISTS idx_secret_metadata_title;
+DROP INDEX IF EXISTS idx_secret_metadata_secret_id;
+
+DROP INDEX IF EXISTS idx_secrets_vault_id_created_at;
+DROP INDEX IF EXISTS idx_secrets_vault_id_type;
+DROP INDEX IF EXISTS idx_secrets_last_accessed_at;
+DROP INDEX IF EXISTS idx_secrets_created_at;
+DROP INDEX IF EXISTS idx_secrets_type;
+DROP INDEX IF EXISTS idx_secrets_vault_id;
+
+DROP INDEX IF EXISTS idx_vault_keys_vault_id;
+
+DROP INDEX IF EXISTS idx_vaults_user_id_created_at;
+DROP INDEX IF EXISTS idx_vaults_created_at;
+DROP INDEX IF EXISTS idx_vaults_user_id;
+
+DROP INDEX IF EXISTS idx_users_last_login_at;
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_external_auth_id;
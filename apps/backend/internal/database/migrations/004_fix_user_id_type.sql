@@ -18,3 +18,17 @@ ALTER TABLE users ADD CONSTRAINT users_external_auth_id_unique UNIQUE(external_a
 -- Optional: If you want to completely remove the users table dependency and use Clerk directly,
 -- you can comment out the users table and use external auth IDs everywhere
 -- For now, we'll keep the users table for potential future use (storing preferences, etc.)
+
+---- create above / drop below ----
+
+-- Only succeeds while every user_id still holds a users.id UUID; rows written
+-- since this migration hold Clerk IDs, which have no UUID to go back to.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_external_auth_id_unique;
+
+ALTER TABLE audit_logs ALTER COLUMN user_id TYPE UUID USING user_id::UUID;
+ALTER TABLE devices ALTER COLUMN user_id TYPE UUID USING user_id::UUID;
+ALTER TABLE vaults ALTER COLUMN user_id TYPE UUID USING user_id::UUID;
+
+ALTER TABLE audit_logs ADD CONSTRAINT audit_logs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER TABLE devices ADD CONSTRAINT devices_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE vaults ADD CONSTRAINT vaults_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
@@ -8,3 +8,11 @@ ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS audit_logs_vault_id_fkey;
 ALTER TABLE audit_logs 
 ADD CONSTRAINT audit_logs_vault_id_fkey 
 FOREIGN KEY (vault_id) REFERENCES vaults(id) ON DELETE CASCADE;
+
+---- create above / drop below ----
+
+ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS audit_logs_vault_id_fkey;
+
+ALTER TABLE audit_logs
+ADD CONSTRAINT audit_logs_vault_id_fkey
+FOREIGN KEY (vault_id) REFERENCES vaults(id);
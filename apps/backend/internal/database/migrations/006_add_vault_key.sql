@@ -7,3 +7,9 @@ ALTER TABLE vaults ADD COLUMN key_encryption_version INTEGER DEFAULT 1;
 
 -- Add index for faster lookups
 CREATE INDEX IF NOT EXISTS idx_vaults_user_id ON vaults(user_id);
+
+---- create above / drop below ----
+
+-- idx_vaults_user_id belongs to 003_indexes.sql
+ALTER TABLE vaults DROP COLUMN IF EXISTS key_encryption_version;
+ALTER TABLE vaults DROP COLUMN IF EXISTS encrypted_key;
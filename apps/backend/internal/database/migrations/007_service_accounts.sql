@@ -0,0 +1,80 @@
+-- Service accounts: non-human principals owned by an organization.
+-- Members authenticate with a client-credentials exchange that issues
+-- short-lived bearer tokens.
+
+CREATE TYPE actor_type AS ENUM (
+    'user',
+    'service_account'
+);
+
+CREATE TABLE service_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    org_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_by TEXT NOT NULL,
+
+    -- SHA-256 of the client secret; the secret itself is only shown once
+    client_secret_hash BYTEA NOT NULL,
+
+    last_used_at TIMESTAMPTZ,
+    disabled_at TIMESTAMPTZ
+);
+
+CREATE TRIGGER set_service_accounts_updated_at
+BEFORE UPDATE ON service_accounts
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_service_accounts_org_id ON service_accounts(org_id);
+
+CREATE TABLE service_account_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    service_account_id UUID NOT NULL REFERENCES service_accounts(id) ON DELETE CASCADE,
+    token_hash BYTEA NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_account_tokens_expires_at ON service_account_tokens(expires_at);
+
+-- Vault membership for principals other than the owner.
+-- encrypted_key is the vault key wrapped for the member by the owner's client.
+CREATE TABLE vault_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    vault_id UUID NOT NULL REFERENCES vaults(id) ON DELETE CASCADE,
+    member_type actor_type NOT NULL,
+    member_id TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('read', 'write')),
+    encrypted_key BYTEA,
+    granted_by TEXT NOT NULL,
+
+    UNIQUE (vault_id, member_type, member_id)
+);
+
+CREATE TRIGGER set_vault_members_updated_at
+BEFORE UPDATE ON vault_members
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_vault_members_member ON vault_members(member_type, member_id);
+
+-- Distinguish human users from service accounts in the audit trail
+ALTER TABLE audit_logs ADD COLUMN actor_type actor_type NOT NULL DEFAULT 'user';
+
+---- create above / drop below ----
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS actor_type;
+
+DROP TABLE IF EXISTS vault_members;
+DROP TABLE IF EXISTS service_account_tokens;
+DROP TABLE IF EXISTS service_accounts;
+-- Also drops the actor types later migrations add (see 002)
+DROP TYPE IF EXISTS actor_type;
@@ -0,0 +1,19 @@
+-- Organization-owned vaults, keyed on the Clerk organization ID.
+-- A vault with org_id set belongs to the organization; user_id records its creator.
+
+ALTER TABLE vaults ADD COLUMN org_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_vaults_org_id ON vaults(org_id) WHERE org_id IS NOT NULL;
+
+-- Org-wide audit trail for organization admins
+ALTER TABLE audit_logs ADD COLUMN org_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org_id_created_at ON audit_logs(org_id, created_at DESC) WHERE org_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_audit_logs_org_id_created_at;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS org_id;
+
+DROP INDEX IF EXISTS idx_vaults_org_id;
+ALTER TABLE vaults DROP COLUMN IF EXISTS org_id;
@@ -0,0 +1,36 @@
+-- One-time share links ("sends") for handing a credential to someone without an account.
+-- The payload is encrypted client-side with a key that never reaches the server
+-- (it travels in the link fragment); the server only enforces views and expiry.
+
+CREATE TABLE shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    owner_id TEXT NOT NULL,
+    -- NULL once the share is exhausted or revoked
+    encrypted_payload BYTEA,
+    max_views INTEGER NOT NULL CHECK (max_views > 0),
+    views_remaining INTEGER NOT NULL CHECK (views_remaining >= 0),
+    expires_at TIMESTAMPTZ NOT NULL,
+    -- bcrypt hash of the optional access password
+    password_hash BYTEA,
+    purged_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_shares_owner_id ON shares(owner_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_shares_expires_at ON shares(expires_at);
+
+-- Share events are recorded in the owner's audit log; consumers are anonymous
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'share_create';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'share_view';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'share_revoke';
+ALTER TYPE actor_type ADD VALUE IF NOT EXISTS 'anonymous';
+
+ALTER TABLE audit_logs ADD COLUMN metadata JSONB;
+
+---- create above / drop below ----
+
+-- Removes shares and audit metadata; the share actions and anonymous actor type stay
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS metadata;
+
+DROP TABLE IF EXISTS shares;
@@ -0,0 +1,27 @@
+-- Client-encrypted file attachments on secrets. The blobs live in the configured
+-- storage driver; this table holds their metadata and storage keys.
+
+CREATE TABLE secret_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    secret_id UUID NOT NULL REFERENCES secrets(id) ON DELETE CASCADE,
+    -- User the attachment counts against for quota: the owner of the vault
+    owner_id TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL DEFAULT 'application/octet-stream',
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    storage_key TEXT NOT NULL UNIQUE,
+    encryption_version INTEGER NOT NULL DEFAULT 1,
+
+    -- NULL while the upload is streaming; incomplete rows are not listed
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_secret_attachments_secret_id ON secret_attachments(secret_id);
+CREATE INDEX IF NOT EXISTS idx_secret_attachments_owner_id ON secret_attachments(owner_id);
+
+---- create above / drop below ----
+
+-- Leaves the blobs in the storage driver
+DROP TABLE IF EXISTS secret_attachments;
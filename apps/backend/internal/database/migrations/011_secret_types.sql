@@ -0,0 +1,90 @@
+-- Table-driven secret types. Each type carries a schema for the plaintext
+-- attributes stored alongside its secrets (never the secret material itself),
+-- so new types are added with an INSERT instead of an enum migration.
+
+CREATE TABLE secret_types (
+    name TEXT PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_]{0,49}$'),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    display_name TEXT NOT NULL,
+    description TEXT,
+    -- Array of {name, label, kind, required, maxLength, pattern, min, max}
+    fields JSONB NOT NULL DEFAULT '[]' CHECK (jsonb_typeof(fields) = 'array')
+);
+
+CREATE TRIGGER set_secret_types_updated_at
+BEFORE UPDATE ON secret_types
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_updated_at();
+
+INSERT INTO secret_types (name, display_name, description, fields) VALUES
+(
+    'password', 'Password', 'Website or application login',
+    '[{"name": "username", "label": "Username", "kind": "text", "maxLength": 255},
+      {"name": "url", "label": "URL", "kind": "url", "maxLength": 2048}]'
+),
+(
+    'note', 'Secure Note', 'Free-form encrypted text',
+    '[]'
+),
+(
+    'api_key', 'API Key', 'Token or key for an API',
+    '[{"name": "service", "label": "Service", "kind": "text", "maxLength": 100},
+      {"name": "environment", "label": "Environment", "kind": "text", "maxLength": 50}]'
+),
+(
+    'card', 'Payment Card', 'Credit or debit card',
+    '[{"name": "brand", "label": "Brand", "kind": "text", "maxLength": 50},
+      {"name": "last4", "label": "Last 4 digits", "kind": "text", "pattern": "^[0-9]{4}$"}]'
+),
+(
+    'ssh_key', 'SSH Key', 'SSH private key',
+    '[{"name": "algorithm", "label": "Algorithm", "kind": "text", "maxLength": 50},
+      {"name": "fingerprint", "label": "Key fingerprint", "kind": "text", "maxLength": 128},
+      {"name": "comment", "label": "Comment", "kind": "text", "maxLength": 255}]'
+),
+(
+    'totp', 'TOTP Seed', 'One-time password generator seed',
+    '[{"name": "issuer", "label": "Issuer", "kind": "text", "maxLength": 100},
+      {"name": "accountName", "label": "Account", "kind": "text", "maxLength": 255},
+      {"name": "digits", "label": "Digits", "kind": "integer", "min": 6, "max": 8},
+      {"name": "period", "label": "Period (seconds)", "kind": "integer", "min": 15, "max": 300}]'
+),
+(
+    'identity', 'Identity Document', 'Passport, licence or other ID',
+    '[{"name": "documentType", "label": "Document type", "kind": "text", "maxLength": 50},
+      {"name": "country", "label": "Issuing country", "kind": "text", "pattern": "^[A-Z]{2}$"}]'
+),
+(
+    'database', 'Database Credential', 'Database connection credentials',
+    '[{"name": "engine", "label": "Engine", "kind": "text", "maxLength": 50},
+      {"name": "host", "label": "Host", "kind": "hostname", "required": true, "maxLength": 255},
+      {"name": "port", "label": "Port", "kind": "integer", "min": 1, "max": 65535},
+      {"name": "database", "label": "Database", "kind": "text", "maxLength": 255},
+      {"name": "username", "label": "Username", "kind": "text", "maxLength": 255}]'
+);
+
+ALTER TABLE secrets ALTER COLUMN type TYPE TEXT USING type::text;
+ALTER TABLE secrets
+    ADD CONSTRAINT secrets_type_fkey FOREIGN KEY (type) REFERENCES secret_types(name) ON UPDATE CASCADE;
+DROP TYPE secret_type;
+
+ALTER TABLE secret_metadata ADD COLUMN attributes JSONB NOT NULL DEFAULT '{}';
+
+---- create above / drop below ----
+
+-- Fails while secrets use a type other than the four the enum had
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS attributes;
+
+CREATE TYPE secret_type AS ENUM(
+    'password',
+    'note',
+    'api_key',
+    'card'
+);
+
+ALTER TABLE secrets DROP CONSTRAINT IF EXISTS secrets_type_fkey;
+ALTER TABLE secrets ALTER COLUMN type TYPE secret_type USING type::secret_type;
+
+DROP TABLE IF EXISTS secret_types;
@@ -0,0 +1,33 @@
+-- User-defined plaintext fields and full-text search over secret metadata.
+
+ALTER TABLE secret_metadata
+    ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}' CHECK (jsonb_typeof(custom_fields) = 'object');
+
+-- Weighted document for ranking: title first, then domain and tags, then
+-- custom field names/values and type attributes. The 'simple' configuration
+-- is used because metadata is mostly names and identifiers, not prose.
+CREATE OR REPLACE FUNCTION secret_search_vector(
+    title TEXT, domain TEXT, tags TEXT[], attributes JSONB, custom_fields JSONB
+) RETURNS tsvector
+LANGUAGE SQL IMMUTABLE PARALLEL SAFE AS $$
+    SELECT
+        setweight(to_tsvector('simple'::regconfig, COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, COALESCE(domain, '')), 'B') ||
+        setweight(to_tsvector('simple'::regconfig, COALESCE(array_to_string(tags, ' '), '')), 'B') ||
+        setweight(jsonb_to_tsvector('simple'::regconfig, COALESCE(custom_fields, '{}'), '["key", "string"]'), 'C') ||
+        setweight(jsonb_to_tsvector('simple'::regconfig, COALESCE(attributes, '{}'), '["string", "numeric"]'), 'D')
+$$;
+
+ALTER TABLE secret_metadata
+    ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (secret_search_vector(title, domain, tags, attributes, custom_fields)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_secret_metadata_search ON secret_metadata USING GIN (search_vector);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_secret_metadata_search;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS secret_search_vector(TEXT, TEXT, TEXT[], JSONB, JSONB);
+
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS custom_fields;
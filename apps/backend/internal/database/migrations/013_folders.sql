@@ -0,0 +1,44 @@
+-- Hierarchical folders inside a vault. Deleting a folder never cascades to its
+-- contents: the API moves child folders and secrets up a level or into the
+-- vault's trash folder before removing it.
+
+CREATE TABLE folders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    vault_id UUID NOT NULL REFERENCES vaults(id) ON DELETE CASCADE,
+    -- NULL for top-level folders
+    parent_id UUID REFERENCES folders(id),
+    name TEXT NOT NULL CHECK (char_length(name) BETWEEN 1 AND 200),
+    -- Each vault has at most one trash folder, created on first use
+    is_trash BOOLEAN NOT NULL DEFAULT FALSE,
+
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE TRIGGER set_folders_updated_at
+BEFORE UPDATE ON folders
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_folders_vault_id ON folders(vault_id);
+CREATE INDEX IF NOT EXISTS idx_folders_parent_id ON folders(parent_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_folders_vault_trash ON folders(vault_id) WHERE is_trash;
+
+ALTER TABLE secrets ADD COLUMN folder_id UUID REFERENCES folders(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_secrets_folder_id ON secrets(folder_id);
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'folder_create';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'folder_rename';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'folder_move';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'folder_delete';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'secret_move';
+
+---- create above / drop below ----
+
+-- Removes folders and secrets.folder_id; the folder and secret_move actions stay
+DROP INDEX IF EXISTS idx_secrets_folder_id;
+ALTER TABLE secrets DROP COLUMN IF EXISTS folder_id;
+
+DROP TABLE IF EXISTS folders;
@@ -0,0 +1,6 @@
+-- Summary entry written for each POST /api/secrets/batch request
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'secret_batch';
+
+---- create above / drop below ----
+
+-- Nothing to undo: the secret_batch action stays
@@ -0,0 +1,6 @@
+-- Logged on both vaults when a secret is copied to another vault
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'secret_copy';
+
+---- create above / drop below ----
+
+-- Nothing to undo: the secret_copy action stays
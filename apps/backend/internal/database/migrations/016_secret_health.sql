@@ -0,0 +1,25 @@
+-- Password health signals computed by the client. The server never sees the
+-- password: it stores a strength score, a reuse fingerprint (an HMAC of the
+-- password under a key derived from the vault key, so equal passwords match
+-- only within a vault) and when the password was last rotated.
+
+CREATE TABLE secret_health (
+    secret_id UUID PRIMARY KEY REFERENCES secrets(id) ON DELETE CASCADE,
+    strength_score SMALLINT NOT NULL CHECK (strength_score BETWEEN 0 AND 4),
+    reuse_fingerprint TEXT,
+    -- NULL falls back to secrets.updated_at
+    last_rotated_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_secret_health_reuse_fingerprint ON secret_health(reuse_fingerprint)
+    WHERE reuse_fingerprint IS NOT NULL;
+
+CREATE TRIGGER set_secret_health_updated_at
+    BEFORE UPDATE ON secret_health
+    FOR EACH ROW
+    EXECUTE FUNCTION trigger_set_updated_at();
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS secret_health;
@@ -0,0 +1,14 @@
+-- Locally imported breached-password corpus served by the k-anonymity range
+-- API. Loaded with cmd/breachimport; hashes are upper-case hex SHA-1 split
+-- into the 5-character range prefix and the 35-character suffix.
+
+CREATE TABLE breached_passwords (
+    prefix CHAR(5) NOT NULL,
+    suffix CHAR(35) NOT NULL,
+    count INTEGER NOT NULL DEFAULT 1 CHECK (count >= 0),
+    PRIMARY KEY (prefix, suffix)
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS breached_passwords;
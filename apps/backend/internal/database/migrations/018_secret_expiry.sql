@@ -0,0 +1,29 @@
+-- Expiry and rotation policy on secrets. rotated_at is when the current
+-- payload was stored; a secret is due for rotation rotate_every_days after it.
+
+ALTER TABLE secrets
+    ADD COLUMN expires_at TIMESTAMPTZ,
+    ADD COLUMN rotate_every_days INTEGER CHECK (rotate_every_days > 0),
+    ADD COLUMN rotated_at TIMESTAMPTZ;
+
+-- Backfill without touching updated_at
+ALTER TABLE secrets DISABLE TRIGGER set_secrets_updated_at;
+UPDATE secrets SET rotated_at = updated_at;
+ALTER TABLE secrets ENABLE TRIGGER set_secrets_updated_at;
+
+ALTER TABLE secrets
+    ALTER COLUMN rotated_at SET DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN rotated_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_secrets_expires_at ON secrets(expires_at) WHERE expires_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_secrets_rotation ON secrets(rotated_at) WHERE rotate_every_days IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_secrets_rotation;
+DROP INDEX IF EXISTS idx_secrets_expires_at;
+
+ALTER TABLE secrets
+    DROP COLUMN IF EXISTS rotated_at,
+    DROP COLUMN IF EXISTS rotate_every_days,
+    DROP COLUMN IF EXISTS expires_at;
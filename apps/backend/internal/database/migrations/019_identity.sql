@@ -0,0 +1,183 @@
+-- One identity model: users.id is the stable internal user ID that every
+-- user reference points at, and the Clerk user ID is kept as
+-- users.external_auth_id. Migration 004 had turned the references into Clerk
+-- IDs without foreign keys; this backfills a users row for each of them and
+-- converts the columns back.
+--
+-- Rows owned by a user go with the user. Audit entries, grants and service
+-- accounts outlive the user who made them, so those references are set null.
+
+-- Users first seen through their data have no known email
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+
+-- Register every user referenced by existing data. A reference is a Clerk ID,
+-- or a users.id written before migration 004.
+INSERT INTO users (external_auth_id)
+SELECT DISTINCT refs.ref
+FROM (
+    SELECT user_id AS ref FROM vaults
+    UNION SELECT user_id FROM devices
+    UNION SELECT user_id FROM audit_logs WHERE actor_type <> 'service_account'
+    UNION SELECT owner_id FROM shares
+    UNION SELECT owner_id FROM secret_attachments
+    UNION SELECT created_by FROM service_accounts
+    UNION SELECT member_id FROM vault_members WHERE member_type = 'user'
+    UNION SELECT granted_by FROM vault_members
+) refs
+WHERE refs.ref <> ''
+    AND NOT EXISTS (SELECT 1 FROM users u WHERE u.external_auth_id = refs.ref OR u.id::text = refs.ref)
+ON CONFLICT (external_auth_id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION pg_temp.internal_user_id(ref TEXT) RETURNS UUID
+LANGUAGE SQL STABLE AS $$
+    SELECT id FROM users
+    WHERE external_auth_id = ref OR id::text = ref
+    ORDER BY external_auth_id = ref DESC
+    LIMIT 1
+$$;
+
+-- Data owned by a user
+ALTER TABLE vaults ALTER COLUMN user_id TYPE UUID USING pg_temp.internal_user_id(user_id);
+ALTER TABLE vaults
+    ADD CONSTRAINT vaults_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE devices ALTER COLUMN user_id TYPE UUID USING pg_temp.internal_user_id(user_id);
+ALTER TABLE devices
+    ADD CONSTRAINT devices_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE shares ALTER COLUMN owner_id TYPE UUID USING pg_temp.internal_user_id(owner_id);
+ALTER TABLE shares
+    ADD CONSTRAINT shares_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE secret_attachments ALTER COLUMN owner_id TYPE UUID USING pg_temp.internal_user_id(owner_id);
+ALTER TABLE secret_attachments
+    ADD CONSTRAINT secret_attachments_owner_id_fkey FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE;
+
+-- Service accounts belong to their organization, not their creator
+ALTER TABLE service_accounts ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE service_accounts ALTER COLUMN created_by TYPE UUID USING pg_temp.internal_user_id(created_by);
+ALTER TABLE service_accounts
+    ADD CONSTRAINT service_accounts_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Vault members reference a user or a service account. member_id stays as
+-- the ID of whichever is set, so grants are still looked up by
+-- (member_type, member_id).
+ALTER TABLE vault_members
+    ADD COLUMN user_id UUID,
+    ADD COLUMN service_account_id UUID;
+
+UPDATE vault_members SET user_id = pg_temp.internal_user_id(member_id) WHERE member_type = 'user';
+UPDATE vault_members m SET service_account_id = sa.id
+FROM service_accounts sa
+WHERE m.member_type = 'service_account' AND sa.id::text = m.member_id;
+
+-- Grants to service accounts that no longer exist
+DELETE FROM vault_members WHERE user_id IS NULL AND service_account_id IS NULL;
+
+ALTER TABLE vault_members DROP COLUMN member_id;
+ALTER TABLE vault_members
+    ADD COLUMN member_id UUID GENERATED ALWAYS AS (COALESCE(user_id, service_account_id)) STORED,
+    ADD CONSTRAINT vault_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    ADD CONSTRAINT vault_members_service_account_id_fkey
+        FOREIGN KEY (service_account_id) REFERENCES service_accounts(id) ON DELETE CASCADE,
+    ADD CONSTRAINT vault_members_member_check CHECK (
+        CASE member_type
+            WHEN 'user' THEN user_id IS NOT NULL AND service_account_id IS NULL
+            WHEN 'service_account' THEN service_account_id IS NOT NULL AND user_id IS NULL
+            ELSE FALSE
+        END
+    ),
+    ADD CONSTRAINT vault_members_vault_id_member_type_member_id_key UNIQUE (vault_id, member_type, member_id);
+
+CREATE INDEX IF NOT EXISTS idx_vault_members_member ON vault_members(member_type, member_id);
+
+ALTER TABLE vault_members ALTER COLUMN granted_by DROP NOT NULL;
+ALTER TABLE vault_members ALTER COLUMN granted_by TYPE UUID USING pg_temp.internal_user_id(granted_by);
+ALTER TABLE vault_members
+    ADD CONSTRAINT vault_members_granted_by_fkey FOREIGN KEY (granted_by) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Audit entries belong to a user (the actor, or the owner for anonymous
+-- events) or to a service account
+ALTER TABLE audit_logs ADD COLUMN service_account_id UUID;
+UPDATE audit_logs a SET service_account_id = sa.id
+FROM service_accounts sa
+WHERE a.actor_type = 'service_account' AND sa.id::text = a.user_id;
+
+ALTER TABLE audit_logs ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE audit_logs ALTER COLUMN user_id TYPE UUID USING
+    CASE WHEN actor_type = 'service_account' THEN NULL ELSE pg_temp.internal_user_id(user_id) END;
+
+ALTER TABLE audit_logs
+    ADD CONSTRAINT audit_logs_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+    ADD CONSTRAINT audit_logs_service_account_id_fkey
+        FOREIGN KEY (service_account_id) REFERENCES service_accounts(id) ON DELETE SET NULL,
+    ADD CONSTRAINT audit_logs_actor_check CHECK (
+        CASE WHEN actor_type = 'service_account' THEN user_id IS NULL ELSE service_account_id IS NULL END
+    );
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_service_account_id_created_at
+    ON audit_logs(service_account_id, created_at DESC) WHERE service_account_id IS NOT NULL;
+
+-- Deleting a secret used to fail once anything about it had been logged
+ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS audit_logs_secret_id_fkey;
+ALTER TABLE audit_logs
+    ADD CONSTRAINT audit_logs_secret_id_fkey FOREIGN KEY (secret_id) REFERENCES secrets(id) ON DELETE SET NULL;
+
+---- create above / drop below ----
+
+-- References whose user was deleted come back as empty strings
+
+CREATE OR REPLACE FUNCTION pg_temp.external_auth_id(internal UUID) RETURNS TEXT
+LANGUAGE SQL STABLE AS $$
+    SELECT external_auth_id FROM users WHERE id = internal
+$$;
+
+ALTER TABLE audit_logs DROP CONSTRAINT IF EXISTS audit_logs_secret_id_fkey;
+ALTER TABLE audit_logs
+    ADD CONSTRAINT audit_logs_secret_id_fkey FOREIGN KEY (secret_id) REFERENCES secrets(id);
+
+DROP INDEX IF EXISTS idx_audit_logs_service_account_id_created_at;
+ALTER TABLE audit_logs
+    DROP CONSTRAINT IF EXISTS audit_logs_actor_check,
+    DROP CONSTRAINT IF EXISTS audit_logs_service_account_id_fkey,
+    DROP CONSTRAINT IF EXISTS audit_logs_user_id_fkey;
+ALTER TABLE audit_logs ALTER COLUMN user_id TYPE TEXT USING
+    COALESCE(pg_temp.external_auth_id(user_id), service_account_id::text, '');
+ALTER TABLE audit_logs ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS service_account_id;
+
+ALTER TABLE vault_members DROP CONSTRAINT IF EXISTS vault_members_granted_by_fkey;
+ALTER TABLE vault_members ALTER COLUMN granted_by TYPE TEXT USING COALESCE(pg_temp.external_auth_id(granted_by), '');
+ALTER TABLE vault_members ALTER COLUMN granted_by SET NOT NULL;
+
+ALTER TABLE vault_members ADD COLUMN member_ref TEXT;
+UPDATE vault_members SET member_ref = COALESCE(pg_temp.external_auth_id(user_id), service_account_id::text);
+ALTER TABLE vault_members DROP COLUMN member_id;
+ALTER TABLE vault_members
+    DROP CONSTRAINT IF EXISTS vault_members_member_check,
+    DROP COLUMN IF EXISTS user_id,
+    DROP COLUMN IF EXISTS service_account_id;
+ALTER TABLE vault_members RENAME COLUMN member_ref TO member_id;
+ALTER TABLE vault_members ALTER COLUMN member_id SET NOT NULL;
+ALTER TABLE vault_members
+    ADD CONSTRAINT vault_members_vault_id_member_type_member_id_key UNIQUE (vault_id, member_type, member_id);
+CREATE INDEX IF NOT EXISTS idx_vault_members_member ON vault_members(member_type, member_id);
+
+ALTER TABLE service_accounts DROP CONSTRAINT IF EXISTS service_accounts_created_by_fkey;
+ALTER TABLE service_accounts ALTER COLUMN created_by TYPE TEXT USING COALESCE(pg_temp.external_auth_id(created_by), '');
+ALTER TABLE service_accounts ALTER COLUMN created_by SET NOT NULL;
+
+ALTER TABLE secret_attachments DROP CONSTRAINT IF EXISTS secret_attachments_owner_id_fkey;
+ALTER TABLE secret_attachments ALTER COLUMN owner_id TYPE TEXT USING pg_temp.external_auth_id(owner_id);
+
+ALTER TABLE shares DROP CONSTRAINT IF EXISTS shares_owner_id_fkey;
+ALTER TABLE shares ALTER COLUMN owner_id TYPE TEXT USING pg_temp.external_auth_id(owner_id);
+
+ALTER TABLE devices DROP CONSTRAINT IF EXISTS devices_user_id_fkey;
+ALTER TABLE devices ALTER COLUMN user_id TYPE TEXT USING pg_temp.external_auth_id(user_id);
+
+ALTER TABLE vaults DROP CONSTRAINT IF EXISTS vaults_user_id_fkey;
+ALTER TABLE vaults ALTER COLUMN user_id TYPE TEXT USING pg_temp.external_auth_id(user_id);
+
+UPDATE users SET email = '' WHERE email IS NULL;
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
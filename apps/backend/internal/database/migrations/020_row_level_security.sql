@@ -0,0 +1,94 @@
+-- Row-level security on tenant data, as a second line of defense behind the
+-- access checks in the services. Requests run their statements as
+-- psvault_tenant with app.current_user (internal user or service account ID)
+-- and app.current_org (the user's active organization) set for the
+-- transaction; psvault_tenant sees only rows those principals may reach.
+-- Connections as the owning role, used by jobs, admin commands and
+-- migrations, are not restricted. Creating the role needs CREATEROLE.
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'psvault_tenant') THEN
+        CREATE ROLE psvault_tenant NOLOGIN;
+    END IF;
+END
+$$;
+
+GRANT psvault_tenant TO CURRENT_USER;
+GRANT USAGE ON SCHEMA public TO psvault_tenant;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO psvault_tenant;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO psvault_tenant;
+REVOKE ALL ON schema_version FROM psvault_tenant;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO psvault_tenant;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT ON SEQUENCES TO psvault_tenant;
+
+CREATE OR REPLACE FUNCTION app_current_user() RETURNS UUID
+LANGUAGE SQL STABLE AS $$
+    SELECT NULLIF(current_setting('app.current_user', true), '')::uuid
+$$;
+
+CREATE OR REPLACE FUNCTION app_current_org() RETURNS TEXT
+LANGUAGE SQL STABLE AS $$
+    SELECT NULLIF(current_setting('app.current_org', true), '')
+$$;
+
+-- Personal vaults of the user, vaults of the active organization and vaults
+-- shared through membership, as in resolveVaultAccess
+ALTER TABLE vaults ENABLE ROW LEVEL SECURITY;
+CREATE POLICY vaults_tenant ON vaults TO psvault_tenant
+    USING (
+        (org_id IS NULL AND user_id = app_current_user())
+        OR org_id = app_current_org()
+        OR EXISTS (SELECT 1 FROM vault_members m WHERE m.vault_id = vaults.id AND m.member_id = app_current_user())
+    );
+
+-- Secrets and their metadata follow their vault
+ALTER TABLE secrets ENABLE ROW LEVEL SECURITY;
+CREATE POLICY secrets_tenant ON secrets TO psvault_tenant
+    USING (EXISTS (SELECT 1 FROM vaults v WHERE v.id = secrets.vault_id));
+
+ALTER TABLE secret_metadata ENABLE ROW LEVEL SECURITY;
+CREATE POLICY secret_metadata_tenant ON secret_metadata TO psvault_tenant
+    USING (EXISTS (SELECT 1 FROM secrets s WHERE s.id = secret_metadata.secret_id));
+
+ALTER TABLE devices ENABLE ROW LEVEL SECURITY;
+CREATE POLICY devices_tenant ON devices TO psvault_tenant
+    USING (user_id = app_current_user());
+
+-- Principals read their own entries and those of the active organization, and
+-- write only their own
+ALTER TABLE audit_logs ENABLE ROW LEVEL SECURITY;
+CREATE POLICY audit_logs_tenant ON audit_logs TO psvault_tenant
+    USING (user_id = app_current_user() OR service_account_id = app_current_user() OR org_id = app_current_org())
+    WITH CHECK (user_id = app_current_user() OR service_account_id = app_current_user());
+
+---- create above / drop below ----
+
+DROP POLICY IF EXISTS audit_logs_tenant ON audit_logs;
+ALTER TABLE audit_logs DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS devices_tenant ON devices;
+ALTER TABLE devices DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS secret_metadata_tenant ON secret_metadata;
+ALTER TABLE secret_metadata DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS secrets_tenant ON secrets;
+ALTER TABLE secrets DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS vaults_tenant ON vaults;
+ALTER TABLE vaults DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_current_org();
+DROP FUNCTION IF EXISTS app_current_user();
+
+-- Revokes the grants and default privileges in this database; the role itself
+-- stays while other databases in the cluster still use it
+DROP OWNED BY psvault_tenant;
+DO $$
+BEGIN
+    DROP ROLE IF EXISTS psvault_tenant;
+EXCEPTION WHEN dependent_objects_still_exist THEN
+    RAISE NOTICE 'role psvault_tenant is still used by another database';
+END
+$$;
@@ -0,0 +1,36 @@
+-- Transactional outbox: side effects of a change (audit events, emails and
+-- other background tasks) are written in the same transaction as the change
+-- and published afterwards by the relay task, at least once. Tasks are
+-- enqueued on asynq and events published on Redis pub/sub.
+
+CREATE TABLE outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- 'task' rows are enqueued as asynq tasks of type topic, 'event' rows are
+    -- published on the Redis channel events:<topic>
+    kind TEXT NOT NULL CHECK (kind IN ('task', 'event')),
+    topic TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    -- Rows with the same key are written once; the key (or the row ID) also
+    -- identifies the message to consumers so redeliveries can be ignored
+    idempotency_key TEXT UNIQUE,
+
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ,
+    -- Set once the relay gives up after the configured number of attempts
+    failed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(available_at) WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox(published_at) WHERE published_at IS NOT NULL;
+
+-- Requests write to the outbox as psvault_tenant but never read it back:
+-- payloads span every tenant
+REVOKE SELECT, UPDATE, DELETE ON outbox FROM psvault_tenant;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,62 @@
+-- Outgoing webhooks: endpoints notified of vault and secret changes, owned by a
+-- user (events of their personal vaults) or an organization (events of its
+-- vaults). Deliveries are signed with the endpoint's secret, which is kept in
+-- the clear because signing needs it.
+
+CREATE TABLE webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Owner of personal webhooks, creator of organization webhooks
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    org_id TEXT,
+    url TEXT NOT NULL,
+    description TEXT,
+    -- Event types delivered, e.g. secret.update or secret.*; empty for all
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL,
+
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMPTZ,
+    disabled_reason TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_user_id ON webhooks(user_id) WHERE org_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_webhooks_org_id ON webhooks(org_id) WHERE org_id IS NOT NULL;
+
+CREATE TRIGGER set_webhooks_updated_at
+BEFORE UPDATE ON webhooks
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_updated_at();
+
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    -- The audit entry the event describes
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    -- Set on deliveries requested manually, which repeat an earlier one
+    redelivery_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_code INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms INTEGER,
+    last_attempt_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ
+);
+
+-- An event is delivered once per webhook, however often it is fanned out
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON webhook_deliveries(webhook_id, event_id) WHERE redelivery_of IS NULL;
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
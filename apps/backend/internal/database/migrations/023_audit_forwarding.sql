@@ -0,0 +1,27 @@
+-- Forwarding of the audit log to a SIEM: each checkpoint records the last
+-- entry a forwarder sent, in (created_at, id) order, so a restarted
+-- forwarder carries on where it stopped.
+
+CREATE TABLE audit_forward_checkpoints (
+    name TEXT PRIMARY KEY,
+    last_created_at TIMESTAMPTZ NOT NULL,
+    last_id UUID NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER set_audit_forward_checkpoints_updated_at
+BEFORE UPDATE ON audit_forward_checkpoints
+FOR EACH ROW
+EXECUTE FUNCTION trigger_set_updated_at();
+
+-- Checkpoints span every tenant and are only used by the forwarder
+REVOKE ALL ON audit_forward_checkpoints FROM psvault_tenant;
+
+-- Forwarding reads the log in (created_at, id) order; organization exports by
+-- time range use idx_audit_logs_org_id_created_at from 008
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at_id ON audit_logs(created_at, id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_audit_logs_created_at_id;
+DROP TABLE IF EXISTS audit_forward_checkpoints;
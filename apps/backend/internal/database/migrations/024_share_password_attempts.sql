@@ -0,0 +1,8 @@
+-- Wrong passwords given for a share link. The link is purged once they reach
+-- the service's limit, so its password cannot be guessed at leisure.
+
+ALTER TABLE shares ADD COLUMN failed_password_attempts INTEGER NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE shares DROP COLUMN IF EXISTS failed_password_attempts;
@@ -0,0 +1,34 @@
+-- Vault memberships are keyed by (member_type, member_id). The vaults policy
+-- matched on member_id alone, so a user and a service account sharing an ID
+-- would see each other's shared vaults; match the principal's type too.
+-- app.current_actor_type is set alongside app.current_user.
+
+CREATE OR REPLACE FUNCTION app_current_actor_type() RETURNS TEXT
+LANGUAGE SQL STABLE AS $$
+    SELECT NULLIF(current_setting('app.current_actor_type', true), '')
+$$;
+
+DROP POLICY IF EXISTS vaults_tenant ON vaults;
+CREATE POLICY vaults_tenant ON vaults TO psvault_tenant
+    USING (
+        (org_id IS NULL AND user_id = app_current_user())
+        OR org_id = app_current_org()
+        OR EXISTS (
+            SELECT 1 FROM vault_members m
+            WHERE m.vault_id = vaults.id
+                AND m.member_type::text = app_current_actor_type()
+                AND m.member_id = app_current_user()
+        )
+    );
+
+---- create above / drop below ----
+
+DROP POLICY IF EXISTS vaults_tenant ON vaults;
+CREATE POLICY vaults_tenant ON vaults TO psvault_tenant
+    USING (
+        (org_id IS NULL AND user_id = app_current_user())
+        OR org_id = app_current_org()
+        OR EXISTS (SELECT 1 FROM vault_members m WHERE m.vault_id = vaults.id AND m.member_id = app_current_user())
+    );
+
+DROP FUNCTION IF EXISTS app_current_actor_type();
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"net"
@@ -12,6 +13,8 @@ import (
 	"github.com/Sameer16536/psvault/internal/config"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	tern "github.com/jackc/tern/v2/migrate"
 	"github.com/rs/zerolog"
 )
@@ -19,7 +22,109 @@ import (
 //go:embed migrations/*.sql
 var migrations embed.FS
 
+// MigrationInfo describes one embedded migration
+type MigrationInfo struct {
+	Version int32  `json:"version"`
+	Name    string `json:"name"`
+	Applied bool   `json:"applied"`
+}
+
+// MigrationStatus compares the schema version of the database with the
+// embedded migrations
+type MigrationStatus struct {
+	Current    int32           `json:"current"`
+	Latest     int32           `json:"latest"`
+	Migrations []MigrationInfo `json:"migrations,omitempty"`
+}
+
+// Pending reports whether migrations remain to be applied
+func (s *MigrationStatus) Pending() bool {
+	return s.Current < s.Latest
+}
+
+// Migrate applies every pending migration
 func Migrate(ctx context.Context, logger *zerolog.Logger, cfg *config.Config) error {
+	return MigrateTo(ctx, logger, cfg, -1)
+}
+
+// MigrateTo migrates up or down to version; a negative version means the latest
+func MigrateTo(ctx context.Context, logger *zerolog.Logger, cfg *config.Config, version int32) error {
+	conn, m, err := newMigrator(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	latest := int32(len(m.Migrations))
+	if version < 0 {
+		version = latest
+	}
+	if version > latest {
+		return fmt.Errorf("unknown migration version %d, latest is %d", version, latest)
+	}
+	from, err := m.GetCurrentVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieving current database migration version: %w", err)
+	}
+	m.OnStart = func(sequence int32, name, direction, _ string) {
+		logger.Info().Msgf("migrating %s: %d %s", direction, sequence, name)
+	}
+	if err := m.MigrateTo(ctx, version); err != nil {
+		return fmt.Errorf("migrating database schema from %d to %d: %w", from, version, err)
+	}
+	if from == version {
+		logger.Info().Msgf("database schema up to date, version %d", version)
+	} else {
+		logger.Info().Msgf("migrated database schema, from %d to %d", from, version)
+	}
+	return nil
+}
+
+// GetMigrationStatus reports which embedded migrations have been applied
+func GetMigrationStatus(ctx context.Context, cfg *config.Config) (*MigrationStatus, error) {
+	conn, m, err := newMigrator(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	current, err := m.GetCurrentVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving current database migration version: %w", err)
+	}
+	status := &MigrationStatus{Current: current, Latest: int32(len(m.Migrations))}
+	for _, migration := range m.Migrations {
+		status.Migrations = append(status.Migrations, MigrationInfo{
+			Version: migration.Sequence,
+			Name:    migration.Name,
+			Applied: migration.Sequence <= current,
+		})
+	}
+	return status, nil
+}
+
+// SchemaStatus reports the current and latest schema versions without the
+// migration list. Unlike GetMigrationStatus it uses the pool and never creates
+// the version table, so /status can call it on every request.
+func SchemaStatus(ctx context.Context, pool *pgxpool.Pool) (*MigrationStatus, error) {
+	paths, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	status := &MigrationStatus{Latest: int32(len(paths))}
+	err = pool.QueryRow(ctx, `SELECT version FROM schema_version`).Scan(&status.Current)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "42P01" {
+		// undefined_table: no migration has ever run
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("retrieving current database migration version: %w", err)
+	}
+	return status, nil
+}
+
+func newMigrator(ctx context.Context, cfg *config.Config) (*pgx.Conn, *tern.Migrator, error) {
 	hostPort := net.JoinHostPort(cfg.Database.Host, strconv.Itoa(cfg.Database.Port))
 
 	// URL-encode the password
@@ -34,32 +139,22 @@ func Migrate(ctx context.Context, logger *zerolog.Logger, cfg *config.Config) er
 
 	conn, err := pgx.Connect(ctx, dsn)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	defer conn.Close(ctx)
 
 	m, err := tern.NewMigrator(ctx, conn, "schema_version")
 	if err != nil {
-		return fmt.Errorf("constructing database migrator: %w", err)
+		conn.Close(ctx)
+		return nil, nil, fmt.Errorf("constructing database migrator: %w", err)
 	}
 	subtree, err := fs.Sub(migrations, "migrations")
 	if err != nil {
-		return fmt.Errorf("retrieving database migrations subtree: %w", err)
+		conn.Close(ctx)
+		return nil, nil, fmt.Errorf("retrieving database migrations subtree: %w", err)
 	}
 	if err := m.LoadMigrations(subtree); err != nil {
-		return fmt.Errorf("loading database migrations: %w", err)
-	}
-	from, err := m.GetCurrentVersion(ctx)
-	if err != nil {
-		return fmt.Errorf("retreiving current database migration version")
-	}
-	if err := m.Migrate(ctx); err != nil {
-		return err
+		conn.Close(ctx)
+		return nil, nil, fmt.Errorf("loading database migrations: %w", err)
 	}
-	if from == int32(len(m.Migrations)) {
-		logger.Info().Msgf("database schema up to date, version %d", len(m.Migrations))
-	} else {
-		logger.Info().Msgf("migrated database schema, from %d to %d", from, len(m.Migrations))
-	}
-	return nil
+	return conn, m, nil
 }
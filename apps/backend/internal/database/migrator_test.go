@@ -0,0 +1,197 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/database"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaSnapshot lists the columns, indexes, constraints, functions, enum
+// values and row-level security policies of the public schema, ignoring
+// tern's version table
+func schemaSnapshot(t *testing.T, ctx context.Context, pool *pgxpool.Pool) []string {
+	t.Helper()
+	query := `
+		SELECT 'column ' || table_name || '.' || column_name || ' ' || data_type || ' ' || is_nullable
+			|| ' ' || COALESCE(column_default, '')
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name <> 'schema_version'
+		UNION ALL
+		SELECT 'index ' || indexdef FROM pg_indexes
+		WHERE schemaname = 'public' AND tablename <> 'schema_version'
+		UNION ALL
+		SELECT 'constraint ' || conrelid::regclass || ' ' || pg_get_constraintdef(oid)
+		FROM pg_constraint
+		WHERE connamespace = 'public'::regnamespace AND conrelid::regclass::text <> 'schema_version'
+		UNION ALL
+		SELECT 'function ' || oid::regprocedure FROM pg_proc
+		WHERE pronamespace = 'public'::regnamespace
+		UNION ALL
+		SELECT 'enum ' || enumtypid::regtype || ' ' || enumlabel FROM pg_enum
+		ORDER BY 1
+	`
+	rows, err := pool.Query(ctx, query)
+	require.NoError(t, err)
+	defer rows.Close()
+	var snapshot []string
+	for rows.Next() {
+		var line string
+		require.NoError(t, rows.Scan(&line))
+		snapshot = append(snapshot, line)
+	}
+	require.NoError(t, rows.Err())
+	return snapshot
+}
+
+// Test: Every migration rolls back, and the schema is rebuilt identically
+func TestMigrate_UpDownUp(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	status, err := database.GetMigrationStatus(ctx, testDB.Config)
+	require.NoError(t, err)
+	require.False(t, status.Pending())
+	latest := status.Latest
+
+	before := schemaSnapshot(t, ctx, testDB.Pool)
+	require.NotEmpty(t, before)
+
+	// Step down one migration at a time so a failure names the migration
+	for version := latest - 1; version >= 0; version-- {
+		require.NoError(t, database.MigrateTo(ctx, &logger, testDB.Config, version), "migrating down to %d", version)
+	}
+
+	status, err = database.GetMigrationStatus(ctx, testDB.Config)
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), status.Current)
+	for _, m := range status.Migrations {
+		assert.False(t, m.Applied, m.Name)
+	}
+
+	// Enum values cannot be dropped, but their types go with migration 002
+	assert.Empty(t, schemaSnapshot(t, ctx, testDB.Pool))
+
+	schema, err := database.SchemaStatus(ctx, testDB.Pool)
+	require.NoError(t, err)
+	assert.True(t, schema.Pending())
+
+	require.NoError(t, database.Migrate(ctx, &logger, testDB.Config))
+
+	schema, err = database.SchemaStatus(ctx, testDB.Pool)
+	require.NoError(t, err)
+	assert.Equal(t, latest, schema.Current)
+	assert.Equal(t, latest, schema.Latest)
+
+	assert.Equal(t, before, schemaSnapshot(t, ctx, testDB.Pool))
+}
+
+// Test: Migrating past the latest version is rejected
+func TestMigrateTo_UnknownVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	status, err := database.GetMigrationStatus(ctx, testDB.Config)
+	require.NoError(t, err)
+
+	err = database.MigrateTo(ctx, &logger, testDB.Config, status.Latest+1)
+	assert.ErrorContains(t, err, "unknown migration version")
+}
+
+// Test: Clerk IDs written before migration 19 become users with foreign keys, and come back on rollback
+func TestMigrate_IdentityBackfill(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	pool := testDB.Pool
+
+	require.NoError(t, database.MigrateTo(ctx, &logger, testDB.Config, 18))
+
+	var vaultID, serviceAccountID string
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO vaults (user_id, name) VALUES ('user_owner', 'Legacy') RETURNING id`).Scan(&vaultID))
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO service_accounts (org_id, name, created_by, client_secret_hash)
+		VALUES ('org_legacy', 'ci', 'user_owner', '\x00') RETURNING id`).Scan(&serviceAccountID))
+	_, err := pool.Exec(ctx, `
+		INSERT INTO vault_members (vault_id, member_type, member_id, role, granted_by)
+		VALUES ($1, 'user', 'user_member', 'read', 'user_owner'),
+			($1, 'service_account', $2, 'write', 'user_owner')`, vaultID, serviceAccountID)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `
+		INSERT INTO audit_logs (user_id, actor_type, vault_id, action)
+		VALUES ('user_owner', 'user', $1, 'create'), ($2, 'service_account', $1, 'view')`, vaultID, serviceAccountID)
+	require.NoError(t, err)
+
+	require.NoError(t, database.Migrate(ctx, &logger, testDB.Config))
+
+	userID := func(externalAuthID string) string {
+		var id string
+		require.NoError(t, pool.QueryRow(ctx, `SELECT id FROM users WHERE external_auth_id = $1`, externalAuthID).Scan(&id))
+		return id
+	}
+	ownerID, memberID := userID("user_owner"), userID("user_member")
+
+	var vaultOwner, createdBy string
+	require.NoError(t, pool.QueryRow(ctx, `SELECT user_id FROM vaults WHERE id = $1`, vaultID).Scan(&vaultOwner))
+	require.NoError(t, pool.QueryRow(ctx, `SELECT created_by FROM service_accounts WHERE id = $1`, serviceAccountID).Scan(&createdBy))
+	assert.Equal(t, ownerID, vaultOwner)
+	assert.Equal(t, ownerID, createdBy)
+
+	var members []string
+	rows, err := pool.Query(ctx, `
+		SELECT member_type || ' ' || member_id || ' ' || granted_by
+		FROM vault_members WHERE vault_id = $1 ORDER BY member_type`, vaultID)
+	require.NoError(t, err)
+	for rows.Next() {
+		var line string
+		require.NoError(t, rows.Scan(&line))
+		members = append(members, line)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{
+		"user " + memberID + " " + ownerID,
+		"service_account " + serviceAccountID + " " + ownerID,
+	}, members)
+
+	var auditUsers, auditServiceAccounts int
+	require.NoError(t, pool.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE user_id = $1), COUNT(*) FILTER (WHERE service_account_id = $2)
+		FROM audit_logs`, ownerID, serviceAccountID).Scan(&auditUsers, &auditServiceAccounts))
+	assert.Equal(t, 1, auditUsers)
+	assert.Equal(t, 1, auditServiceAccounts)
+
+	// Rolling back restores the Clerk IDs
+	require.NoError(t, database.MigrateTo(ctx, &logger, testDB.Config, 18))
+	require.NoError(t, pool.QueryRow(ctx, `SELECT user_id FROM vaults WHERE id = $1`, vaultID).Scan(&vaultOwner))
+	assert.Equal(t, "user_owner", vaultOwner)
+	var memberRef string
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT member_id FROM vault_members WHERE vault_id = $1 AND member_type = 'user'`, vaultID).Scan(&memberRef))
+	assert.Equal(t, "user_member", memberRef)
+}
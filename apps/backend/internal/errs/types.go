@@ -1,6 +1,7 @@
 package errs
 
 import (
+	"fmt"
 	"net/http"
 )
 
@@ -66,3 +67,71 @@ func NewInternalServerError() *HTTPError {
 func ValidationError(err error) *HTTPError {
 	return NewBadRequestError("Validation failed: "+err.Error(), false, nil, nil, nil)
 }
+
+func NewTooManyRequestsError(message string, override bool) *HTTPError {
+	return &HTTPError{
+		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusTooManyRequests)),
+		Message:  message,
+		Status:   http.StatusTooManyRequests,
+		Override: override,
+	}
+}
+
+// NewReauthenticationRequiredError asks the client to verify the user again before retrying
+func NewReauthenticationRequiredError(message string) *HTTPError {
+	return &HTTPError{
+		Code:     "REAUTHENTICATION_REQUIRED",
+		Message:  message,
+		Status:   http.StatusUnauthorized,
+		Override: false,
+		Action: &Action{
+			Type:    ActionTypeRedirect,
+			Message: "Verify your identity to continue",
+			Value:   "/reverify",
+		},
+	}
+}
+
+func NewAccountLockedError(message string) *HTTPError {
+	return &HTTPError{
+		Code:     "ACCOUNT_LOCKED",
+		Message:  message,
+		Status:   http.StatusForbidden,
+		Override: false,
+	}
+}
+
+// NewStepUpRequiredError tells the client to verify the user again; the session
+// itself stays valid, so clients should not sign the user out
+func NewStepUpRequiredError(maxAgeSeconds int) *HTTPError {
+	return &HTTPError{
+		Code:     "REAUTHENTICATION_REQUIRED",
+		Message:  "This action requires a recent sign-in",
+		Status:   http.StatusForbidden,
+		Override: false,
+		Action: &Action{
+			Type:    ActionTypeRedirect,
+			Message: "Verify your identity to continue",
+			Value:   fmt.Sprintf("/reverify?max_age=%d", maxAgeSeconds),
+		},
+	}
+}
+
+func NewPayloadTooLargeError(message string) *HTTPError {
+	return &HTTPError{
+		Code:     MakeUpperCaseWithUnderscores(http.StatusText(http.StatusRequestEntityTooLarge)),
+		Message:  message,
+		Status:   http.StatusRequestEntityTooLarge,
+		Override: false,
+	}
+}
+
+// NewQuotaExceededError is returned when an upload would exceed the owner's storage quota
+func NewQuotaExceededError(message string) *HTTPError {
+	return &HTTPError{
+		Code:     "QUOTA_EXCEEDED",
+		Message:  message,
+		Status:   http.StatusRequestEntityTooLarge,
+		Override: false,
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// AttachmentContentTypeHeader carries the original content type of the decrypted file
+	AttachmentContentTypeHeader = "X-Attachment-Content-Type"
+	// AttachmentEncryptionVersionHeader carries the encryption version of the blob
+	AttachmentEncryptionVersionHeader = "X-Encryption-Version"
+)
+
+type AttachmentHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewAttachmentHandler(s *server.Server, services *service.Services) *AttachmentHandler {
+	return &AttachmentHandler{server: s, services: services}
+}
+
+// Upload - POST /api/secrets/:id/attachments (raw encrypted body)
+func (h *AttachmentHandler) Upload(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	secretID := c.Param("id")
+
+	// The body is the file itself, so only the query string is bound
+	var req secret.UploadAttachmentRequest
+	if err := (&echo.DefaultBinder{}).BindQueryParams(c, &req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	r := c.Request()
+	result, err := h.services.Attachment.Upload(r.Context(), userID, secretID, &req, r.Body, r.ContentLength)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to upload attachment")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to upload attachment")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// List - GET /api/secrets/:id/attachments
+func (h *AttachmentHandler) List(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	secretID := c.Param("id")
+
+	result, err := h.services.Attachment.List(c.Request().Context(), userID, secretID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to list attachments")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list attachments")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Download - GET /api/secrets/:id/attachments/:attachmentId (streams the encrypted blob)
+func (h *AttachmentHandler) Download(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	secretID := c.Param("id")
+	attachmentID := c.Param("attachmentId")
+
+	a, blob, err := h.services.Attachment.Download(c.Request().Context(), userID, secretID, attachmentID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("attachment_id", attachmentID).Msg("failed to download attachment")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to download attachment")
+	}
+	defer blob.Close()
+
+	header := c.Response().Header()
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName}))
+	header.Set("Content-Length", strconv.FormatInt(a.SizeBytes, 10))
+	header.Set("Cache-Control", "no-store")
+	header.Set(AttachmentContentTypeHeader, a.ContentType)
+	header.Set(AttachmentEncryptionVersionHeader, strconv.Itoa(a.EncryptionVersion))
+	// The blob is ciphertext regardless of the original file type
+	return c.Stream(http.StatusOK, "application/octet-stream", blob)
+}
+
+// Delete - DELETE /api/secrets/:id/attachments/:attachmentId
+func (h *AttachmentHandler) Delete(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	secretID := c.Param("id")
+	attachmentID := c.Param("attachmentId")
+
+	if err := h.services.Attachment.Delete(c.Request().Context(), userID, secretID, attachmentID); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("attachment_id", attachmentID).Msg("failed to delete attachment")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete attachment")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Usage - GET /api/attachments/usage
+func (h *AttachmentHandler) Usage(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	result, err := h.services.Attachment.Usage(c.Request().Context(), userID)
+	if err != nil {
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to get attachment usage")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get attachment usage")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	Handler
+	server   *server.Server
+	services *service.Services
+}
+
+func NewAuditHandler(s *server.Server, services *service.Services) *AuditHandler {
+	return &AuditHandler{Handler: NewHandler(s), server: s, services: services}
+}
+
+// List - GET /api/audit-logs
+func (h *AuditHandler) List(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req audit.ListAuditLogsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Audit.List(c.Request().Context(), userID, &req)
+	if err != nil {
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to list audit logs")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list audit logs")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ListOrg - GET /api/orgs/audit-logs
+func (h *AuditHandler) ListOrg(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req audit.ListAuditLogsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Audit.ListOrg(c.Request().Context(), &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to list organization audit logs")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list audit logs")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Export - GET /api/audit-logs/export
+func (h *AuditHandler) Export(c echo.Context) error {
+	return h.handleExport(c, func(c echo.Context, req *audit.ExportAuditLogsRequest) (func(io.Writer) error, error) {
+		return h.services.Audit.Export(c.Request().Context(), c.Get("user_id").(string), req)
+	})
+}
+
+// ExportOrg - GET /api/orgs/audit-logs/export
+func (h *AuditHandler) ExportOrg(c echo.Context) error {
+	return h.handleExport(c, func(c echo.Context, req *audit.ExportAuditLogsRequest) (func(io.Writer) error, error) {
+		return h.services.Audit.ExportOrg(c.Request().Context(), req)
+	})
+}
+
+// handleExport streams an export as a CSV or NDJSON attachment, named after the requested format
+func (h *AuditHandler) handleExport(c echo.Context, export func(c echo.Context, req *audit.ExportAuditLogsRequest) (func(io.Writer) error, error)) error {
+	format := audit.ExportFormat(c.QueryParam("format"))
+	return HandleFile(h.Handler, func(c echo.Context, req *audit.ExportAuditLogsRequest) (FileStream, error) {
+		stream, err := export(c, req)
+		return stream, err
+	}, http.StatusOK, &audit.ExportAuditLogsRequest{}, format.Filename("audit-logs"), format.ContentType())(c)
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"bufio"
+	"io"
 	"time"
 
 	"github.com/Sameer16536/psvault/internal/middleware"
@@ -68,6 +70,15 @@ func (h NoContentResponseHandler) AddAttributes(txn *newrelic.Transaction, resul
 	// http.status_code is already set by tracing middleware
 }
 
+// FileStream is a file written to the response as it is produced, for files
+// too large to hold in memory
+type FileStream func(w io.Writer) error
+
+// FileContent is what file handlers return: the whole file or a FileStream
+type FileContent interface {
+	[]byte | FileStream
+}
+
 // FileResponseHandler handles file responses
 type FileResponseHandler struct {
 	status      int
@@ -76,11 +87,51 @@ type FileResponseHandler struct {
 }
 
 func (h FileResponseHandler) Handle(c echo.Context, result interface{}) error {
+	if stream, ok := result.(FileStream); ok {
+		return h.stream(c, stream)
+	}
 	data := result.([]byte)
 	c.Response().Header().Set("Content-Disposition", "attachment; filename="+h.filename)
 	return c.Blob(h.status, h.contentType, data)
 }
 
+// stream writes the file through a buffer. The response is committed with the
+// first buffer full, so a stream failing before that is answered with an
+// error; after that the failure can only cut the file short.
+func (h FileResponseHandler) stream(c echo.Context, stream FileStream) error {
+	w := bufio.NewWriterSize(&fileStreamWriter{h: h, c: c}, 32<<10)
+	if err := stream(w); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !c.Response().Committed {
+		h.writeHeader(c)
+	}
+	c.Response().Flush()
+	return nil
+}
+
+func (h FileResponseHandler) writeHeader(c echo.Context) {
+	c.Response().Header().Set("Content-Disposition", "attachment; filename="+h.filename)
+	c.Response().Header().Set(echo.HeaderContentType, h.contentType)
+	c.Response().WriteHeader(h.status)
+}
+
+// fileStreamWriter writes to the response, committing it on the first write
+type fileStreamWriter struct {
+	h FileResponseHandler
+	c echo.Context
+}
+
+func (w *fileStreamWriter) Write(p []byte) (int, error) {
+	if !w.c.Response().Committed {
+		w.h.writeHeader(w.c)
+	}
+	return w.c.Response().Write(p)
+}
+
 func (h FileResponseHandler) GetOperation() string {
 	return "handler_file"
 }
@@ -220,9 +271,10 @@ func Handle[Req validation.Validatable, Res any](
 	}
 }
 
-func HandleFile[Req validation.Validatable](
+// HandleFile wraps a handler returning a file, whole or as a FileStream, as an attachment
+func HandleFile[Req validation.Validatable, Res FileContent](
 	h Handler,
-	handler HandlerFunc[Req, []byte],
+	handler HandlerFunc[Req, Res],
 	status int,
 	req Req,
 	filename string,
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+// BreachPaddingHeader asks for a padded range response, as in the Pwned Passwords API
+const BreachPaddingHeader = "Add-Padding"
+
+type BreachHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewBreachHandler(s *server.Server, services *service.Services) *BreachHandler {
+	return &BreachHandler{server: s, services: services}
+}
+
+// Range - GET /api/breach/range/:prefix
+func (h *BreachHandler) Range(c echo.Context) error {
+	prefix := c.Param("prefix")
+	pad := strings.EqualFold(c.Request().Header.Get(BreachPaddingHeader), "true")
+
+	entries, err := h.services.Breach.Range(c.Request().Context(), prefix, pad)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("prefix", prefix).Msg("failed to get breach range")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get breach range")
+	}
+
+	var body strings.Builder
+	for _, e := range entries {
+		body.WriteString(e.Suffix())
+		body.WriteByte(':')
+		body.WriteString(strconv.Itoa(e.Count))
+		body.WriteString("\r\n")
+	}
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, max-age=86400")
+	return c.String(http.StatusOK, body.String())
+}
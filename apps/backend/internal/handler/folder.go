@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/folder"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type FolderHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewFolderHandler(s *server.Server, services *service.Services) *FolderHandler {
+	return &FolderHandler{server: s, services: services}
+}
+
+// Create - POST /api/vaults/:id/folders
+func (h *FolderHandler) Create(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	vaultID := c.Param("id")
+
+	var req folder.CreateFolderRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Folder.Create(c.Request().Context(), userID, vaultID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to create folder")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create folder")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// Tree - GET /api/vaults/:id/folders
+func (h *FolderHandler) Tree(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	vaultID := c.Param("id")
+
+	var req folder.ListFoldersRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Folder.Tree(c.Request().Context(), userID, vaultID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to list folders")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list folders")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Rename - PATCH /api/folders/:id
+func (h *FolderHandler) Rename(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	folderID := c.Param("id")
+
+	var req folder.RenameFolderRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Folder.Rename(c.Request().Context(), userID, folderID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("folder_id", folderID).Msg("failed to rename folder")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to rename folder")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Move - POST /api/folders/:id/move
+func (h *FolderHandler) Move(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	folderID := c.Param("id")
+
+	var req folder.MoveFolderRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Folder.Move(c.Request().Context(), userID, folderID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("folder_id", folderID).Msg("failed to move folder")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to move folder")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Delete - DELETE /api/folders/:id?mode=up|trash
+func (h *FolderHandler) Delete(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	folderID := c.Param("id")
+
+	var req folder.DeleteFolderRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.services.Folder.Delete(c.Request().Context(), userID, folderID, &req); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("folder_id", folderID).Msg("failed to delete folder")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete folder")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// MoveSecret - PUT /api/secrets/:id/folder
+func (h *FolderHandler) MoveSecret(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	secretID := c.Param("id")
+
+	var req folder.MoveSecretRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.services.Folder.MoveSecret(c.Request().Context(), userID, secretID, &req); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to move secret")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to move secret")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -6,19 +6,37 @@ import (
 )
 
 type Handlers struct {
-	Health  *HealthHandler
-	OpenAPI *OpenAPIHandler
-	Vault   *VaultHandler
-	Secret  *SecretHandler
-	Device  *DeviceHandler
+	Health         *HealthHandler
+	OpenAPI        *OpenAPIHandler
+	Vault          *VaultHandler
+	Secret         *SecretHandler
+	Device         *DeviceHandler
+	ServiceAccount *ServiceAccountHandler
+	Audit          *AuditHandler
+	Share          *ShareHandler
+	Attachment     *AttachmentHandler
+	SecretType     *SecretTypeHandler
+	Folder         *FolderHandler
+	PasswordHealth *PasswordHealthHandler
+	Breach         *BreachHandler
+	Webhook        *WebhookHandler
 }
 
 func NewHandlers(s *server.Server, services *service.Services) *Handlers {
 	return &Handlers{
-		Health:  NewHealthHandler(s),
-		OpenAPI: NewOpenAPIHandler(s),
-		Vault:   NewVaultHandler(s, services),
-		Secret:  NewSecretHandler(s, services),
-		Device:  NewDeviceHandler(s, services),
+		Health:         NewHealthHandler(s),
+		OpenAPI:        NewOpenAPIHandler(s),
+		Vault:          NewVaultHandler(s, services),
+		Secret:         NewSecretHandler(s, services),
+		Device:         NewDeviceHandler(s, services),
+		ServiceAccount: NewServiceAccountHandler(s, services),
+		Audit:          NewAuditHandler(s, services),
+		Share:          NewShareHandler(s, services),
+		Attachment:     NewAttachmentHandler(s, services),
+		SecretType:     NewSecretTypeHandler(s, services),
+		Folder:         NewFolderHandler(s, services),
+		PasswordHealth: NewPasswordHealthHandler(s, services),
+		Breach:         NewBreachHandler(s, services),
+		Webhook:        NewWebhookHandler(s, services),
 	}
 }
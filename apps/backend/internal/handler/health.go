@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Sameer16536/psvault/internal/database"
 	"github.com/Sameer16536/psvault/internal/middleware"
 	"github.com/Sameer16536/psvault/internal/server"
 
@@ -67,6 +68,33 @@ func (h *HealthHandler) CheckHealth(c echo.Context) error {
 			"response_time": time.Since(dbStart).String(),
 		}
 		logger.Info().Dur("response_time", time.Since(dbStart)).Msg("database health check passed")
+
+		// Check the schema is migrated; a newer schema than this binary knows
+		// is fine, as during a rolling deploy
+		schema, err := database.SchemaStatus(ctx, h.server.DB.Pool)
+		switch {
+		case err != nil:
+			checks["migrations"] = map[string]interface{}{
+				"status": "unhealthy",
+				"error":  err.Error(),
+			}
+			isHealthy = false
+			logger.Error().Err(err).Msg("migration health check failed")
+		case schema.Pending():
+			checks["migrations"] = map[string]interface{}{
+				"status":  "pending",
+				"current": schema.Current,
+				"latest":  schema.Latest,
+			}
+			isHealthy = false
+			logger.Warn().Int32("current", schema.Current).Int32("latest", schema.Latest).Msg("database migrations pending")
+		default:
+			checks["migrations"] = map[string]interface{}{
+				"status":  "healthy",
+				"current": schema.Current,
+				"latest":  schema.Latest,
+			}
+		}
 	}
 
 	// Database connection metrics are automatically captured by New Relic nrpgx5 integration
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type PasswordHealthHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewPasswordHealthHandler(s *server.Server, services *service.Services) *PasswordHealthHandler {
+	return &PasswordHealthHandler{server: s, services: services}
+}
+
+// Set - PUT /api/secrets/:id/health
+func (h *PasswordHealthHandler) Set(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	secretID := c.Param("id")
+
+	var req secret.SetPasswordHealthRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.PasswordHealth.Set(c.Request().Context(), userID, secretID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to set password health")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to set password health")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Report - GET /api/vaults/:id/health
+func (h *PasswordHealthHandler) Report(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	vaultID := c.Param("id")
+
+	var req secret.VaultHealthRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.PasswordHealth.Report(c.Request().Context(), userID, vaultID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to build vault health report")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build vault health report")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/Sameer16536/psvault/internal/errs"
 	"github.com/Sameer16536/psvault/internal/model/secret"
 	"github.com/Sameer16536/psvault/internal/server"
 	"github.com/Sameer16536/psvault/internal/service"
@@ -33,6 +35,10 @@ func (h *SecretHandler) Create(c echo.Context) error {
 
 	result, err := h.services.Secret.Create(c.Request().Context(), userID, &req)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to create secret")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create secret")
 	}
@@ -47,6 +53,10 @@ func (h *SecretHandler) GetByID(c echo.Context) error {
 
 	result, err := h.services.Secret.GetByID(c.Request().Context(), userID, secretID)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to get secret")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get secret")
 	}
@@ -65,6 +75,10 @@ func (h *SecretHandler) List(c echo.Context) error {
 
 	result, err := h.services.Secret.List(c.Request().Context(), userID, vaultID)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to list secrets")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list secrets")
 	}
@@ -87,6 +101,10 @@ func (h *SecretHandler) Search(c echo.Context) error {
 
 	result, err := h.services.Secret.Search(c.Request().Context(), userID, &req)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to search secrets")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search secrets")
 	}
@@ -94,6 +112,32 @@ func (h *SecretHandler) Search(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
+// ListExpiring - GET /api/secrets/expiring
+func (h *SecretHandler) ListExpiring(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req secret.ListExpiringRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Secret.ListExpiring(c.Request().Context(), userID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to list expiring secrets")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list expiring secrets")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 // Update - PUT /api/secrets/:id
 func (h *SecretHandler) Update(c echo.Context) error {
 	userID := c.Get("user_id").(string)
@@ -110,6 +154,10 @@ func (h *SecretHandler) Update(c echo.Context) error {
 
 	result, err := h.services.Secret.Update(c.Request().Context(), userID, secretID, &req)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to update secret")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update secret")
 	}
@@ -117,15 +165,75 @@ func (h *SecretHandler) Update(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
+// Transfer - POST /api/secrets/:id/transfer
+func (h *SecretHandler) Transfer(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	secretID := c.Param("id")
+
+	var req secret.TransferSecretRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Secret.Transfer(c.Request().Context(), userID, secretID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to transfer secret")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to transfer secret")
+	}
+
+	if req.Mode == secret.TransferModeCopy {
+		return c.JSON(http.StatusCreated, result)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
 // Delete - DELETE /api/secrets/:id
 func (h *SecretHandler) Delete(c echo.Context) error {
 	userID := c.Get("user_id").(string)
 	secretID := c.Param("id")
 
 	if err := h.services.Secret.Delete(c.Request().Context(), userID, secretID); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("secret_id", secretID).Msg("failed to delete secret")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete secret")
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// Batch - POST /api/secrets/batch
+func (h *SecretHandler) Batch(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req secret.BatchRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Secret.Batch(c.Request().Context(), userID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to run secret batch")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to run secret batch")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
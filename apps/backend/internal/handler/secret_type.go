@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type SecretTypeHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewSecretTypeHandler(s *server.Server, services *service.Services) *SecretTypeHandler {
+	return &SecretTypeHandler{server: s, services: services}
+}
+
+// List - GET /api/secret-types
+func (h *SecretTypeHandler) List(c echo.Context) error {
+	result, err := h.services.SecretType.List(c.Request().Context())
+	if err != nil {
+		h.server.Logger.Error().Err(err).Msg("failed to list secret types")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list secret types")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
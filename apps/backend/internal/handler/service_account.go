@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/serviceaccount"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type ServiceAccountHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewServiceAccountHandler(s *server.Server, services *service.Services) *ServiceAccountHandler {
+	return &ServiceAccountHandler{server: s, services: services}
+}
+
+// Create - POST /api/service-accounts
+func (h *ServiceAccountHandler) Create(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req serviceaccount.CreateServiceAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.ServiceAccount.Create(c.Request().Context(), userID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to create service account")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create service account")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// List - GET /api/service-accounts
+func (h *ServiceAccountHandler) List(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	result, err := h.services.ServiceAccount.List(c.Request().Context())
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to list service accounts")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list service accounts")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Disable - DELETE /api/service-accounts/:id
+func (h *ServiceAccountHandler) Disable(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	serviceAccountID := c.Param("id")
+
+	if err := h.services.ServiceAccount.Disable(c.Request().Context(), serviceAccountID); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("service_account_id", serviceAccountID).Msg("failed to disable service account")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to disable service account")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Token - POST /api/auth/token
+func (h *ServiceAccountHandler) Token(c echo.Context) error {
+	var req serviceaccount.TokenRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.ServiceAccount.IssueToken(c.Request().Context(), &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("client_id", req.ClientID).Msg("failed to issue service account token")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to issue token")
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, result)
+}
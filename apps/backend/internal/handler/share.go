@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/share"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// SharePasswordHeader carries the optional access password of a share link
+const SharePasswordHeader = "X-Share-Password"
+
+type ShareHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewShareHandler(s *server.Server, services *service.Services) *ShareHandler {
+	return &ShareHandler{server: s, services: services}
+}
+
+// Create - POST /api/shares
+func (h *ShareHandler) Create(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req share.CreateShareRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Share.Create(c.Request().Context(), userID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to create share")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create share")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// List - GET /api/shares
+func (h *ShareHandler) List(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	result, err := h.services.Share.List(c.Request().Context(), userID)
+	if err != nil {
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to list shares")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list shares")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Revoke - DELETE /api/shares/:id
+func (h *ShareHandler) Revoke(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	shareID := c.Param("id")
+	if _, err := uuid.Parse(shareID); err != nil {
+		return errs.NewNotFoundError("Share not found", false, nil)
+	}
+
+	if err := h.services.Share.Revoke(c.Request().Context(), userID, shareID); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("share_id", shareID).Msg("failed to revoke share")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke share")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Consume - GET /api/shares/:id (unauthenticated, uses up one view)
+func (h *ShareHandler) Consume(c echo.Context) error {
+	shareID := c.Param("id")
+	// Malformed IDs are just unknown shares, not a query error
+	if _, err := uuid.Parse(shareID); err != nil {
+		return errs.NewNotFoundError("Share not found or no longer available", false, nil)
+	}
+
+	result, err := h.services.Share.Consume(
+		c.Request().Context(),
+		shareID,
+		c.Request().Header.Get(SharePasswordHeader),
+		c.RealIP(),
+		c.Request().UserAgent(),
+	)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("share_id", shareID).Msg("failed to consume share")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open share")
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/share"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/service"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShareContext(method, id string, header http.Header) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, "/api/shares/"+url.PathEscape(id), nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	return c, rec
+}
+
+func requireHTTPStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, status, httpErr.Status)
+}
+
+// Test: Malformed share IDs are unknown shares, rejected before reaching the database
+func TestShareHandler_MalformedID(t *testing.T) {
+	h := NewShareHandler(nil, nil)
+
+	c, _ := newShareContext(http.MethodGet, "not-a-uuid", nil)
+	requireHTTPStatus(t, h.Consume(c), http.StatusNotFound)
+
+	c, _ = newShareContext(http.MethodDelete, "1'; DROP TABLE shares; --", nil)
+	c.Set("user_id", "user")
+	requireHTTPStatus(t, h.Revoke(c), http.StatusNotFound)
+}
+
+// Test: The share password is read from its header and the content is not cached
+func TestShareHandler_Consume(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	services, err := service.NewServices(srv, repos)
+	require.NoError(t, err)
+	h := NewShareHandler(srv, services)
+
+	ctx := repository.Unscoped(context.Background())
+	ownerID, err := repos.User.Resolve(ctx, "user_owner")
+	require.NoError(t, err)
+	ownerCtx := actor.WithActor(ctx, &actor.Actor{ID: ownerID, ExternalID: "user_owner", Type: actor.TypeUser})
+	created, err := services.Share.Create(ownerCtx, ownerID, &share.CreateShareRequest{
+		EncryptedPayload: []byte("payload"),
+		Password:         tt.Ptr("hunter2"),
+	})
+	require.NoError(t, err)
+
+	c, _ := newShareContext(http.MethodGet, created.ID, http.Header{SharePasswordHeader: {"wrong"}})
+	requireHTTPStatus(t, h.Consume(c), http.StatusUnauthorized)
+
+	c, rec := newShareContext(http.MethodGet, created.ID, http.Header{SharePasswordHeader: {"hunter2"}})
+	require.NoError(t, h.Consume(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	var content share.ShareContentResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &content))
+	assert.Equal(t, []byte("payload"), content.EncryptedPayload)
+	assert.Equal(t, 0, content.ViewsRemaining)
+
+	// Unknown and used-up shares look the same
+	c, _ = newShareContext(http.MethodGet, created.ID, http.Header{SharePasswordHeader: {"hunter2"}})
+	requireHTTPStatus(t, h.Consume(c), http.StatusNotFound)
+	c, _ = newShareContext(http.MethodGet, "00000000-0000-0000-0000-000000000000", nil)
+	requireHTTPStatus(t, h.Consume(c), http.StatusNotFound)
+}
@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/Sameer16536/psvault/internal/errs"
 	"github.com/Sameer16536/psvault/internal/model/vault"
 	"github.com/Sameer16536/psvault/internal/server"
 	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -34,6 +37,10 @@ func (h *VaultHandler) Create(c echo.Context) error {
 
 	result, err := h.services.Vault.Create(c.Request().Context(), userID, &req)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to create vault")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create vault")
 	}
@@ -61,6 +68,10 @@ func (h *VaultHandler) GetByID(c echo.Context) error {
 
 	result, err := h.services.Vault.GetByID(c.Request().Context(), userID, vaultID)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to get vault")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get vault")
 	}
@@ -88,6 +99,10 @@ func (h *VaultHandler) Update(c echo.Context) error {
 
 	result, err := h.services.Vault.Update(c.Request().Context(), userID, vaultID, &req)
 	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to update vault")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update vault")
 	}
@@ -101,9 +116,79 @@ func (h *VaultHandler) Delete(c echo.Context) error {
 	vaultID := c.Param("id")
 
 	if err := h.services.Vault.Delete(c.Request().Context(), userID, vaultID); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
 		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to delete vault")
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete vault")
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// AddMember - POST /api/vaults/:id/members
+func (h *VaultHandler) AddMember(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	vaultID := c.Param("id")
+
+	var req vault.AddMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Vault.AddMember(c.Request().Context(), userID, vaultID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to add vault member")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add vault member")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// ListMembers - GET /api/vaults/:id/members
+func (h *VaultHandler) ListMembers(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	vaultID := c.Param("id")
+
+	result, err := h.services.Vault.ListMembers(c.Request().Context(), userID, vaultID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Msg("failed to list vault members")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list vault members")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// RemoveMember - DELETE /api/vaults/:id/members/:memberId
+func (h *VaultHandler) RemoveMember(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	vaultID := c.Param("id")
+	memberID := c.Param("memberId")
+	if _, err := uuid.Parse(memberID); err != nil {
+		return errs.NewNotFoundError("Vault member not found", false, nil)
+	}
+
+	if err := h.services.Vault.RemoveMember(c.Request().Context(), userID, vaultID, memberID); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("vault_id", vaultID).Str("member_id", memberID).Msg("failed to remove vault member")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to remove vault member")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/webhook"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	server   *server.Server
+	services *service.Services
+}
+
+func NewWebhookHandler(s *server.Server, services *service.Services) *WebhookHandler {
+	return &WebhookHandler{server: s, services: services}
+}
+
+// Create - POST /api/webhooks
+func (h *WebhookHandler) Create(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	var req webhook.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Webhook.Create(c.Request().Context(), userID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to create webhook")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create webhook")
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// List - GET /api/webhooks
+func (h *WebhookHandler) List(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+
+	result, err := h.services.Webhook.List(c.Request().Context(), userID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Msg("failed to list webhooks")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list webhooks")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// GetByID - GET /api/webhooks/:id
+func (h *WebhookHandler) GetByID(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	webhookID := c.Param("id")
+
+	result, err := h.services.Webhook.GetByID(c.Request().Context(), userID, webhookID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("webhook_id", webhookID).Msg("failed to get webhook")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get webhook")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Update - PATCH /api/webhooks/:id
+func (h *WebhookHandler) Update(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	webhookID := c.Param("id")
+
+	var req webhook.UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Webhook.Update(c.Request().Context(), userID, webhookID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("webhook_id", webhookID).Msg("failed to update webhook")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update webhook")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Delete - DELETE /api/webhooks/:id
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	webhookID := c.Param("id")
+
+	if err := h.services.Webhook.Delete(c.Request().Context(), userID, webhookID); err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("webhook_id", webhookID).Msg("failed to delete webhook")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete webhook")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries - GET /api/webhooks/:id/deliveries
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	webhookID := c.Param("id")
+
+	var req webhook.ListDeliveriesRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	result, err := h.services.Webhook.ListDeliveries(c.Request().Context(), userID, webhookID, &req)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("webhook_id", webhookID).Msg("failed to list webhook deliveries")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list webhook deliveries")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// Redeliver - POST /api/webhooks/:id/deliveries/:deliveryId/redeliver
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	userID := c.Get("user_id").(string)
+	webhookID := c.Param("id")
+	deliveryID := c.Param("deliveryId")
+
+	result, err := h.services.Webhook.Redeliver(c.Request().Context(), userID, webhookID, deliveryID)
+	if err != nil {
+		var httpErr *errs.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		h.server.Logger.Error().Err(err).Str("user_id", userID).Str("webhook_id", webhookID).Str("delivery_id", deliveryID).Msg("failed to redeliver webhook")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to redeliver webhook")
+	}
+
+	return c.JSON(http.StatusAccepted, result)
+}
@@ -0,0 +1,54 @@
+// Package actor carries the authenticated principal of a request through
+// context.Context so services and repositories can attribute their work.
+package actor
+
+import "context"
+
+type Type string
+
+const (
+	TypeUser           Type = "user"
+	TypeServiceAccount Type = "service_account"
+)
+
+// OrgRoleAdmin is the Clerk role key for organization administrators
+const OrgRoleAdmin = "org:admin"
+
+// Actor describes who is performing a request and from where.
+// ID is the internal users.id or service_accounts.id; ExternalID is the Clerk
+// user ID of users. For service accounts OrgID is the owning organization; for
+// users it is the active organization from the session claims, if any.
+type Actor struct {
+	ID             string
+	ExternalID     string
+	Type           Type
+	OrgID          string
+	OrgRole        string
+	OrgPermissions []string
+	IPAddress      string
+	UserAgent      string
+}
+
+// IsOrgAdmin reports whether the actor administers its active organization.
+func (a *Actor) IsOrgAdmin() bool {
+	return a.Type == TypeUser && a.OrgID != "" && a.OrgRole == OrgRoleAdmin
+}
+
+type contextKey struct{}
+
+// WithActor returns a copy of ctx carrying a.
+func WithActor(ctx context.Context, a *Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, a)
+}
+
+// FromContext returns the actor stored in ctx, if any.
+func FromContext(ctx context.Context) (*Actor, bool) {
+	a, ok := ctx.Value(contextKey{}).(*Actor)
+	return a, ok && a != nil
+}
+
+// IsServiceAccount reports whether the request in ctx was made by a service account.
+func IsServiceAccount(ctx context.Context) bool {
+	a, ok := FromContext(ctx)
+	return ok && a.Type == TypeServiceAccount
+}
@@ -0,0 +1,117 @@
+// Package breach reads breached-password corpora for the k-anonymity range API.
+//
+// A corpus is a list of SHA-1 password hashes in upper- or lower-case hex,
+// one per line and sorted by hash, optionally followed by ":count" as in the
+// Pwned Passwords "ordered by hash" download:
+//
+//	000000005AD76BD555C1D6D771DE417A4B87E4B4:10
+//	00000000A8DAE4228F821FB418F59826079BF368:4
+//
+// Clients send the first PrefixLength characters of a hash and receive the
+// suffixes of every corpus entry sharing that prefix.
+package breach
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// HashLength is the length of a hex SHA-1 hash
+	HashLength = 40
+	// PrefixLength is the number of hash characters a client reveals
+	PrefixLength = 5
+)
+
+// Entry is a breached password hash and how often it was seen
+type Entry struct {
+	// Hash is the upper-case hex SHA-1 hash
+	Hash  string
+	Count int
+}
+
+// Prefix returns the range an entry belongs to
+func (e Entry) Prefix() string {
+	return e.Hash[:PrefixLength]
+}
+
+// Suffix returns the part of the hash the range API returns
+func (e Entry) Suffix() string {
+	return e.Hash[PrefixLength:]
+}
+
+// ValidPrefix reports whether p is PrefixLength hex characters
+func ValidPrefix(p string) bool {
+	return len(p) == PrefixLength && isHex(p)
+}
+
+// ParseLine parses "HASH" or "HASH:COUNT"; a missing count is 1
+func ParseLine(line string) (Entry, error) {
+	hash, countText, hasCount := strings.Cut(strings.TrimSpace(line), ":")
+	if len(hash) != HashLength || !isHex(hash) {
+		return Entry{}, errors.New("not a hex SHA-1 hash")
+	}
+	e := Entry{Hash: strings.ToUpper(hash), Count: 1}
+	if hasCount {
+		count, err := strconv.Atoi(countText)
+		if err != nil || count < 0 {
+			return Entry{}, fmt.Errorf("invalid count %q", countText)
+		}
+		e.Count = count
+	}
+	return e, nil
+}
+
+// Reader reads the entries of a corpus, rejecting lines that are out of order.
+// Blank lines and lines starting with "#" are skipped.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+	last    string
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scanner: bufio.NewScanner(r)}
+}
+
+// Next returns the next entry, or io.EOF at the end of the corpus
+func (r *Reader) Next() (Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		text := strings.TrimSpace(r.scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		e, err := ParseLine(text)
+		if err != nil {
+			return Entry{}, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		if e.Hash <= r.last {
+			return Entry{}, fmt.Errorf("line %d: hashes must be sorted and unique", r.line)
+		}
+		r.last = e.Hash
+		return e, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	return Entry{}, io.EOF
+}
+
+// Line returns the number of lines read so far
+func (r *Reader) Line() int {
+	return r.line
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
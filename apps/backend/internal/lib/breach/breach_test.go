@@ -0,0 +1,57 @@
+package breach
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	e, err := ParseLine("000000005ad76bd555c1d6d771de417a4b87e4b4:10")
+	require.NoError(t, err)
+	assert.Equal(t, Entry{Hash: "000000005AD76BD555C1D6D771DE417A4B87E4B4", Count: 10}, e)
+	assert.Equal(t, "00000", e.Prefix())
+	assert.Equal(t, "0005AD76BD555C1D6D771DE417A4B87E4B4", e.Suffix())
+
+	e, err = ParseLine("00000000A8DAE4228F821FB418F59826079BF368")
+	require.NoError(t, err)
+	assert.Equal(t, 1, e.Count)
+
+	for _, line := range []string{"", "abc", "000000005AD76BD555C1D6D771DE417A4B87E4BZ", "000000005AD76BD555C1D6D771DE417A4B87E4B4:-1", "000000005AD76BD555C1D6D771DE417A4B87E4B4:x"} {
+		_, err := ParseLine(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestReader(t *testing.T) {
+	corpus := "# comment\n000000005AD76BD555C1D6D771DE417A4B87E4B4:10\n\n00000000A8DAE4228F821FB418F59826079BF368:4\n"
+	r := NewReader(strings.NewReader(corpus))
+	var hashes []string
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		hashes = append(hashes, e.Hash)
+	}
+	assert.Len(t, hashes, 2)
+	assert.Equal(t, 4, r.Line())
+
+	unsorted := "00000000A8DAE4228F821FB418F59826079BF368\n000000005AD76BD555C1D6D771DE417A4B87E4B4\n"
+	r = NewReader(strings.NewReader(unsorted))
+	_, err := r.Next()
+	require.NoError(t, err)
+	_, err = r.Next()
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestValidPrefix(t *testing.T) {
+	assert.True(t, ValidPrefix("21BD1"))
+	assert.True(t, ValidPrefix("21bd1"))
+	assert.False(t, ValidPrefix("21BD"))
+	assert.False(t, ValidPrefix("21BDG"))
+}
@@ -12,3 +12,41 @@ func (c *Client) SendWelcomeEmail(to, firstName string) error {
 		data,
 	)
 }
+
+func (c *Client) SendSecurityAlertEmail(to, reason, ipAddress, userAgent, detectedAt, response string) error {
+	data := map[string]string{
+		"Reason":     reason,
+		"IPAddress":  ipAddress,
+		"UserAgent":  userAgent,
+		"DetectedAt": detectedAt,
+		"Response":   response,
+	}
+
+	return c.SendEmail(
+		to,
+		"Unusual activity on your vault",
+		TemplateSecurityAlert,
+		data,
+	)
+}
+
+// ExpiryItem is one secret listed in an expiry digest
+type ExpiryItem struct {
+	Title     string `json:"title"`
+	VaultName string `json:"vault_name"`
+	Status    string `json:"status"`
+	Date      string `json:"date"`
+}
+
+func (c *Client) SendExpiryDigestEmail(to string, items []ExpiryItem) error {
+	data := map[string]any{
+		"Items": items,
+	}
+
+	return c.SendEmail(
+		to,
+		"Secrets that need your attention",
+		TemplateExpiryDigest,
+		data,
+	)
+}
@@ -1,7 +1,20 @@
 package email
 
-var PreviewData = map[string]map[string]string{
+var PreviewData = map[string]map[string]any{
 	"welcome": {
 		"UserFirstName": "John",
 	},
+	"security-alert": {
+		"Reason":     "an unusually high number of secrets were revealed",
+		"IPAddress":  "203.0.113.7",
+		"UserAgent":  "Mozilla/5.0",
+		"DetectedAt": "2026-02-07 20:00 UTC",
+		"Response":   "No further action was taken.",
+	},
+	"expiry-digest": {
+		"Items": []ExpiryItem{
+			{Title: "Production database", VaultName: "Infrastructure", Status: "Rotation due", Date: "2026-03-01"},
+			{Title: "Staging API key", VaultName: "Infrastructure", Status: "Due soon", Date: "2026-03-12"},
+		},
+	},
 }
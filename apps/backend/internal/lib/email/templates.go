@@ -3,5 +3,7 @@ package email
 type Template string
 
 const (
-	TemplateWelcome Template = "welcome"
+	TemplateWelcome       Template = "welcome"
+	TemplateSecurityAlert Template = "security-alert"
+	TemplateExpiryDigest  Template = "expiry-digest"
 )
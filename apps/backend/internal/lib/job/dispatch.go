@@ -0,0 +1,67 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// taskOptions are the options each task type is enqueued with by default
+var taskOptions = map[string][]asynq.Option{
+	TaskWelcome:           {asynq.MaxRetry(3), asynq.Queue("default"), asynq.Timeout(30 * time.Second)},
+	TaskSecurityAlert:     {asynq.MaxRetry(3), asynq.Queue("critical"), asynq.Timeout(30 * time.Second)},
+	TaskExpiryDigestEmail: {asynq.MaxRetry(3), asynq.Queue("low"), asynq.Timeout(30 * time.Second)},
+	TaskWebhookFanout:     {asynq.MaxRetry(5), asynq.Queue("default"), asynq.Timeout(time.Minute)},
+}
+
+// retryDelays replace asynq's default backoff for some task types
+var retryDelays = map[string]func(n int) time.Duration{
+	TaskWebhookDeliver: WebhookRetryDelay,
+}
+
+func retryDelay(n int, err error, t *asynq.Task) time.Duration {
+	if delay, ok := retryDelays[t.Type()]; ok {
+		return delay(n)
+	}
+	return asynq.DefaultRetryDelayFunc(n, err, t)
+}
+
+// ExponentialBackoff - Delay doubling from base with every retry n (0 for the first), capped at max
+func ExponentialBackoff(base, max time.Duration, n int) time.Duration {
+	delay := base
+	for i := 0; i < n; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
+
+// defaultTaskOptions apply to task types without options of their own
+var defaultTaskOptions = []asynq.Option{asynq.MaxRetry(3), asynq.Queue("default"), asynq.Timeout(time.Minute)}
+
+// NewTask builds a task of taskType with payload marshalled as JSON and the
+// options of its type; opts override them
+func NewTask(taskType string, payload any, opts ...asynq.Option) (*asynq.Task, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	defaults, ok := taskOptions[taskType]
+	if !ok {
+		defaults = defaultTaskOptions
+	}
+	return asynq.NewTask(taskType, b, append(append([]asynq.Option{}, defaults...), opts...)...), nil
+}
+
+// Dispatch enqueues a task of any type, built as by NewTask
+func (j *JobService) Dispatch(ctx context.Context, taskType string, payload any, opts ...asynq.Option) (*asynq.TaskInfo, error) {
+	task, err := NewTask(taskType, payload, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return j.Client.EnqueueContext(ctx, task)
+}
@@ -1,14 +1,14 @@
 package job
 
 import (
-	"encoding/json"
-	"time"
-
+	"github.com/Sameer16536/psvault/internal/lib/email"
 	"github.com/hibiken/asynq"
 )
 
 const (
-	TaskWelcome = "email:welcome"
+	TaskWelcome           = "email:welcome"
+	TaskSecurityAlert     = "email:security_alert"
+	TaskExpiryDigestEmail = "email:expiry_digest"
 )
 
 type WelcomeEmailPayload struct {
@@ -17,16 +17,34 @@ type WelcomeEmailPayload struct {
 }
 
 func NewWelcomeEmailTask(to, firstName string) (*asynq.Task, error) {
-	payload, err := json.Marshal(WelcomeEmailPayload{
+	return NewTask(TaskWelcome, WelcomeEmailPayload{
 		To:        to,
 		FirstName: firstName,
 	})
-	if err != nil {
-		return nil, err
-	}
-
-	return asynq.NewTask(TaskWelcome, payload,
-		asynq.MaxRetry(3),
-		asynq.Queue("default"),
-		asynq.Timeout(30*time.Second)), nil
+}
+
+// SecurityAlertEmailPayload addresses the alert by Clerk user ID; the
+// recipient's primary email is resolved when the task runs.
+type SecurityAlertEmailPayload struct {
+	UserID     string `json:"user_id"`
+	Reason     string `json:"reason"`
+	IPAddress  string `json:"ip_address"`
+	UserAgent  string `json:"user_agent"`
+	DetectedAt string `json:"detected_at"`
+	Response   string `json:"response"`
+}
+
+func NewSecurityAlertEmailTask(p SecurityAlertEmailPayload) (*asynq.Task, error) {
+	return NewTask(TaskSecurityAlert, p)
+}
+
+// ExpiryDigestEmailPayload lists a user's secrets that need attention; like
+// security alerts, the recipient is resolved when the task runs
+type ExpiryDigestEmailPayload struct {
+	UserID string             `json:"user_id"`
+	Items  []email.ExpiryItem `json:"items"`
+}
+
+func NewExpiryDigestEmailTask(p ExpiryDigestEmailPayload) (*asynq.Task, error) {
+	return NewTask(TaskExpiryDigestEmail, p)
 }
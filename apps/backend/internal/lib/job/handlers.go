@@ -7,6 +7,7 @@ import (
 
 	"github.com/Sameer16536/psvault/internal/config"
 	"github.com/Sameer16536/psvault/internal/lib/email"
+	"github.com/clerk/clerk-sdk-go/v2/user"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
 )
@@ -47,3 +48,97 @@ func (j *JobService) handleWelcomeEmailTask(ctx context.Context, t *asynq.Task)
 		Msg("Successfully sent welcome email")
 	return nil
 }
+
+func (j *JobService) handleSecurityAlertEmailTask(ctx context.Context, t *asynq.Task) error {
+	var p SecurityAlertEmailPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal security alert email payload: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "security_alert").
+		Str("user_id", p.UserID).
+		Msg("Processing security alert email task")
+
+	to, err := primaryEmailAddress(ctx, p.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to look up security alert recipient: %w", err)
+	}
+	if to == "" {
+		j.logger.Warn().
+			Str("type", "security_alert").
+			Str("user_id", p.UserID).
+			Msg("User has no primary email address, skipping security alert")
+		return nil
+	}
+
+	err = emailClient.SendSecurityAlertEmail(to, p.Reason, p.IPAddress, p.UserAgent, p.DetectedAt, p.Response)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "security_alert").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to send security alert email")
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "security_alert").
+		Str("user_id", p.UserID).
+		Msg("Successfully sent security alert email")
+	return nil
+}
+
+func (j *JobService) handleExpiryDigestEmailTask(ctx context.Context, t *asynq.Task) error {
+	var p ExpiryDigestEmailPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal expiry digest email payload: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "expiry_digest").
+		Str("user_id", p.UserID).
+		Int("items", len(p.Items)).
+		Msg("Processing expiry digest email task")
+
+	to, err := primaryEmailAddress(ctx, p.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to look up expiry digest recipient: %w", err)
+	}
+	if to == "" {
+		j.logger.Warn().
+			Str("type", "expiry_digest").
+			Str("user_id", p.UserID).
+			Msg("User has no primary email address, skipping expiry digest")
+		return nil
+	}
+
+	if err := emailClient.SendExpiryDigestEmail(to, p.Items); err != nil {
+		j.logger.Error().
+			Str("type", "expiry_digest").
+			Str("user_id", p.UserID).
+			Err(err).
+			Msg("Failed to send expiry digest email")
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "expiry_digest").
+		Str("user_id", p.UserID).
+		Msg("Successfully sent expiry digest email")
+	return nil
+}
+
+// primaryEmailAddress resolves a Clerk user's primary email address
+func primaryEmailAddress(ctx context.Context, userID string) (string, error) {
+	u, err := user.Get(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	for _, e := range u.EmailAddresses {
+		if u.PrimaryEmailAddressID != nil && e.ID == *u.PrimaryEmailAddressID {
+			return e.EmailAddress, nil
+		}
+	}
+	return "", nil
+}
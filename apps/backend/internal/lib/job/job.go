@@ -1,58 +1,98 @@
 package job
 
 import (
+	"context"
+
 	"github.com/Sameer16536/psvault/internal/config"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
 )
 
+// QueuePriorities weights the queues tasks are enqueued on
+var QueuePriorities = map[string]int{
+	"critical": 6, // Higher priority queue for important emails
+	"default":  3, // Default priority for most emails
+	"low":      1, // Lower priority for non-urgent emails
+}
+
 type JobService struct {
-	Client *asynq.Client
-	server *asynq.Server
-	logger *zerolog.Logger
+	Client    *asynq.Client
+	redis     asynq.RedisClientOpt
+	server    *asynq.Server
+	scheduler *asynq.Scheduler
+	mux       *asynq.ServeMux
+	logger    *zerolog.Logger
 }
 
 func NewJobService(logger *zerolog.Logger, cfg *config.Config) *JobService {
 	redisAddr := cfg.Redis.Address
 
-	client := asynq.NewClient(asynq.RedisClientOpt{
-		Addr: redisAddr,
-	})
+	redisOpt := asynq.RedisClientOpt{Addr: redisAddr}
+
+	client := asynq.NewClient(redisOpt)
 
 	server := asynq.NewServer(
-		asynq.RedisClientOpt{Addr: redisAddr},
+		redisOpt,
 		asynq.Config{
-			Concurrency: 10,
-			Queues: map[string]int{
-				"critical": 6, // Higher priority queue for important emails
-				"default":  3, // Default priority for most emails
-				"low":      1, // Lower priority for non-urgent emails
-			},
+			Concurrency:    10,
+			Queues:         QueuePriorities,
+			RetryDelayFunc: retryDelay,
 		},
 	)
 
+	scheduler := asynq.NewScheduler(redisOpt, nil)
+
 	return &JobService{
-		Client: client,
-		server: server,
-		logger: logger,
+		Client:    client,
+		redis:     redisOpt,
+		server:    server,
+		scheduler: scheduler,
+		mux:       asynq.NewServeMux(),
+		logger:    logger,
 	}
 }
 
+// HandleFunc registers a task handler. Handlers may be registered after Start,
+// which lets services that depend on repositories add their own tasks.
+func (j *JobService) HandleFunc(pattern string, handler func(context.Context, *asynq.Task) error) {
+	j.mux.HandleFunc(pattern, handler)
+}
+
+// Schedule enqueues task periodically according to cronspec (e.g. "@every 15m").
+// Every instance runs a scheduler, so periodic tasks should be enqueued with
+// asynq.Unique to avoid duplicate runs.
+func (j *JobService) Schedule(cronspec string, task *asynq.Task, opts ...asynq.Option) error {
+	_, err := j.scheduler.Register(cronspec, task, opts...)
+	return err
+}
+
 func (j *JobService) Start() error {
 	// Register task handlers
-	mux := asynq.NewServeMux()
-	mux.HandleFunc(TaskWelcome, j.handleWelcomeEmailTask)
+	j.mux.HandleFunc(TaskWelcome, j.handleWelcomeEmailTask)
+	j.mux.HandleFunc(TaskSecurityAlert, j.handleSecurityAlertEmailTask)
+	j.mux.HandleFunc(TaskExpiryDigestEmail, j.handleExpiryDigestEmailTask)
 
 	j.logger.Info().Msg("Starting background job server")
-	if err := j.server.Start(mux); err != nil {
+	if err := j.server.Start(j.mux); err != nil {
+		return err
+	}
+
+	j.logger.Info().Msg("Starting periodic task scheduler")
+	if err := j.scheduler.Start(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// Inspector returns an inspector for the job queues; close it after use
+func (j *JobService) Inspector() *asynq.Inspector {
+	return asynq.NewInspector(j.redis)
+}
+
 func (j *JobService) Stop() {
 	j.logger.Info().Msg("Stopping background job server")
+	j.scheduler.Shutdown()
 	j.server.Shutdown()
 	j.Client.Close()
 }
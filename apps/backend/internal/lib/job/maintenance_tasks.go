@@ -0,0 +1,53 @@
+package job
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskShareSweep   = "maintenance:share_sweep"
+	TaskExpiryDigest = "maintenance:expiry_digest"
+	TaskOutboxRelay  = "maintenance:outbox_relay"
+	TaskAuditForward = "maintenance:audit_forward"
+)
+
+// ShareSweepInterval is how often expired share links are purged
+const ShareSweepInterval = 15 * time.Minute
+
+func NewShareSweepTask() *asynq.Task {
+	return asynq.NewTask(TaskShareSweep, nil,
+		asynq.MaxRetry(1),
+		asynq.Queue("low"),
+		asynq.Timeout(5*time.Minute),
+		asynq.Unique(ShareSweepInterval-time.Minute))
+}
+
+// NewExpiryDigestTask collects expiring and overdue secrets and enqueues one
+// digest email per vault owner
+func NewExpiryDigestTask() *asynq.Task {
+	return asynq.NewTask(TaskExpiryDigest, nil,
+		asynq.MaxRetry(1),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.Unique(12*time.Hour))
+}
+
+// NewOutboxRelayTask publishes pending outbox messages; interval is how often it is scheduled
+func NewOutboxRelayTask(interval time.Duration) *asynq.Task {
+	return asynq.NewTask(TaskOutboxRelay, nil,
+		asynq.MaxRetry(0),
+		asynq.Queue("critical"),
+		asynq.Timeout(time.Minute),
+		asynq.Unique(interval))
+}
+
+// NewAuditForwardTask sends new audit entries to the SIEM; interval is how often it is scheduled
+func NewAuditForwardTask(interval time.Duration) *asynq.Task {
+	return asynq.NewTask(TaskAuditForward, nil,
+		asynq.MaxRetry(0),
+		asynq.Queue("low"),
+		asynq.Timeout(5*time.Minute),
+		asynq.Unique(interval))
+}
@@ -0,0 +1,19 @@
+package job
+
+import "time"
+
+const (
+	// TaskWebhookFanout creates a delivery for each webhook subscribed to an audit entry
+	TaskWebhookFanout = "webhook:fanout"
+	// TaskWebhookDeliver POSTs one delivery, retried with WebhookRetryDelay
+	TaskWebhookDeliver = "webhook:deliver"
+)
+
+type WebhookDeliverPayload struct {
+	DeliveryID string `json:"delivery_id"`
+}
+
+// WebhookRetryDelay - Backoff before retry n+1 of a webhook delivery: 30s, 1m, 2m, ... up to 6h
+func WebhookRetryDelay(n int) time.Duration {
+	return ExponentialBackoff(30*time.Second, 6*time.Hour, n)
+}
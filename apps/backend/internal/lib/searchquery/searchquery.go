@@ -0,0 +1,295 @@
+// Package searchquery parses the secret search syntax used by GET /api/secrets/search.
+//
+// Terms separated by spaces must all match. OR between terms matches either
+// side, NOT or a leading "-" excludes a term, and parentheses group. Terms may
+// be quoted phrases and may be qualified with "field:value":
+//
+//	type:api_key aws env:prod -tag:legacy
+//	(github OR gitlab) AND NOT tag:"old accounts"
+//
+// Operators are only recognised in upper case; "or" is an ordinary word.
+package searchquery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxTerms bounds the size of a parsed query
+	MaxTerms = 32
+	// MaxDepth bounds nesting of parentheses and NOT
+	MaxDepth = 16
+)
+
+// ErrEmpty is returned for a query with no terms
+var ErrEmpty = errors.New("search query is empty")
+
+// Node is a node of a parsed query
+type Node interface {
+	node()
+}
+
+// And matches when every child matches
+type And struct {
+	Children []Node
+}
+
+// Or matches when any child matches
+type Or struct {
+	Children []Node
+}
+
+// Not matches when its child does not
+type Not struct {
+	Child Node
+}
+
+// Term matches a word or phrase, optionally restricted to Field
+type Term struct {
+	// Field is the lower-cased qualifier, or "" for free text
+	Field string
+	Value string
+	// Phrase is set for quoted values
+	Phrase bool
+}
+
+func (And) node()  {}
+func (Or) node()   {}
+func (Not) node()  {}
+func (Term) node() {}
+
+// Parse parses a search query into a tree of And, Or, Not and Term nodes
+func Parse(input string) (Node, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, ErrEmpty
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return n, nil
+}
+
+type tokenKind int
+
+const (
+	tokenTerm tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	term Term
+}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")"})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			// A leading dash negates the following term or group
+			tokens = append(tokens, token{kind: tokenNot, text: "-"})
+			i++
+		case r == '"':
+			value, next, err := readQuoted(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenTerm, text: value, term: Term{Value: value, Phrase: true}})
+			i = next
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"`, runes[i]) {
+				if runes[i] == ':' && i > start {
+					break
+				}
+				i++
+			}
+			word := string(runes[start:i])
+
+			// field:value or field:"quoted value"
+			if i < len(runes) && runes[i] == ':' {
+				field := strings.ToLower(word)
+				i++
+				if i < len(runes) && runes[i] == '"' {
+					value, next, err := readQuoted(runes, i)
+					if err != nil {
+						return nil, err
+					}
+					tokens = append(tokens, token{kind: tokenTerm, text: word, term: Term{Field: field, Value: value, Phrase: true}})
+					i = next
+					continue
+				}
+				start = i
+				for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"`, runes[i]) {
+					i++
+				}
+				value := string(runes[start:i])
+				if value == "" {
+					return nil, fmt.Errorf("missing value for %q", word+":")
+				}
+				// Treat URLs as plain words rather than a "https" field
+				if strings.HasPrefix(value, "//") {
+					tokens = append(tokens, token{kind: tokenTerm, text: word + ":" + value, term: Term{Value: word + ":" + value}})
+					continue
+				}
+				tokens = append(tokens, token{kind: tokenTerm, text: word, term: Term{Field: field, Value: value}})
+				continue
+			}
+
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{kind: tokenAnd, text: word})
+			case "OR":
+				tokens = append(tokens, token{kind: tokenOr, text: word})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokenNot, text: word})
+			default:
+				tokens = append(tokens, token{kind: tokenTerm, text: word, term: Term{Value: word}})
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// readQuoted reads a double-quoted string starting at runes[i] and returns
+// its contents and the index after the closing quote
+func readQuoted(runes []rune, i int) (string, int, error) {
+	end := i + 1
+	for end < len(runes) && runes[end] != '"' {
+		end++
+	}
+	if end >= len(runes) {
+		return "", 0, errors.New("unterminated quote")
+	}
+	value := strings.TrimSpace(string(runes[i+1 : end]))
+	if value == "" {
+		return "", 0, errors.New("empty quoted phrase")
+	}
+	return value, end + 1, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	terms  int
+}
+
+func (p *parser) peek() *token {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *parser) parseOr(depth int) (Node, error) {
+	first, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	children := []Node{first}
+	for t := p.peek(); t != nil && t.kind == tokenOr; t = p.peek() {
+		p.pos++
+		next, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return Or{Children: children}, nil
+}
+
+func (p *parser) parseAnd(depth int) (Node, error) {
+	first, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	children := []Node{first}
+	for {
+		t := p.peek()
+		if t == nil || t.kind == tokenOr || t.kind == tokenRParen {
+			break
+		}
+		// AND is optional between terms
+		if t.kind == tokenAnd {
+			p.pos++
+		}
+		next, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, next)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return And{Children: children}, nil
+}
+
+func (p *parser) parseUnary(depth int) (Node, error) {
+	if depth > MaxDepth {
+		return nil, errors.New("search query is nested too deeply")
+	}
+	t := p.peek()
+	if t == nil {
+		return nil, errors.New("search query ends with an operator")
+	}
+	switch t.kind {
+	case tokenNot:
+		p.pos++
+		child, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{Child: child}, nil
+	case tokenLParen:
+		p.pos++
+		inner, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t == nil || t.kind != tokenRParen {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return inner, nil
+	case tokenTerm:
+		p.pos++
+		p.terms++
+		if p.terms > MaxTerms {
+			return nil, fmt.Errorf("search query has more than %d terms", MaxTerms)
+		}
+		return t.term, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+}
@@ -0,0 +1,93 @@
+package searchquery_test
+
+import (
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/lib/searchquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  searchquery.Node
+	}{
+		{
+			name:  "single word",
+			input: "github",
+			want:  searchquery.Term{Value: "github"},
+		},
+		{
+			name:  "implicit and with qualifiers and negation",
+			input: "type:api_key aws env:prod -tag:legacy",
+			want: searchquery.And{Children: []searchquery.Node{
+				searchquery.Term{Field: "type", Value: "api_key"},
+				searchquery.Term{Value: "aws"},
+				searchquery.Term{Field: "env", Value: "prod"},
+				searchquery.Not{Child: searchquery.Term{Field: "tag", Value: "legacy"}},
+			}},
+		},
+		{
+			name:  "or binds looser than and",
+			input: "a b OR c",
+			want: searchquery.Or{Children: []searchquery.Node{
+				searchquery.And{Children: []searchquery.Node{searchquery.Term{Value: "a"}, searchquery.Term{Value: "b"}}},
+				searchquery.Term{Value: "c"},
+			}},
+		},
+		{
+			name:  "groups, explicit operators and quoted phrases",
+			input: `(github OR gitlab) AND NOT Tag:"old accounts"`,
+			want: searchquery.And{Children: []searchquery.Node{
+				searchquery.Or{Children: []searchquery.Node{searchquery.Term{Value: "github"}, searchquery.Term{Value: "gitlab"}}},
+				searchquery.Not{Child: searchquery.Term{Field: "tag", Value: "old accounts", Phrase: true}},
+			}},
+		},
+		{
+			name:  "lower-case operators are words",
+			input: "salt and pepper",
+			want: searchquery.And{Children: []searchquery.Node{
+				searchquery.Term{Value: "salt"}, searchquery.Term{Value: "and"}, searchquery.Term{Value: "pepper"},
+			}},
+		},
+		{
+			name:  "urls and inner dashes are plain words",
+			input: "https://github.com us-east-1",
+			want: searchquery.And{Children: []searchquery.Node{
+				searchquery.Term{Value: "https://github.com"}, searchquery.Term{Value: "us-east-1"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searchquery.Parse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"aws OR",
+		"(aws",
+		"aws)",
+		`"unterminated`,
+		"tag:",
+		"NOT",
+		"a OR OR b",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := searchquery.Parse(input)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := searchquery.Parse("")
+	assert.ErrorIs(t, err, searchquery.ErrEmpty)
+}
@@ -0,0 +1,56 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPSink posts each batch as one NDJSON request, an event's Data per line
+type HTTPSink struct {
+	url           string
+	authorization string
+	client        *http.Client
+}
+
+func NewHTTPSink(url, authorization string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:           url,
+		authorization: authorization,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *HTTPSink) Send(ctx context.Context, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	for _, e := range events {
+		body.Write(e.Data)
+		body.WriteByte('\n')
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.authorization != "" {
+		req.Header.Set("Authorization", s.authorization)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send audit events: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Package siem forwards audit events to a security information and event
+// management system, either to a syslog collector as RFC 5424 messages or to
+// an HTTP collector as batches of NDJSON.
+package siem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+)
+
+// Event is one audit entry as forwarded
+type Event struct {
+	ID   string
+	Time time.Time
+	// Action names the event, e.g. secret_view; it is the syslog MSGID
+	Action string
+	// Fields are the entry's attributes, sent as syslog structured data
+	Fields []Field
+	// Data is the whole entry as JSON: the syslog message, or a line of an HTTP batch
+	Data json.RawMessage
+}
+
+// Field is a named attribute of an event
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Sink sends events to a collector. Send returns once the whole batch has
+// been handed over, or with an error, in which case the batch may have been
+// partly delivered and is sent again.
+type Sink interface {
+	Send(ctx context.Context, events []Event) error
+}
+
+// New returns the sink selected by cfg
+func New(cfg *config.AuditForwardConfig) (Sink, error) {
+	switch cfg.Sink {
+	case "syslog":
+		hostname := cfg.Syslog.Hostname
+		if hostname == "" {
+			hostname, _ = os.Hostname()
+		}
+		return &SyslogSink{
+			Network:  cfg.Syslog.Network,
+			Address:  cfg.Syslog.Address,
+			Facility: cfg.Syslog.Facility,
+			Hostname: hostname,
+			AppName:  "psvault",
+			Timeout:  cfg.Timeout,
+		}, nil
+	case "http":
+		return NewHTTPSink(cfg.HTTP.URL, cfg.HTTP.Authorization, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown audit forward sink %q", cfg.Sink)
+	}
+}
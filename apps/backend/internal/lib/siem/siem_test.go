@@ -0,0 +1,150 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvents() []Event {
+	at := time.Date(2026, 3, 1, 12, 30, 45, 123456789, time.UTC)
+	return []Event{
+		{
+			ID:     "a1",
+			Time:   at,
+			Action: "secret_view",
+			Fields: []Field{{Name: "id", Value: "a1"}, {Name: "userAgent", Value: `curl "x" [y]\z`}},
+			Data:   []byte(`{"id":"a1"}`),
+		},
+		{
+			ID:     "a2",
+			Time:   at.Add(time.Second),
+			Action: "delete",
+			Data:   []byte(`{"id":"a2"}`),
+		},
+	}
+}
+
+func TestSyslogSink_Format(t *testing.T) {
+	s := &SyslogSink{Facility: 13, Hostname: "vault 1", AppName: "psvault"}
+	events := testEvents()
+	pid := strconv.Itoa(os.Getpid())
+
+	assert.Equal(t,
+		`<110>1 2026-03-01T12:30:45.123456Z vault_1 psvault `+pid+` secret_view `+
+			`[psvault@32473 id="a1" userAgent="curl \"x\" [y\]\\z"] {"id":"a1"}`,
+		string(s.Format(events[0])))
+	assert.Equal(t,
+		`<110>1 2026-03-01T12:30:46.123456Z vault_1 psvault `+pid+` delete - {"id":"a2"}`,
+		string(s.Format(events[1])))
+
+	s.Hostname = ""
+	assert.Contains(t, string(s.Format(events[1])), " - psvault ")
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	s := &SyslogSink{Network: "udp", Address: conn.LocalAddr().String(), Facility: 13, Hostname: "h", AppName: "psvault", Timeout: time.Second}
+	events := testEvents()
+	require.NoError(t, s.Send(context.Background(), events))
+
+	buf := make([]byte, 2048)
+	for _, e := range events {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.Equal(t, string(s.Format(e)), string(buf[:n]))
+	}
+}
+
+func TestSyslogSink_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		// Read octet-counted frames until the sender closes the connection
+		var msgs []string
+		r := bufio.NewReader(conn)
+		for {
+			length, err := r.ReadString(' ')
+			if err != nil {
+				break
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				break
+			}
+			msgs = append(msgs, string(msg))
+		}
+		received <- msgs
+	}()
+
+	s := &SyslogSink{Network: "tcp", Address: ln.Addr().String(), Facility: 13, Hostname: "h", AppName: "psvault", Timeout: time.Second}
+	events := testEvents()
+	require.NoError(t, s.Send(context.Background(), events))
+
+	select {
+	case msgs := <-received:
+		require.Len(t, msgs, len(events))
+		for i, e := range events {
+			assert.Equal(t, string(s.Format(e)), msgs[i])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("listener received nothing")
+	}
+}
+
+func TestSyslogSink_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	s := &SyslogSink{Network: "tcp", Address: addr, Facility: 13, Timeout: time.Second}
+	assert.Error(t, s.Send(context.Background(), testEvents()))
+}
+
+func TestHTTPSink(t *testing.T) {
+	var body, contentType, authorization string
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, contentType, authorization = string(b), r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := NewHTTPSink(srv.URL, "Bearer token", time.Second)
+	require.NoError(t, s.Send(context.Background(), testEvents()))
+	assert.Equal(t, "{\"id\":\"a1\"}\n{\"id\":\"a2\"}\n", body)
+	assert.Equal(t, "application/x-ndjson", contentType)
+	assert.Equal(t, "Bearer token", authorization)
+
+	status = http.StatusServiceUnavailable
+	err := s.Send(context.Background(), testEvents())
+	assert.EqualError(t, err, fmt.Sprintf("collector responded %d %s", status, http.StatusText(status)))
+}
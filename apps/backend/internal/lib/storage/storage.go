@@ -0,0 +1,39 @@
+// Package storage provides blob storage for client-encrypted attachments
+// behind a driver-agnostic interface.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Sameer16536/psvault/internal/config"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage stores opaque blobs by key. Keys are slash-separated and generated
+// by the server, never taken from user input.
+type Storage interface {
+	// Put streams size bytes from r into key, replacing any existing object.
+	// It fails without storing anything if r holds fewer or more than size bytes.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens key for reading; the caller must close the reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the driver selected by cfg
+func New(ctx context.Context, cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocalStorage(cfg.LocalPath)
+	case "s3":
+		return NewS3Storage(ctx, &cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,98 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/lib/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exerciseDriver runs the behaviour every driver must share
+func exerciseDriver(t *testing.T, s storage.Storage) {
+	t.Helper()
+	ctx := context.Background()
+	key := "attachments/secret-1/blob-1"
+	payload := []byte("encrypted-bytes")
+
+	require.NoError(t, s.Put(ctx, key, bytes.NewReader(payload), int64(len(payload))))
+
+	r, err := s.Get(ctx, key)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, payload, got)
+
+	require.NoError(t, s.Delete(ctx, key))
+	_, err = s.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Deleting a missing key is not an error
+	assert.NoError(t, s.Delete(ctx, key))
+
+	// Bodies larger than the declared size are rejected, not truncated
+	err = s.Put(ctx, key, bytes.NewReader(payload), int64(len(payload))-1)
+	assert.Error(t, err)
+	_, err = s.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	exerciseDriver(t, s)
+}
+
+func TestLocalStorage_RejectsEscapingKeys(t *testing.T) {
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	err = s.Put(context.Background(), "../outside", bytes.NewReader([]byte("x")), 1)
+	assert.Error(t, err)
+}
+
+func TestLocalStorage_RejectsShortWrites(t *testing.T) {
+	s, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+
+	err = s.Put(context.Background(), "short", bytes.NewReader([]byte("abc")), 10)
+	assert.Error(t, err)
+	_, err = s.Get(context.Background(), "short")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+// TestS3Storage runs against a local MinIO, e.g.
+//
+//	docker run -p 9000:9000 minio/minio server /data
+//	PSVAULT_TEST_S3_ENDPOINT=localhost:9000 go test ./internal/lib/storage/
+func TestS3Storage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	endpoint := os.Getenv("PSVAULT_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("PSVAULT_TEST_S3_ENDPOINT not set")
+	}
+
+	s, err := storage.NewS3Storage(context.Background(), &config.S3StorageConfig{
+		Endpoint:  endpoint,
+		Bucket:    "psvault-test",
+		AccessKey: envOr("PSVAULT_TEST_S3_ACCESS_KEY", "minioadmin"),
+		SecretKey: envOr("PSVAULT_TEST_S3_SECRET_KEY", "minioadmin"),
+	})
+	require.NoError(t, err)
+	exerciseDriver(t, s)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,114 @@
+// Package vaultcrypto implements the client-side encryption scheme of the web
+// app (apps/frontend/src/lib/crypto.ts) so other clients can read and write
+// the same vaults.
+//
+// Every vault has a random 256-bit AES-GCM key. The server stores it encrypted
+// under a key derived from the vault's master password:
+//
+//	encryptedKey     = salt(16) || iv(12) || AES-GCM(PBKDF2-SHA256(password, salt, 100000), vaultKey)
+//	encryptedPayload = iv(12) || AES-GCM(vaultKey, payload)
+//
+// Payloads are JSON objects such as {"username": "...", "password": "..."}.
+// The API carries both values as base64 strings, which encoding/json maps to
+// []byte.
+package vaultcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+const (
+	PBKDF2Iterations = 100000
+	SaltLength       = 16
+	IVLength         = 12
+	KeyLength        = 32
+)
+
+// ErrDecrypt is returned when a ciphertext fails authentication, usually
+// because of a wrong master password or vault key
+var ErrDecrypt = errors.New("decryption failed: wrong key or corrupted data")
+
+// DeriveKey derives the key that wraps a vault key from a master password
+func DeriveKey(masterPassword string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, masterPassword, salt, PBKDF2Iterations, KeyLength)
+}
+
+// GenerateVaultKey returns a new random vault key
+func GenerateVaultKey() ([]byte, error) {
+	key := make([]byte, KeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Encrypt seals data under key, returning iv || ciphertext
+func Encrypt(data, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, IVLength)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(iv, iv, data, nil), nil
+}
+
+// Decrypt opens an iv || ciphertext value produced by Encrypt
+func Decrypt(data, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < IVLength+gcm.Overhead() {
+		return nil, ErrDecrypt
+	}
+	plain, err := gcm.Open(nil, data[:IVLength], data[IVLength:], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
+
+// EncryptVaultKey wraps a vault key with a master password, returning salt || iv || ciphertext
+func EncryptVaultKey(vaultKey []byte, masterPassword string) ([]byte, error) {
+	salt := make([]byte, SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	wrapKey, err := DeriveKey(masterPassword, salt)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := Encrypt(vaultKey, wrapKey)
+	if err != nil {
+		return nil, err
+	}
+	return append(salt, sealed...), nil
+}
+
+// DecryptVaultKey unwraps a vault key produced by EncryptVaultKey or the web app
+func DecryptVaultKey(encryptedKey []byte, masterPassword string) ([]byte, error) {
+	if len(encryptedKey) < SaltLength {
+		return nil, ErrDecrypt
+	}
+	wrapKey, err := DeriveKey(masterPassword, encryptedKey[:SaltLength])
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(encryptedKey[SaltLength:], wrapKey)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,57 @@
+package vaultcrypto
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Produced by the web app's WebCrypto code with fixed salt and IVs
+const (
+	webMasterPassword = "correct horse battery staple"
+	webEncryptedKey   = "AQIDBAUGBwgJCgsMDQ4PECAhIiMkJSYnKCkqK57H72EAVrErayQZE0bODPvM+McDyBMWLrwWvuT7Oglm5RRv7wimZ9Tv3/0wXFjqrA=="
+	webPayload        = "QEFCQ0RFRkdISUpLouh0NVaX0AsVlPNDHcR6UxVBOxyaIL7aRSTIN0zTGONY21bQ662fTCxBH2zXq31ZBk/AhFeEUaI="
+)
+
+func TestDecryptWebAppVault(t *testing.T) {
+	encryptedKey, err := base64.StdEncoding.DecodeString(webEncryptedKey)
+	require.NoError(t, err)
+	payload, err := base64.StdEncoding.DecodeString(webPayload)
+	require.NoError(t, err)
+
+	vaultKey, err := DecryptVaultKey(encryptedKey, webMasterPassword)
+	require.NoError(t, err)
+	for i, b := range vaultKey {
+		assert.Equal(t, byte(0x80+i), b)
+	}
+
+	plain, err := Decrypt(payload, vaultKey)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"username":"jane","password":"hunter2"}`, string(plain))
+
+	_, err = DecryptVaultKey(encryptedKey, "wrong password")
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestRoundTrip(t *testing.T) {
+	vaultKey, err := GenerateVaultKey()
+	require.NoError(t, err)
+
+	encryptedKey, err := EncryptVaultKey(vaultKey, "master")
+	require.NoError(t, err)
+	assert.Len(t, encryptedKey, SaltLength+IVLength+KeyLength+16)
+	unwrapped, err := DecryptVaultKey(encryptedKey, "master")
+	require.NoError(t, err)
+	assert.Equal(t, vaultKey, unwrapped)
+
+	sealed, err := Encrypt([]byte(`{"apiKey":"k"}`), vaultKey)
+	require.NoError(t, err)
+	plain, err := Decrypt(sealed, vaultKey)
+	require.NoError(t, err)
+	assert.Equal(t, `{"apiKey":"k"}`, string(plain))
+
+	_, err = Decrypt(sealed[:IVLength], vaultKey)
+	assert.ErrorIs(t, err, ErrDecrypt)
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.10", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.public, PublicAddr(netip.MustParseAddr(tt.addr)), tt.addr)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+	assert.ErrorIs(t, CheckHost(ctx, "169.254.169.254"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckHost(ctx, "::1"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckHost(ctx, "localhost"), ErrForbiddenAddress)
+	assert.NoError(t, CheckHost(ctx, "93.184.216.34"))
+}
+
+func TestDialControl(t *testing.T) {
+	assert.ErrorIs(t, DialControl("tcp4", "127.0.0.1:6379", nil), ErrForbiddenAddress)
+	assert.ErrorIs(t, DialControl("tcp6", "[fe80::1]:443", nil), ErrForbiddenAddress)
+	assert.NoError(t, DialControl("tcp4", "93.184.216.34:443", nil))
+}
@@ -0,0 +1,83 @@
+// Package webhook signs outgoing webhook requests so receivers can check they
+// come from psvault and were not replayed, and keeps requests away from
+// non-public addresses.
+//
+// Each request carries the header
+//
+//	X-Psvault-Signature: t=<unix seconds>,v1=<hex HMAC-SHA256(secret, "<t>.<body>")>
+//
+// Receivers recompute the HMAC over the timestamp and the raw body with the
+// endpoint's secret, compare in constant time and reject stale timestamps.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the timestamp and signature of a request
+	SignatureHeader = "X-Psvault-Signature"
+	// EventHeader carries the event type, e.g. secret.update
+	EventHeader = "X-Psvault-Event"
+	// DeliveryHeader carries the delivery ID; redeliveries get a new one
+	DeliveryHeader = "X-Psvault-Delivery"
+)
+
+var (
+	ErrMalformedSignature = errors.New("malformed webhook signature")
+	ErrSignatureMismatch  = errors.New("webhook signature mismatch")
+	ErrSignatureExpired   = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// Sign returns the signature header value for body sent at t
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks a signature header value against body, accepting timestamps
+// within tolerance of now
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return ErrMalformedSignature
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrMalformedSignature
+	}
+	want, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrMalformedSignature
+	}
+	if !hmac.Equal(want, mac(secret, ts, body)) {
+		return ErrSignatureMismatch
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
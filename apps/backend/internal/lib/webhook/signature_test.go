@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1780000000, 0)
+	body := []byte(`{"type":"secret.update"}`)
+	header := Sign("whsec_test", now, body)
+
+	assert.Regexp(t, `^t=1780000000,v1=[0-9a-f]{64}$`, header)
+	assert.NoError(t, Verify("whsec_test", header, body, now.Add(time.Minute), 5*time.Minute))
+	assert.ErrorIs(t, Verify("whsec_other", header, body, now, 5*time.Minute), ErrSignatureMismatch)
+	assert.ErrorIs(t, Verify("whsec_test", header, []byte(`{"type":"secret.delete"}`), now, 5*time.Minute), ErrSignatureMismatch)
+	assert.ErrorIs(t, Verify("whsec_test", header, body, now.Add(time.Hour), 5*time.Minute), ErrSignatureExpired)
+	assert.ErrorIs(t, Verify("whsec_test", "v1=abc", body, now, 5*time.Minute), ErrMalformedSignature)
+}
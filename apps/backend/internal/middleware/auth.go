@@ -3,26 +3,103 @@ package middleware
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/serviceaccount"
 	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
 	"github.com/clerk/clerk-sdk-go/v2"
 	clerkhttp "github.com/clerk/clerk-sdk-go/v2/http"
 	"github.com/labstack/echo/v4"
 )
 
 type AuthMiddleware struct {
-	server *server.Server
+	server          *server.Server
+	users           *service.UserService
+	serviceAccounts *service.ServiceAccountService
+	anomaly         *service.AnomalyService
+	// recentAuthVerifiers are consulted in order by RequireRecentAuth
+	recentAuthVerifiers []RecentAuthVerifier
 }
 
-func NewAuthMiddleware(s *server.Server) *AuthMiddleware {
+func NewAuthMiddleware(s *server.Server, users *service.UserService, serviceAccounts *service.ServiceAccountService, anomaly *service.AnomalyService) *AuthMiddleware {
+	level := clerk.SessionReverificationLevelSecondFactor
+	if s.Config.StepUp != nil {
+		level = clerk.SessionReverificationLevel(s.Config.StepUp.Level)
+	}
 	return &AuthMiddleware{
-		server: s,
+		server:          s,
+		users:           users,
+		serviceAccounts: serviceAccounts,
+		anomaly:         anomaly,
+		recentAuthVerifiers: []RecentAuthVerifier{
+			ClerkFactorVerifier{Level: level},
+		},
 	}
 }
 
+// RequireAuth accepts either a Clerk session token or a service account
+// access token issued by POST /api/auth/token.
 func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	clerkAuth := auth.requireClerkAuth(next)
+	serviceAccountAuth := auth.requireServiceAccountAuth(next)
+	return func(c echo.Context) error {
+		token := strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+		if strings.HasPrefix(token, serviceaccount.AccessTokenPrefix) {
+			return serviceAccountAuth(c)
+		}
+		return clerkAuth(c)
+	}
+}
+
+func (auth *AuthMiddleware) requireServiceAccountAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		token := strings.TrimPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+
+		sa, err := auth.serviceAccounts.Authenticate(c.Request().Context(), token)
+		if err != nil {
+			return err
+		}
+		if sa == nil {
+			auth.server.Logger.Error().
+				Str("function", "RequireAuth").
+				Str("request_id", GetRequestID(c)).
+				Dur("duration", time.Since(start)).
+				Msg("invalid or expired service account token")
+			return errs.NewUnauthorizedError("Unauthorized", false)
+		}
+
+		serviceAccountID := sa.ID.String()
+		if err := auth.checkAnomalyState(c, serviceAccountID, nil); err != nil {
+			return err
+		}
+		c.Set("user_id", serviceAccountID)
+		c.Set("actor_type", string(actor.TypeServiceAccount))
+		c.SetRequest(c.Request().WithContext(actor.WithActor(c.Request().Context(), &actor.Actor{
+			ID:        serviceAccountID,
+			Type:      actor.TypeServiceAccount,
+			OrgID:     sa.OrgID,
+			IPAddress: c.RealIP(),
+			UserAgent: c.Request().UserAgent(),
+		})))
+
+		auth.server.Logger.Info().
+			Str("function", "RequireAuth").
+			Str("service_account_id", serviceAccountID).
+			Str("request_id", GetRequestID(c)).
+			Dur("duration", time.Since(start)).
+			Msg("service account authenticated successfully")
+
+		return next(c)
+	}
+}
+
+func (auth *AuthMiddleware) requireClerkAuth(next echo.HandlerFunc) echo.HandlerFunc {
 	return echo.WrapMiddleware(
 		clerkhttp.WithHeaderAuthorization(
 			clerkhttp.AuthorizationFailureHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -58,13 +135,35 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 			return errs.NewUnauthorizedError("Unauthorized", false)
 		}
 
-		c.Set("user_id", claims.Subject)
+		// Everything a user owns references the internal user ID, not the Clerk ID
+		userID, err := auth.users.Resolve(c.Request().Context(), claims.Subject)
+		if err != nil {
+			return err
+		}
+
+		if err := auth.checkAnomalyState(c, userID, claims); err != nil {
+			return err
+		}
+
+		c.Set("user_id", userID)
 		c.Set("user_role", claims.ActiveOrganizationRole)
 		c.Set("permissions", claims.Claims.ActiveOrganizationPermissions)
+		c.Set("actor_type", string(actor.TypeUser))
+		c.SetRequest(c.Request().WithContext(actor.WithActor(c.Request().Context(), &actor.Actor{
+			ID:             userID,
+			ExternalID:     claims.Subject,
+			Type:           actor.TypeUser,
+			OrgID:          claims.ActiveOrganizationID,
+			OrgRole:        claims.ActiveOrganizationRole,
+			OrgPermissions: claims.Claims.ActiveOrganizationPermissions,
+			IPAddress:      c.RealIP(),
+			UserAgent:      c.Request().UserAgent(),
+		})))
 
 		auth.server.Logger.Info().
 			Str("function", "RequireAuth").
-			Str("user_id", claims.Subject).
+			Str("user_id", userID).
+			Str("clerk_user_id", claims.Subject).
 			Str("request_id", GetRequestID(c)).
 			Dur("duration", time.Since(start)).
 			Msg("user authenticated successfully")
@@ -72,3 +171,35 @@ func (auth *AuthMiddleware) RequireAuth(next echo.HandlerFunc) echo.HandlerFunc
 		return next(c)
 	})
 }
+
+// checkAnomalyState rejects principals the anomaly detector has locked, and
+// sessions that have not re-verified since a re-authentication was required.
+// claims is nil for service accounts, which cannot re-verify.
+func (auth *AuthMiddleware) checkAnomalyState(c echo.Context, principalID string, claims *clerk.SessionClaims) error {
+	ctx := c.Request().Context()
+	if until, locked := auth.anomaly.LockedUntil(ctx, principalID); locked {
+		c.Response().Header().Set("Retry-After", strconv.Itoa(int(time.Until(until).Seconds())+1))
+		auth.server.Logger.Warn().
+			Str("function", "RequireAuth").
+			Str("user_id", principalID).
+			Str("request_id", GetRequestID(c)).
+			Time("locked_until", until).
+			Msg("request from locked account rejected")
+		return errs.NewAccountLockedError("Account temporarily locked due to unusual activity")
+	}
+
+	since, required := auth.anomaly.ReauthRequiredSince(ctx, principalID)
+	if !required || claims == nil {
+		return nil
+	}
+	// The session passes once its first factor was verified after the requirement was set
+	policy := clerk.SessionReverificationPolicy{
+		AfterMinutes: int64(time.Since(since).Minutes()) - 1,
+		Level:        clerk.SessionReverificationLevelFirstFactor,
+	}
+	if claims.NeedsReverification(policy) {
+		return errs.NewReauthenticationRequiredError("Unusual activity detected, please sign in again")
+	}
+	auth.anomaly.ClearReauth(ctx, principalID)
+	return nil
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/Sameer16536/psvault/internal/service"
 	"github.com/newrelic/go-agent/v3/newrelic"
 )
 
@@ -13,7 +14,7 @@ type Middlewares struct {
 	RateLimit       *RateLimitMiddleware
 }
 
-func NewMiddlewares(s *server.Server) *Middlewares {
+func NewMiddlewares(s *server.Server, services *service.Services) *Middlewares {
 	// Get New Relic application instance from server
 	var nrApp *newrelic.Application
 	if s.LoggerService != nil {
@@ -22,7 +23,7 @@ func NewMiddlewares(s *server.Server) *Middlewares {
 
 	return &Middlewares{
 		Global:          NewGlobalMiddlewares(s),
-		Auth:            NewAuthMiddleware(s),
+		Auth:            NewAuthMiddleware(s, services.User, services.ServiceAccount, services.Anomaly),
 		ContextEnhancer: NewContextEnhancer(s),
 		Tracing:         NewTracingMiddleware(s, nrApp),
 		RateLimit:       NewRateLimitMiddleware(s),
@@ -1,9 +1,36 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/errs"
 	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
 )
 
+// rateLimitTimeout bounds how long a request waits on Redis before failing open
+const rateLimitTimeout = 250 * time.Millisecond
+
+// fixedWindowScript increments the counter for the current window and returns
+// the new count and the milliseconds until the window resets.
+var fixedWindowScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
 type RateLimitMiddleware struct {
 	server *server.Server
 }
@@ -14,6 +41,67 @@ func NewRateLimitMiddleware(s *server.Server) *RateLimitMiddleware {
 	}
 }
 
+// Limit enforces policy under name, counting per authenticated user when
+// user_id is set on the context and per client IP otherwise. Counters live
+// in Redis so limits are shared across instances; if Redis is unavailable
+// requests are let through.
+func (r *RateLimitMiddleware) Limit(name string, policy config.RateLimitPolicy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if r.server.Redis == nil || (r.server.Config.RateLimit != nil && r.server.Config.RateLimit.Disabled) {
+				return next(c)
+			}
+
+			identifier := "ip:" + c.RealIP()
+			if userID, ok := c.Get("user_id").(string); ok && userID != "" {
+				identifier = "user:" + userID
+			}
+			key := fmt.Sprintf("ratelimit:%s:%s", name, identifier)
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), rateLimitTimeout)
+			res, err := fixedWindowScript.Run(ctx, r.server.Redis, []string{key}, policy.Window.Milliseconds()).Int64Slice()
+			cancel()
+			if err != nil || len(res) != 2 {
+				r.server.Logger.Warn().
+					Err(err).
+					Str("request_id", GetRequestID(c)).
+					Str("policy", name).
+					Msg("rate limiter unavailable, allowing request")
+				return next(c)
+			}
+			count, resetIn := res[0], time.Duration(res[1])*time.Millisecond
+			resetSeconds := int64((resetIn + time.Second - 1) / time.Second)
+
+			remaining := int64(policy.Requests) - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			h := c.Response().Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(policy.Requests))
+			h.Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+			h.Set("X-RateLimit-Reset", strconv.FormatInt(resetSeconds, 10))
+
+			if count > int64(policy.Requests) {
+				h.Set("Retry-After", strconv.FormatInt(resetSeconds, 10))
+				r.RecordRateLimitHit(c.Path())
+
+				r.server.Logger.Warn().
+					Str("request_id", GetRequestID(c)).
+					Str("policy", name).
+					Str("identifier", identifier).
+					Str("path", c.Path()).
+					Str("method", c.Request().Method).
+					Str("ip", c.RealIP()).
+					Msg("rate limit exceeded")
+
+				return errs.NewTooManyRequestsError("Rate limit exceeded", false)
+			}
+
+			return next(c)
+		}
+	}
+}
+
 func (r *RateLimitMiddleware) RecordRateLimitHit(endpoint string) {
 	if r.server.LoggerService != nil && r.server.LoggerService.GetApplication() != nil {
 		r.server.LoggerService.GetApplication().RecordCustomEvent("RateLimitHit", map[string]interface{}{
@@ -0,0 +1,132 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/middleware"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitServer(t *testing.T, addr string) *server.Server {
+	t.Helper()
+	logger := zerolog.Nop()
+	client := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+	return &server.Server{
+		Config: &config.Config{RateLimit: config.DefaultRateLimitConfig()},
+		Logger: &logger,
+		Redis:  client,
+	}
+}
+
+// Helper to send one request from ip through the limiter, optionally as userID
+func limitRequest(t *testing.T, limit echo.MiddlewareFunc, ip, userID string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderXRealIP, ip)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	if userID != "" {
+		c.Set("user_id", userID)
+	}
+	err := limit(func(c echo.Context) error { return c.NoContent(http.StatusNoContent) })(c)
+	return rec, err
+}
+
+// Test: Requests past the policy are rejected until the window resets, with the limit headers set
+func TestRateLimit_Window(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newRateLimitServer(t, mr.Addr())
+	limit := middleware.NewRateLimitMiddleware(s).Limit("test", config.RateLimitPolicy{Requests: 2, Window: time.Minute})
+
+	for i, remaining := range []string{"1", "0"} {
+		rec, err := limitRequest(t, limit, "203.0.113.1", "")
+		require.NoError(t, err, "request %d", i)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, remaining, rec.Header().Get("X-RateLimit-Remaining"))
+		assert.Equal(t, "60", rec.Header().Get("X-RateLimit-Reset"))
+		assert.Empty(t, rec.Header().Get("Retry-After"))
+	}
+
+	mr.FastForward(20 * time.Second)
+	rec, err := limitRequest(t, limit, "203.0.113.1", "")
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusTooManyRequests, httpErr.Status)
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "40", rec.Header().Get("X-RateLimit-Reset"))
+	assert.Equal(t, "40", rec.Header().Get("Retry-After"))
+
+	// The counter expires with the window
+	mr.FastForward(40 * time.Second)
+	rec, err = limitRequest(t, limit, "203.0.113.1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "60", rec.Header().Get("X-RateLimit-Reset"))
+}
+
+// Test: Counters are kept per policy, per user when authenticated and per IP otherwise
+func TestRateLimit_Keys(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newRateLimitServer(t, mr.Addr())
+	limits := middleware.NewRateLimitMiddleware(s)
+	policy := config.RateLimitPolicy{Requests: 1, Window: time.Minute}
+	auth, shares := limits.Limit("auth", policy), limits.Limit("share_view", policy)
+
+	_, err := limitRequest(t, auth, "203.0.113.1", "")
+	require.NoError(t, err)
+	_, err = limitRequest(t, auth, "203.0.113.1", "")
+	require.Error(t, err)
+
+	// Another policy, another IP and a signed-in user from the same IP have their own counters
+	_, err = limitRequest(t, shares, "203.0.113.1", "")
+	require.NoError(t, err)
+	_, err = limitRequest(t, auth, "203.0.113.2", "")
+	require.NoError(t, err)
+	_, err = limitRequest(t, auth, "203.0.113.1", "user-1")
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"ratelimit:auth:ip:203.0.113.1",
+		"ratelimit:auth:ip:203.0.113.2",
+		"ratelimit:auth:user:user-1",
+		"ratelimit:share_view:ip:203.0.113.1",
+	}, mr.Keys())
+}
+
+// Test: Requests are let through without limit headers when Redis fails or limiting is disabled
+func TestRateLimit_FailOpen(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newRateLimitServer(t, mr.Addr())
+	limit := middleware.NewRateLimitMiddleware(s).Limit("test", config.RateLimitPolicy{Requests: 1, Window: time.Minute})
+
+	mr.SetError("READONLY unavailable")
+	for range 3 {
+		rec, err := limitRequest(t, limit, "203.0.113.1", "")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+	}
+
+	mr.Close()
+	rec, err := limitRequest(t, limit, "203.0.113.1", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	s.Config.RateLimit.Disabled = true
+	rec, err = limitRequest(t, limit, "203.0.113.1", "")
+	require.NoError(t, err)
+	assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+}
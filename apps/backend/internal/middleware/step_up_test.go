@@ -0,0 +1,118 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/middleware"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/clerk/clerk-sdk-go/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClerkFactorVerifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   clerk.SessionReverificationLevel
+		fva     [2]int64
+		wantOK  bool
+		wantAge time.Duration
+	}{
+		{"first factor", clerk.SessionReverificationLevelFirstFactor, [2]int64{5, 30}, true, 5 * time.Minute},
+		{"first factor never verified", clerk.SessionReverificationLevelFirstFactor, [2]int64{-1, -1}, false, 0},
+		{"second factor", clerk.SessionReverificationLevelSecondFactor, [2]int64{5, 30}, true, 30 * time.Minute},
+		{"second factor not enabled", clerk.SessionReverificationLevelSecondFactor, [2]int64{5, -1}, false, 0},
+		{"multi factor uses oldest", clerk.SessionReverificationLevelMultiFactor, [2]int64{40, 30}, true, 40 * time.Minute},
+		{"multi factor without second factor", clerk.SessionReverificationLevelMultiFactor, [2]int64{5, -1}, true, 5 * time.Minute},
+		{"multi factor never verified", clerk.SessionReverificationLevelMultiFactor, [2]int64{-1, 5}, false, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			claims := &clerk.SessionClaims{Claims: clerk.Claims{FactorVerificationAge: tc.fva}}
+			req = req.WithContext(clerk.ContextWithSessionClaims(req.Context(), claims))
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			verifiedAt, ok := middleware.ClerkFactorVerifier{Level: tc.level}.VerifiedAt(c)
+			assert.Equal(t, tc.wantOK, ok)
+			if ok {
+				assert.WithinDuration(t, time.Now().Add(-tc.wantAge), verifiedAt, time.Second)
+			}
+		})
+	}
+}
+
+func TestClerkFactorVerifierWithoutSession(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+	_, ok := middleware.ClerkFactorVerifier{Level: clerk.SessionReverificationLevelFirstFactor}.VerifiedAt(c)
+	assert.False(t, ok)
+}
+
+func TestRequireRecentAuth(t *testing.T) {
+	tests := []struct {
+		name     string
+		fva      *[2]int64
+		actor    *actor.Actor
+		disabled bool
+		wantOK   bool
+	}{
+		{"recently verified", &[2]int64{5, 5}, nil, false, true},
+		{"verified within the limit", &[2]int64{9, 9}, nil, false, true},
+		{"verified just past the limit", &[2]int64{11, 11}, nil, false, false},
+		{"verified too long ago", &[2]int64{30, 30}, nil, false, false},
+		{"second factor too old", &[2]int64{1, 30}, nil, false, false},
+		{"never verified", &[2]int64{-1, -1}, nil, false, false},
+		{"no session", nil, nil, false, false},
+		{"service account", nil, &actor.Actor{ID: "sa-1", Type: actor.TypeServiceAccount}, false, true},
+		{"user without session", nil, &actor.Actor{ID: "user-1", Type: actor.TypeUser}, false, false},
+		{"disabled", nil, nil, true, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := zerolog.Nop()
+			cfg := config.DefaultStepUpConfig()
+			cfg.Disabled = tc.disabled
+			s := &server.Server{Config: &config.Config{StepUp: cfg}, Logger: &logger}
+			auth := middleware.NewAuthMiddleware(s, nil, nil, nil)
+
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			ctx := req.Context()
+			if tc.fva != nil {
+				ctx = clerk.ContextWithSessionClaims(ctx, &clerk.SessionClaims{Claims: clerk.Claims{FactorVerificationAge: *tc.fva}})
+			}
+			if tc.actor != nil {
+				ctx = actor.WithActor(ctx, tc.actor)
+			}
+			c := echo.New().NewContext(req.WithContext(ctx), httptest.NewRecorder())
+
+			called := false
+			err := auth.RequireRecentAuth(10 * time.Minute)(func(c echo.Context) error {
+				called = true
+				return nil
+			})(c)
+
+			assert.Equal(t, tc.wantOK, called)
+			if tc.wantOK {
+				assert.NoError(t, err)
+				return
+			}
+			var httpErr *errs.HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			assert.Equal(t, http.StatusForbidden, httpErr.Status)
+			assert.Equal(t, "REAUTHENTICATION_REQUIRED", httpErr.Code)
+			require.NotNil(t, httpErr.Action)
+			assert.Equal(t, "/reverify?max_age=600", httpErr.Action.Value)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package admin holds the reports produced by the operator commands.
+package admin
+
+import "time"
+
+// UserSummary describes a user with counts of what they own.
+// ID is the internal user ID and ExternalAuthID the Clerk user ID.
+type UserSummary struct {
+	ID             string     `json:"id"`
+	ExternalAuthID string     `json:"externalAuthId"`
+	Email          *string    `json:"email,omitempty"`
+	Vaults         int64      `json:"vaults"`
+	Devices        int64      `json:"devices"`
+	LastActiveAt   *time.Time `json:"lastActiveAt,omitempty"`
+}
+
+// VaultStats counts what a vault holds
+type VaultStats struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	UserID          string     `json:"userId"`
+	OrgID           *string    `json:"orgId,omitempty"`
+	Secrets         int64      `json:"secrets"`
+	Members         int64      `json:"members"`
+	Attachments     int64      `json:"attachments"`
+	AttachmentBytes int64      `json:"attachmentBytes"`
+	LastActivityAt  *time.Time `json:"lastActivityAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// AuditCheck is the result of one audit log consistency check. Sample holds
+// up to a few IDs of offending rows.
+type AuditCheck struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Count       int64    `json:"count"`
+	Sample      []string `json:"sample,omitempty"`
+}
+
+// Passed reports whether the check found nothing
+func (c *AuditCheck) Passed() bool {
+	return c.Count == 0
+}
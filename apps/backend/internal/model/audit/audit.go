@@ -9,15 +9,48 @@ const (
 	ActionView   Action = "view"
 	ActionUpdate Action = "update"
 	ActionDelete Action = "delete"
+
+	ActionShareCreate Action = "share_create"
+	ActionShareView   Action = "share_view"
+	ActionShareRevoke Action = "share_revoke"
+
+	ActionFolderCreate Action = "folder_create"
+	ActionFolderRename Action = "folder_rename"
+	ActionFolderMove   Action = "folder_move"
+	ActionFolderDelete Action = "folder_delete"
+	ActionSecretMove   Action = "secret_move"
+	ActionSecretCopy   Action = "secret_copy"
+
+	// ActionSecretBatch summarizes a batch request; each item is also logged with its own action
+	ActionSecretBatch Action = "secret_batch"
+)
+
+type ActorType string
+
+const (
+	ActorTypeUser           ActorType = "user"
+	ActorTypeServiceAccount ActorType = "service_account"
+	// ActorTypeAnonymous marks events caused by someone without an account, e.g. a share recipient
+	ActorTypeAnonymous ActorType = "anonymous"
 )
 
 type AuditLog struct {
-	ID        string    `json:"id" db:"id"`
-	UserID    string    `json:"userId" db:"user_id"`
-	VaultID   *string   `json:"vaultId,omitempty" db:"vault_id"`
-	SecretID  *string   `json:"secretId,omitempty" db:"secret_id"`
-	Action    Action    `json:"action" db:"action"`
-	IPAddress *string   `json:"ipAddress,omitempty" db:"ip_address"`
-	UserAgent *string   `json:"userAgent,omitempty" db:"user_agent"`
-	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	ID        string         `json:"id" db:"id"`
+	UserID    string         `json:"userId" db:"user_id"`
+	ActorType ActorType      `json:"actorType" db:"actor_type"`
+	OrgID     *string        `json:"orgId,omitempty" db:"org_id"`
+	VaultID   *string        `json:"vaultId,omitempty" db:"vault_id"`
+	SecretID  *string        `json:"secretId,omitempty" db:"secret_id"`
+	Action    Action         `json:"action" db:"action"`
+	IPAddress *string        `json:"ipAddress,omitempty" db:"ip_address"`
+	UserAgent *string        `json:"userAgent,omitempty" db:"user_agent"`
+	Metadata  map[string]any `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt time.Time      `json:"createdAt" db:"created_at"`
+}
+
+// Checkpoint is how far a forwarder has read the audit log: the last entry it
+// sent, in (CreatedAt, ID) order
+type Checkpoint struct {
+	CreatedAt time.Time
+	ID        string
 }
@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+// Request to list audit logs
+type ListAuditLogsRequest struct {
+	Limit *int `query:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// Request to export the audit logs created from From up to To (RFC 3339)
+type ExportAuditLogsRequest struct {
+	Format ExportFormat `query:"format" validate:"omitempty,oneof=csv ndjson"`
+	From   time.Time    `query:"from" validate:"required"`
+	// To defaults to now
+	To *time.Time `query:"to"`
+}
+
+func (r *ExportAuditLogsRequest) Validate() error {
+	if err := validate.Struct(r); err != nil {
+		return err
+	}
+	if r.To != nil && !r.To.After(r.From) {
+		return validation.CustomValidationErrors{{Field: "to", Message: "must be after from"}}
+	}
+	return nil
+}
+
+// Range - Time range of the export, To defaulting to now
+func (r *ExportAuditLogsRequest) Range() (time.Time, time.Time) {
+	if r.To != nil {
+		return r.From, *r.To
+	}
+	return r.From, time.Now()
+}
@@ -0,0 +1,97 @@
+package folder
+
+import (
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model/secret"
+)
+
+// DeleteMode decides where the contents of a deleted folder go
+type DeleteMode string
+
+const (
+	// DeleteModeUp moves child folders and secrets to the parent folder
+	DeleteModeUp DeleteMode = "up"
+	// DeleteModeTrash moves child folders and secrets to the vault's trash folder
+	DeleteModeTrash DeleteMode = "trash"
+)
+
+// Request to create a folder
+type CreateFolderRequest struct {
+	Name     string  `json:"name" validate:"required,min=1,max=200"`
+	ParentID *string `json:"parentId,omitempty" validate:"omitempty,uuid"`
+}
+
+// Request to rename a folder
+type RenameFolderRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=200"`
+}
+
+// Request to move a folder; a null parentId moves it to the top level
+type MoveFolderRequest struct {
+	ParentID *string `json:"parentId" validate:"omitempty,uuid"`
+}
+
+// Request to delete a folder
+type DeleteFolderRequest struct {
+	Mode DeleteMode `query:"mode" validate:"omitempty,oneof=up trash"`
+}
+
+// Request to list the folder tree of a vault, optionally below one folder
+type ListFoldersRequest struct {
+	ParentID *string `query:"parentId" validate:"omitempty,uuid"`
+}
+
+// Request to move a secret into a folder; a null folderId moves it to the top level
+type MoveSecretRequest struct {
+	FolderID *string `json:"folderId" validate:"omitempty,uuid"`
+}
+
+// Response containing folder data
+type FolderResponse struct {
+	ID        string    `json:"id"`
+	VaultID   string    `json:"vaultId"`
+	ParentID  *string   `json:"parentId,omitempty"`
+	Name      string    `json:"name"`
+	IsTrash   bool      `json:"isTrash"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SecretSummary lists a secret in a folder tree without its encrypted payload
+type SecretSummary struct {
+	ID        string            `json:"id"`
+	FolderID  *string           `json:"-"`
+	Type      secret.SecretType `json:"type"`
+	Title     string            `json:"title"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+// FolderNode is a folder with its nested folders and secrets
+type FolderNode struct {
+	FolderResponse
+	Children []*FolderNode    `json:"children"`
+	Secrets  []*SecretSummary `json:"secrets"`
+}
+
+// Response containing a folder tree. Secrets holds the secrets directly at
+// the root of the listing (top level of the vault, or inside parentId).
+type FolderTreeResponse struct {
+	VaultID  string           `json:"vaultId"`
+	ParentID *string          `json:"parentId,omitempty"`
+	Folders  []*FolderNode    `json:"folders"`
+	Secrets  []*SecretSummary `json:"secrets"`
+}
+
+// Convert folder model to response
+func ToFolderResponse(f *Folder) *FolderResponse {
+	return &FolderResponse{
+		ID:        f.ID.String(),
+		VaultID:   f.VaultID,
+		ParentID:  f.ParentID,
+		Name:      f.Name,
+		IsTrash:   f.IsTrash,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package folder
+
+import (
+	"github.com/Sameer16536/psvault/internal/model"
+)
+
+// Folder groups secrets inside a vault; folders nest through ParentID
+type Folder struct {
+	model.Base
+	VaultID  string  `json:"vaultId" db:"vault_id"`
+	ParentID *string `json:"parentId,omitempty" db:"parent_id"`
+	Name     string  `json:"name" db:"name"`
+	IsTrash  bool    `json:"isTrash" db:"is_trash"`
+}
+
+// TrashName is the name given to a vault's trash folder
+const TrashName = "Trash"
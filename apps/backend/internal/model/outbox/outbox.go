@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model"
+)
+
+// Kind is how the relay publishes a message
+type Kind string
+
+const (
+	// KindTask messages are enqueued as asynq tasks of type Topic
+	KindTask Kind = "task"
+	// KindEvent messages are published on the Redis channel EventChannel(Topic)
+	KindEvent Kind = "event"
+)
+
+// TopicAuditLogged events carry each audit entry written by a unit of work
+const TopicAuditLogged = "audit:logged"
+
+// Message is a side effect recorded in the same transaction as the change
+// that causes it, published later by the relay
+type Message struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	Kind           Kind            `json:"kind" db:"kind"`
+	Topic          string          `json:"topic" db:"topic"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	IdempotencyKey *string         `json:"idempotencyKey,omitempty" db:"idempotency_key"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	LastError      *string         `json:"lastError,omitempty" db:"last_error"`
+	AvailableAt    time.Time       `json:"availableAt" db:"available_at"`
+	PublishedAt    *time.Time      `json:"publishedAt,omitempty" db:"published_at"`
+	FailedAt       *time.Time      `json:"failedAt,omitempty" db:"failed_at"`
+}
+
+// NewTask - Message enqueuing a task of taskType with payload as JSON
+func NewTask(taskType string, payload any) (*Message, error) {
+	return newMessage(KindTask, taskType, payload)
+}
+
+// NewEvent - Message publishing an event on topic with payload as JSON
+func NewEvent(topic string, payload any) (*Message, error) {
+	return newMessage(KindEvent, topic, payload)
+}
+
+func newMessage(kind Kind, topic string, payload any) (*Message, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Kind: kind, Topic: topic, Payload: b}, nil
+}
+
+// DeliveryID identifies the message to consumers: its idempotency key, or its
+// row ID when it has none. Redeliveries carry the same ID.
+func (m *Message) DeliveryID() string {
+	if m.IdempotencyKey != nil {
+		return *m.IdempotencyKey
+	}
+	return m.ID.String()
+}
+
+// EventChannel is the Redis pub/sub channel events on topic are published to
+func EventChannel(topic string) string {
+	return "events:" + topic
+}
+
+// Event is the envelope published on an event channel
+type Event struct {
+	ID        string          `json:"id"`
+	Topic     string          `json:"topic"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,22 @@
+package secret
+
+import (
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model"
+)
+
+// Attachment is a client-encrypted file stored alongside a secret.
+// The blob itself lives in the storage driver under StorageKey.
+type Attachment struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	SecretID          string     `json:"secretId" db:"secret_id"`
+	OwnerID           string     `json:"-" db:"owner_id"`
+	FileName          string     `json:"fileName" db:"file_name"`
+	ContentType       string     `json:"contentType" db:"content_type"`
+	SizeBytes         int64      `json:"sizeBytes" db:"size_bytes"`
+	StorageKey        string     `json:"-" db:"storage_key"`
+	EncryptionVersion int        `json:"encryptionVersion" db:"encryption_version"`
+	CompletedAt       *time.Time `json:"completedAt,omitempty" db:"completed_at"`
+}
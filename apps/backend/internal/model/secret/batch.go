@@ -0,0 +1,76 @@
+package secret
+
+// MaxBatchOperations caps the number of operations in one batch request
+const MaxBatchOperations = 100
+
+// BatchOp is the kind of a batch operation
+type BatchOp string
+
+const (
+	BatchOpCreate BatchOp = "create"
+	BatchOpUpdate BatchOp = "update"
+	BatchOpMove   BatchOp = "move"
+	BatchOpDelete BatchOp = "delete"
+	BatchOpTag    BatchOp = "tag"
+)
+
+// BatchStatus is the outcome of one batch operation
+type BatchStatus string
+
+const (
+	BatchStatusOK    BatchStatus = "ok"
+	BatchStatusError BatchStatus = "error"
+	// BatchStatusRolledBack marks operations that succeeded but were undone
+	// because another operation of an atomic batch failed
+	BatchStatusRolledBack BatchStatus = "rolled_back"
+)
+
+// Request to run several secret operations in one transaction
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations" validate:"required,min=1,max=100,dive"`
+	// Atomic rolls back every operation if any of them fails
+	Atomic bool `json:"atomic"`
+}
+
+// BatchOperation is one item of a batch; the field matching Op carries its input
+type BatchOperation struct {
+	Op       BatchOp              `json:"op" validate:"required,oneof=create update move delete tag"`
+	SecretID *string              `json:"secretId,omitempty" validate:"required_unless=Op create,omitempty,uuid"`
+	Create   *CreateSecretRequest `json:"create,omitempty" validate:"required_if=Op create"`
+	Update   *UpdateSecretRequest `json:"update,omitempty" validate:"required_if=Op update"`
+	Move     *BatchMove           `json:"move,omitempty" validate:"required_if=Op move"`
+	Tags     *BatchTags           `json:"tags,omitempty" validate:"required_if=Op tag"`
+}
+
+// BatchMove moves a secret to a folder and/or another vault. Moving to another
+// vault requires the payload re-encrypted with that vault's key.
+type BatchMove struct {
+	VaultID           *string `json:"vaultId,omitempty" validate:"omitempty,uuid"`
+	FolderID          *string `json:"folderId,omitempty" validate:"omitempty,uuid"`
+	EncryptedPayload  *[]byte `json:"encryptedPayload,omitempty"`
+	EncryptionVersion *int    `json:"encryptionVersion,omitempty" validate:"omitempty,min=1"`
+}
+
+// BatchTags adds and removes tags
+type BatchTags struct {
+	Add    []string `json:"add,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	Remove []string `json:"remove,omitempty" validate:"omitempty,dive,min=1,max=50"`
+}
+
+// BatchItemResult is the outcome of one batch operation
+type BatchItemResult struct {
+	Index    int         `json:"index"`
+	Op       BatchOp     `json:"op"`
+	SecretID *string     `json:"secretId,omitempty"`
+	Status   BatchStatus `json:"status"`
+	Error    *string     `json:"error,omitempty"`
+}
+
+// Response describing the outcome of a batch
+type BatchResponse struct {
+	Atomic    bool               `json:"atomic"`
+	Committed bool               `json:"committed"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Results   []*BatchItemResult `json:"results"`
+}
@@ -7,10 +7,12 @@ import (
 // Request to create a new secret
 type CreateSecretRequest struct {
 	VaultID           string            `json:"vaultId" validate:"required,uuid"`
-	Type              SecretType        `json:"type" validate:"required,oneof=password note api_key card"`
+	FolderID          *string           `json:"folderId,omitempty" validate:"omitempty,uuid"`
+	Type              SecretType        `json:"type" validate:"required,secret_type"`
 	EncryptedPayload  []byte            `json:"encryptedPayload" validate:"required"`
 	EncryptionVersion int               `json:"encryptionVersion" validate:"required,min=1"`
 	Metadata          SecretMetadataDTO `json:"metadata" validate:"required"`
+	Policy            *SecretPolicyDTO  `json:"policy,omitempty"`
 }
 
 // Request to update a secret
@@ -18,33 +20,98 @@ type UpdateSecretRequest struct {
 	EncryptedPayload  *[]byte            `json:"encryptedPayload,omitempty"`
 	EncryptionVersion *int               `json:"encryptionVersion,omitempty" validate:"omitempty,min=1"`
 	Metadata          *SecretMetadataDTO `json:"metadata,omitempty"`
+	Policy            *SecretPolicyDTO   `json:"policy,omitempty"`
+}
+
+// TransferMode selects whether a transfer moves the secret or copies it
+type TransferMode string
+
+const (
+	TransferModeMove TransferMode = "move"
+	TransferModeCopy TransferMode = "copy"
+)
+
+// Request to move or copy a secret to another vault. The client decrypts the
+// payload with the source vault's key and re-encrypts it for the target vault.
+type TransferSecretRequest struct {
+	Mode              TransferMode `json:"mode" validate:"required,oneof=move copy"`
+	VaultID           string       `json:"vaultId" validate:"required,uuid"`
+	FolderID          *string      `json:"folderId,omitempty" validate:"omitempty,uuid"`
+	EncryptedPayload  []byte       `json:"encryptedPayload" validate:"required"`
+	EncryptionVersion int          `json:"encryptionVersion" validate:"required,min=1"`
 }
 
 // Metadata for a secret
 type SecretMetadataDTO struct {
-	Title  string   `json:"title" validate:"required,min=1,max=200"`
-	Domain *string  `json:"domain,omitempty" validate:"omitempty,max=255"`
-	Tags   []string `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	Title        string            `json:"title" validate:"required,min=1,max=200"`
+	Domain       *string           `json:"domain,omitempty" validate:"omitempty,max=255"`
+	Tags         []string          `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	Attributes   map[string]any    `json:"attributes,omitempty"`
+	CustomFields map[string]string `json:"customFields,omitempty" validate:"omitempty,max=20,dive,keys,min=1,max=50,endkeys,max=500"`
 }
 
 // Response containing secret data
 type SecretResponse struct {
 	ID                string            `json:"id"`
 	VaultID           string            `json:"vaultId"`
+	FolderID          *string           `json:"folderId,omitempty"`
 	Type              SecretType        `json:"type"`
 	EncryptedPayload  []byte            `json:"encryptedPayload"`
 	EncryptionVersion int               `json:"encryptionVersion"`
 	Metadata          SecretMetadataDTO `json:"metadata"`
 	LastAccessedAt    *time.Time        `json:"lastAccessedAt,omitempty"`
+	Status            SecretStatus      `json:"status"`
+	ExpiresAt         *time.Time        `json:"expiresAt,omitempty"`
+	RotateEveryDays   *int              `json:"rotateEveryDays,omitempty"`
+	RotatedAt         time.Time         `json:"rotatedAt"`
+	RotationDueAt     *time.Time        `json:"rotationDueAt,omitempty"`
 	CreatedAt         time.Time         `json:"createdAt"`
 	UpdatedAt         time.Time         `json:"updatedAt"`
 }
 
-// Request to search secrets
+// Request to search secrets; Q uses the search syntax, e.g. `type:api_key aws env:prod -tag:legacy`
 type SearchSecretsRequest struct {
 	VaultID *string     `query:"vaultId" validate:"omitempty,uuid"`
-	Type    *SecretType `query:"type" validate:"omitempty,oneof=password note api_key card"`
+	Type    *SecretType `query:"type" validate:"omitempty,secret_type"`
 	Title   *string     `query:"title" validate:"omitempty,max=200"`
 	Domain  *string     `query:"domain" validate:"omitempty,max=255"`
 	Tags    []string    `query:"tags" validate:"omitempty,dive,min=1,max=50"`
+	Q       *string     `query:"q" validate:"omitempty,min=1,max=500"`
+}
+
+// Request to upload an attachment; the encrypted file is streamed as the raw request body
+type UploadAttachmentRequest struct {
+	FileName          string  `query:"name" validate:"required,min=1,max=255"`
+	ContentType       *string `query:"contentType" validate:"omitempty,max=255"`
+	EncryptionVersion *int    `query:"encryptionVersion" validate:"omitempty,min=1"`
+}
+
+// Response describing an attachment
+type AttachmentResponse struct {
+	ID                string    `json:"id"`
+	SecretID          string    `json:"secretId"`
+	FileName          string    `json:"fileName"`
+	ContentType       string    `json:"contentType"`
+	SizeBytes         int64     `json:"sizeBytes"`
+	EncryptionVersion int       `json:"encryptionVersion"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// Response describing the caller's attachment storage usage
+type AttachmentUsageResponse struct {
+	UsedBytes  int64 `json:"usedBytes"`
+	QuotaBytes int64 `json:"quotaBytes"`
+}
+
+// Convert attachment model to response
+func ToAttachmentResponse(a *Attachment) *AttachmentResponse {
+	return &AttachmentResponse{
+		ID:                a.ID.String(),
+		SecretID:          a.SecretID,
+		FileName:          a.FileName,
+		ContentType:       a.ContentType,
+		SizeBytes:         a.SizeBytes,
+		EncryptionVersion: a.EncryptionVersion,
+		CreatedAt:         a.CreatedAt,
+	}
 }
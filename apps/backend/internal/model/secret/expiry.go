@@ -0,0 +1,88 @@
+package secret
+
+import "time"
+
+// SecretStatus summarizes a secret's expiry and rotation policy
+type SecretStatus string
+
+const (
+	SecretStatusActive SecretStatus = "active"
+	// SecretStatusExpiring - expires or is due for rotation within the warning window
+	SecretStatusExpiring SecretStatus = "expiring"
+	// SecretStatusRotationDue - not rotated within rotate_every_days
+	SecretStatusRotationDue SecretStatus = "rotation_due"
+	SecretStatusExpired     SecretStatus = "expired"
+)
+
+// RotationDueAt returns when the secret should next be rotated, or nil without a rotation policy
+func (s *Secret) RotationDueAt() *time.Time {
+	if s.RotateEveryDays == nil {
+		return nil
+	}
+	due := s.RotatedAt.AddDate(0, 0, *s.RotateEveryDays)
+	return &due
+}
+
+// DueAt returns the earlier of the secret's expiry and rotation due date, or nil without a policy
+func (s *Secret) DueAt() *time.Time {
+	due := s.RotationDueAt()
+	if s.ExpiresAt != nil && (due == nil || s.ExpiresAt.Before(*due)) {
+		due = s.ExpiresAt
+	}
+	return due
+}
+
+// Status returns the secret's status at now; anything due within window counts as expiring
+func (s *Secret) Status(now time.Time, window time.Duration) SecretStatus {
+	if s.ExpiresAt != nil && !s.ExpiresAt.After(now) {
+		return SecretStatusExpired
+	}
+	if due := s.RotationDueAt(); due != nil && !due.After(now) {
+		return SecretStatusRotationDue
+	}
+	if due := s.DueAt(); due != nil && !due.After(now.Add(window)) {
+		return SecretStatusExpiring
+	}
+	return SecretStatusActive
+}
+
+// Expiry and rotation policy of a secret. When sent on update it replaces the
+// whole policy, so omitted fields are cleared.
+type SecretPolicyDTO struct {
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	RotateEveryDays *int       `json:"rotateEveryDays,omitempty" validate:"omitempty,min=1,max=3650"`
+}
+
+// Apply sets the policy on s
+func (p *SecretPolicyDTO) Apply(s *Secret) {
+	s.ExpiresAt = p.ExpiresAt
+	s.RotateEveryDays = p.RotateEveryDays
+}
+
+// Request to list secrets that are expired, expiring or due for rotation
+type ListExpiringRequest struct {
+	VaultID    *string `query:"vaultId" validate:"omitempty,uuid"`
+	WithinDays *int    `query:"withinDays" validate:"omitempty,min=0,max=365"`
+}
+
+// Response describing a secret's expiry and rotation, without its payload
+type ExpiringSecretResponse struct {
+	ID              string       `json:"id"`
+	VaultID         string       `json:"vaultId"`
+	Title           string       `json:"title"`
+	Type            SecretType   `json:"type"`
+	Status          SecretStatus `json:"status"`
+	ExpiresAt       *time.Time   `json:"expiresAt,omitempty"`
+	RotateEveryDays *int         `json:"rotateEveryDays,omitempty"`
+	RotatedAt       time.Time    `json:"rotatedAt"`
+	RotationDueAt   *time.Time   `json:"rotationDueAt,omitempty"`
+}
+
+// ExpiryDigestRow is a secret needing attention, addressed to the owner of its
+// vault. OwnerID is the owner's Clerk user ID, which their email is looked up by.
+type ExpiryDigestRow struct {
+	OwnerID   string
+	VaultName string
+	Secret    *Secret
+	Title     string
+}
@@ -0,0 +1,83 @@
+package secret
+
+import "time"
+
+// PasswordHealth holds the health signals a client computed for a secret's password.
+// None of them reveal the password: StrengthScore is a 0-4 estimate and
+// ReuseFingerprint is an HMAC under a key derived from the vault key.
+type PasswordHealth struct {
+	SecretID         string     `json:"secretId" db:"secret_id"`
+	StrengthScore    int        `json:"strengthScore" db:"strength_score"`
+	ReuseFingerprint *string    `json:"reuseFingerprint,omitempty" db:"reuse_fingerprint"`
+	LastRotatedAt    *time.Time `json:"lastRotatedAt,omitempty" db:"last_rotated_at"`
+	UpdatedAt        time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// PasswordHealthRow is one secret of a vault as seen by the health report.
+// The signals are nil for secrets the client has not scored; LastRotatedAt
+// falls back to when the secret's current payload was stored.
+type PasswordHealthRow struct {
+	SecretID         string
+	Title            string
+	Type             SecretType
+	FolderID         *string
+	StrengthScore    *int
+	ReuseFingerprint *string
+	LastRotatedAt    time.Time
+	LastAccessedAt   *time.Time
+}
+
+// Request to set the health signals of a secret
+type SetPasswordHealthRequest struct {
+	StrengthScore    *int       `json:"strengthScore" validate:"required,min=0,max=4"`
+	ReuseFingerprint *string    `json:"reuseFingerprint,omitempty" validate:"omitempty,hexadecimal,min=32,max=128"`
+	LastRotatedAt    *time.Time `json:"lastRotatedAt,omitempty"`
+}
+
+// Request for a vault health report; RotationDays overrides the configured rotation window
+type VaultHealthRequest struct {
+	RotationDays *int `query:"rotationDays" validate:"omitempty,min=1,max=3650"`
+}
+
+// Policy applied by a health report
+type HealthPolicy struct {
+	RotationDays int `json:"rotationDays"`
+	WeakScore    int `json:"weakScore"`
+}
+
+// Counts of a health report. A secret can be weak, reused and old at once.
+type HealthSummary struct {
+	Secrets     int `json:"secrets"`
+	WithSignals int `json:"withSignals"`
+	Weak        int `json:"weak"`
+	Reused      int `json:"reused"`
+	Old         int `json:"old"`
+}
+
+// One secret listed in a health report
+type HealthEntry struct {
+	SecretID       string     `json:"secretId"`
+	Title          string     `json:"title"`
+	Type           SecretType `json:"type"`
+	FolderID       *string    `json:"folderId,omitempty"`
+	StrengthScore  *int       `json:"strengthScore,omitempty"`
+	LastRotatedAt  time.Time  `json:"lastRotatedAt"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt,omitempty"`
+}
+
+// Secrets of a vault sharing one password
+type ReusedGroup struct {
+	Count   int            `json:"count"`
+	Secrets []*HealthEntry `json:"secrets"`
+}
+
+// Response containing the password health of a vault
+type VaultHealthReport struct {
+	VaultID     string         `json:"vaultId"`
+	GeneratedAt time.Time      `json:"generatedAt"`
+	Policy      HealthPolicy   `json:"policy"`
+	Summary     HealthSummary  `json:"summary"`
+	Reused      []*ReusedGroup `json:"reused"`
+	Weak        []*HealthEntry `json:"weak"`
+	Old         []*HealthEntry `json:"old"`
+}
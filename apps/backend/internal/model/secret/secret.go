@@ -10,8 +10,12 @@ type Secret struct {
 	model.Base
 
 	VaultID           string     `json:"vaultId" db:"vault_id"`
+	FolderID          *string    `json:"folderId,omitempty" db:"folder_id"`
 	Type              SecretType `json:"type" db:"type"`
 	EncryptedPayload  []byte     `json:"-" db:"encrypted_payload"`
 	EncryptionVersion int        `json:"encryptionVersion" db:"encryption_version"`
 	LastAccessedAt    *time.Time `json:"lastAccessedAt,omitempty" db:"last_accessed_at"`
+	ExpiresAt         *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
+	RotateEveryDays   *int       `json:"rotateEveryDays,omitempty" db:"rotate_every_days"`
+	RotatedAt         time.Time  `json:"rotatedAt" db:"rotated_at"`
 }
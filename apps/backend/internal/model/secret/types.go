@@ -1,10 +1,51 @@
 package secret
 
+import "time"
+
+// SecretType names a row in the secret_types table
 type SecretType string
 
+// Built-in secret types seeded by migration 011; more can be added as rows
 const (
 	SecretTypePassword SecretType = "password"
 	SecretTypeNote     SecretType = "note"
 	SecretTypeAPIKey   SecretType = "api_key"
 	SecretTypeCard     SecretType = "card"
+	SecretTypeSSHKey   SecretType = "ssh_key"
+	SecretTypeTOTP     SecretType = "totp"
+	SecretTypeIdentity SecretType = "identity"
+	SecretTypeDatabase SecretType = "database"
 )
+
+// FieldKind is the value type of a metadata attribute
+type FieldKind string
+
+const (
+	FieldKindText     FieldKind = "text"
+	FieldKindURL      FieldKind = "url"
+	FieldKindHostname FieldKind = "hostname"
+	FieldKindEmail    FieldKind = "email"
+	FieldKindInteger  FieldKind = "integer"
+)
+
+// FieldSchema describes one plaintext metadata attribute of a secret type
+type FieldSchema struct {
+	Name      string    `json:"name"`
+	Label     string    `json:"label"`
+	Kind      FieldKind `json:"kind"`
+	Required  bool      `json:"required,omitempty"`
+	MaxLength int       `json:"maxLength,omitempty"`
+	Pattern   string    `json:"pattern,omitempty"`
+	Min       *int64    `json:"min,omitempty"`
+	Max       *int64    `json:"max,omitempty"`
+}
+
+// TypeDefinition is a registered secret type and its metadata schema
+type TypeDefinition struct {
+	Name        SecretType    `json:"name" db:"name"`
+	DisplayName string        `json:"displayName" db:"display_name"`
+	Description *string       `json:"description,omitempty" db:"description"`
+	Fields      []FieldSchema `json:"fields" db:"fields"`
+	CreatedAt   time.Time     `json:"-" db:"created_at"`
+	UpdatedAt   time.Time     `json:"-" db:"updated_at"`
+}
@@ -0,0 +1,60 @@
+package serviceaccount
+
+import (
+	"time"
+)
+
+// Request to create a service account in the caller's active organization
+type CreateServiceAccountRequest struct {
+	Name        string  `json:"name" validate:"required,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+}
+
+// Response containing service account data
+type ServiceAccountResponse struct {
+	ID          string     `json:"id"`
+	OrgID       string     `json:"orgId"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	CreatedBy   string     `json:"createdBy"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	DisabledAt  *time.Time `json:"disabledAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// Response returned once on creation; the client secret cannot be retrieved again
+type ServiceAccountCredentialsResponse struct {
+	ServiceAccountResponse
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
+
+// Client-credentials token request (RFC 6749 section 4.4), accepted as JSON or form data
+type TokenRequest struct {
+	GrantType    string `json:"grant_type" form:"grant_type" validate:"required,eq=client_credentials"`
+	ClientID     string `json:"client_id" form:"client_id" validate:"required,uuid"`
+	ClientSecret string `json:"client_secret" form:"client_secret" validate:"required"`
+}
+
+// Client-credentials token response (RFC 6749 section 5.1)
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Convert service account model to response
+func ToServiceAccountResponse(sa *ServiceAccount) *ServiceAccountResponse {
+	return &ServiceAccountResponse{
+		ID:          sa.ID.String(),
+		OrgID:       sa.OrgID,
+		Name:        sa.Name,
+		Description: sa.Description,
+		CreatedBy:   sa.CreatedBy,
+		LastUsedAt:  sa.LastUsedAt,
+		DisabledAt:  sa.DisabledAt,
+		CreatedAt:   sa.CreatedAt,
+		UpdatedAt:   sa.UpdatedAt,
+	}
+}
@@ -0,0 +1,35 @@
+package serviceaccount
+
+import (
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model"
+)
+
+const (
+	// ClientSecretPrefix marks client secrets so they are recognisable in logs and scanners
+	ClientSecretPrefix = "psv_sk_"
+	// AccessTokenPrefix marks short-lived service account bearer tokens
+	AccessTokenPrefix = "psv_at_"
+)
+
+type ServiceAccount struct {
+	model.Base
+
+	OrgID            string     `json:"orgId" db:"org_id"`
+	Name             string     `json:"name" db:"name"`
+	Description      *string    `json:"description,omitempty" db:"description"`
+	CreatedBy        string     `json:"createdBy" db:"created_by"`
+	ClientSecretHash []byte     `json:"-" db:"client_secret_hash"`
+	LastUsedAt       *time.Time `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	DisabledAt       *time.Time `json:"disabledAt,omitempty" db:"disabled_at"`
+}
+
+type Token struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+
+	ServiceAccountID string    `json:"serviceAccountId" db:"service_account_id"`
+	TokenHash        []byte    `json:"-" db:"token_hash"`
+	ExpiresAt        time.Time `json:"expiresAt" db:"expires_at"`
+}
@@ -0,0 +1,46 @@
+package share
+
+import (
+	"time"
+)
+
+// Request to create a share link
+type CreateShareRequest struct {
+	// Payload encrypted client-side with a key the server never sees
+	EncryptedPayload []byte `json:"encryptedPayload" validate:"required,max=65536"`
+	MaxViews         *int   `json:"maxViews,omitempty" validate:"omitempty,min=1,max=100"`
+	// Lifetime in seconds, between 5 minutes and 30 days (default 1 day)
+	ExpiresIn *int    `json:"expiresIn,omitempty" validate:"omitempty,min=300,max=2592000"`
+	Password  *string `json:"password,omitempty" validate:"omitempty,min=4,max=72"`
+}
+
+// Response describing a share link owned by the caller
+type ShareResponse struct {
+	ID                string     `json:"id"`
+	MaxViews          int        `json:"maxViews"`
+	ViewsRemaining    int        `json:"viewsRemaining"`
+	PasswordProtected bool       `json:"passwordProtected"`
+	ExpiresAt         time.Time  `json:"expiresAt"`
+	PurgedAt          *time.Time `json:"purgedAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+}
+
+// Response returned to the recipient of a share link
+type ShareContentResponse struct {
+	EncryptedPayload []byte    `json:"encryptedPayload"`
+	ViewsRemaining   int       `json:"viewsRemaining"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// Convert share model to response
+func ToShareResponse(s *Share) *ShareResponse {
+	return &ShareResponse{
+		ID:                s.ID.String(),
+		MaxViews:          s.MaxViews,
+		ViewsRemaining:    s.ViewsRemaining,
+		PasswordProtected: len(s.PasswordHash) > 0,
+		ExpiresAt:         s.ExpiresAt,
+		PurgedAt:          s.PurgedAt,
+		CreatedAt:         s.CreatedAt,
+	}
+}
@@ -0,0 +1,20 @@
+package share
+
+import (
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model"
+)
+
+// Share is a view- and time-limited link to a client-encrypted payload
+type Share struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	OwnerID          string     `json:"ownerId" db:"owner_id"`
+	EncryptedPayload []byte     `json:"-" db:"encrypted_payload"`
+	MaxViews         int        `json:"maxViews" db:"max_views"`
+	ViewsRemaining   int        `json:"viewsRemaining" db:"views_remaining"`
+	ExpiresAt        time.Time  `json:"expiresAt" db:"expires_at"`
+	PasswordHash     []byte     `json:"-" db:"password_hash"`
+	PurgedAt         *time.Time `json:"purgedAt,omitempty" db:"purged_at"`
+}
@@ -6,11 +6,13 @@ import (
 	"github.com/Sameer16536/psvault/internal/model"
 )
 
+// User is the internal identity of a Clerk user. Everything a user owns
+// references ID; ExternalAuthID is the Clerk user ID.
 type User struct {
 	model.Base
 
 	ExternalAuthID string     `json:"externalAuthId" db:"external_auth_id"`
-	Email          string     `json:"email" db:"email"`
+	Email          *string    `json:"email,omitempty" db:"email"`
 	Name           *string    `json:"name,omitempty" db:"name"`
 	LastLoginAt    *time.Time `json:"lastLoginAt,omitempty" db:"last_login_at"`
 }
@@ -4,6 +4,8 @@ package vault
 
 import (
 	"time"
+
+	"github.com/Sameer16536/psvault/internal/model/audit"
 )
 
 // Request to create a new vault
@@ -12,6 +14,8 @@ type CreateVaultRequest struct {
 	Description          *string `json:"description,omitempty" validate:"omitempty,max=500"`
 	EncryptedKey         []byte  `json:"encryptedKey,omitempty"`
 	KeyEncryptionVersion *int    `json:"keyEncryptionVersion,omitempty"`
+	// OrgID creates the vault in the caller's active organization instead of as a personal vault
+	OrgID *string `json:"orgId,omitempty" validate:"omitempty,max=255"`
 }
 
 // Request to Update vault
@@ -24,6 +28,7 @@ type UpdateVaultRequest struct {
 type VaultResponse struct {
 	ID                   string    `json:"id"`
 	UserID               string    `json:"userId"`
+	OrgID                *string   `json:"orgId,omitempty"`
 	Name                 string    `json:"name"`
 	Description          *string   `json:"description,omitempty"`
 	EncryptedKey         []byte    `json:"encryptedKey,omitempty"`
@@ -37,6 +42,7 @@ func ToVaultResponse(v *Vault) *VaultResponse {
 	return &VaultResponse{
 		ID:                   v.ID.String(),
 		UserID:               v.UserID,
+		OrgID:                v.OrgID,
 		Name:                 v.Name,
 		Description:          v.Description,
 		EncryptedKey:         v.EncryptedKey,
@@ -45,3 +51,39 @@ func ToVaultResponse(v *Vault) *VaultResponse {
 		UpdatedAt:            v.UpdatedAt,
 	}
 }
+
+// Request to grant a principal access to a vault
+type AddMemberRequest struct {
+	MemberType   audit.ActorType `json:"memberType" validate:"required,oneof=user service_account"`
+	MemberID     string          `json:"memberId" validate:"required,max=255"`
+	Role         MemberRole      `json:"role" validate:"required,oneof=read write"`
+	EncryptedKey []byte          `json:"encryptedKey,omitempty"`
+}
+
+// Response containing vault member data
+type MemberResponse struct {
+	ID           string          `json:"id"`
+	VaultID      string          `json:"vaultId"`
+	MemberType   audit.ActorType `json:"memberType"`
+	MemberID     string          `json:"memberId"`
+	Role         MemberRole      `json:"role"`
+	EncryptedKey []byte          `json:"encryptedKey,omitempty"`
+	GrantedBy    string          `json:"grantedBy"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+}
+
+// Convert vault member model to response
+func ToMemberResponse(m *Member) *MemberResponse {
+	return &MemberResponse{
+		ID:           m.ID.String(),
+		VaultID:      m.VaultID,
+		MemberType:   m.MemberType,
+		MemberID:     m.MemberID,
+		Role:         m.Role,
+		EncryptedKey: m.EncryptedKey,
+		GrantedBy:    m.GrantedBy,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
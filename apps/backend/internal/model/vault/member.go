@@ -0,0 +1,25 @@
+package vault
+
+import (
+	"github.com/Sameer16536/psvault/internal/model"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+)
+
+type MemberRole string
+
+const (
+	MemberRoleRead  MemberRole = "read"
+	MemberRoleWrite MemberRole = "write"
+)
+
+// Member grants a principal other than the owner access to a vault
+type Member struct {
+	model.Base
+
+	VaultID      string          `json:"vaultId" db:"vault_id"`
+	MemberType   audit.ActorType `json:"memberType" db:"member_type"`
+	MemberID     string          `json:"memberId" db:"member_id"`
+	Role         MemberRole      `json:"role" db:"role"`
+	EncryptedKey []byte          `json:"encryptedKey,omitempty" db:"encrypted_key"`
+	GrantedBy    string          `json:"grantedBy" db:"granted_by"`
+}
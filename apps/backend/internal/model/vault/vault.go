@@ -6,6 +6,7 @@ type Vault struct {
 	model.Base
 
 	UserID               string  `json:"userId" db:"user_id"`
+	OrgID                *string `json:"orgId,omitempty" db:"org_id"`
 	Name                 string  `json:"name" db:"name"`
 	Description          *string `json:"description,omitempty" db:"description"`
 	EncryptedKey         []byte  `json:"encryptedKey,omitempty" db:"encrypted_key"`
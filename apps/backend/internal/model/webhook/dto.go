@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Request to create a webhook for the caller's personal vaults, or for the
+// vaults of the active organization when OrgID is set
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=500"`
+	EventTypes  []string `json:"eventTypes,omitempty" validate:"omitempty,max=20,dive,required,max=64"`
+	OrgID       *string  `json:"orgId,omitempty" validate:"omitempty,max=255"`
+}
+
+// Request to update a webhook; enabling a disabled webhook resets its failure count
+type UpdateWebhookRequest struct {
+	URL         *string   `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=500"`
+	EventTypes  *[]string `json:"eventTypes,omitempty" validate:"omitempty,max=20,dive,required,max=64"`
+	Enabled     *bool     `json:"enabled,omitempty"`
+}
+
+// Request to list a webhook's deliveries, newest first
+type ListDeliveriesRequest struct {
+	Limit *int `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// Response containing webhook data
+type WebhookResponse struct {
+	ID                  string     `json:"id"`
+	UserID              string     `json:"userId"`
+	OrgID               *string    `json:"orgId,omitempty"`
+	URL                 string     `json:"url"`
+	Description         *string    `json:"description,omitempty"`
+	EventTypes          []string   `json:"eventTypes"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	DisabledAt          *time.Time `json:"disabledAt,omitempty"`
+	DisabledReason      *string    `json:"disabledReason,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+}
+
+// Response returned once on creation; the signing secret cannot be retrieved again
+type WebhookSecretResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+// Response containing a delivery and the outcome of its latest attempt
+type DeliveryResponse struct {
+	ID            string          `json:"id"`
+	WebhookID     string          `json:"webhookId"`
+	EventID       string          `json:"eventId"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload"`
+	RedeliveryOf  *string         `json:"redeliveryOf,omitempty"`
+	Status        DeliveryStatus  `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  *int            `json:"responseCode,omitempty"`
+	ResponseBody  *string         `json:"responseBody,omitempty"`
+	Error         *string         `json:"error,omitempty"`
+	DurationMs    *int            `json:"durationMs,omitempty"`
+	LastAttemptAt *time.Time      `json:"lastAttemptAt,omitempty"`
+	DeliveredAt   *time.Time      `json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// Convert webhook model to response
+func ToWebhookResponse(w *Webhook) *WebhookResponse {
+	eventTypes := w.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return &WebhookResponse{
+		ID:                  w.ID.String(),
+		UserID:              w.UserID,
+		OrgID:               w.OrgID,
+		URL:                 w.URL,
+		Description:         w.Description,
+		EventTypes:          eventTypes,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		DisabledAt:          w.DisabledAt,
+		DisabledReason:      w.DisabledReason,
+		CreatedAt:           w.CreatedAt,
+		UpdatedAt:           w.UpdatedAt,
+	}
+}
+
+// Convert delivery model to response
+func ToDeliveryResponse(d *Delivery) *DeliveryResponse {
+	return &DeliveryResponse{
+		ID:            d.ID.String(),
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		RedeliveryOf:  d.RedeliveryOf,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		ResponseCode:  d.ResponseCode,
+		ResponseBody:  d.ResponseBody,
+		Error:         d.Error,
+		DurationMs:    d.DurationMs,
+		LastAttemptAt: d.LastAttemptAt,
+		DeliveredAt:   d.DeliveredAt,
+		CreatedAt:     d.CreatedAt,
+	}
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+)
+
+// SecretPrefix marks webhook signing secrets so they are recognisable in logs and scanners
+const SecretPrefix = "whsec_"
+
+// EventTypes are the events webhooks can subscribe to, named <resource>.<action>
+var EventTypes = []string{
+	"vault.create", "vault.update", "vault.delete",
+	"secret.create", "secret.update", "secret.delete",
+}
+
+// Webhook is an endpoint notified of changes to the personal vaults of its
+// owner or, when OrgID is set, to the vaults of an organization
+type Webhook struct {
+	model.Base
+	UserID              string     `json:"userId" db:"user_id"`
+	OrgID               *string    `json:"orgId,omitempty" db:"org_id"`
+	URL                 string     `json:"url" db:"url"`
+	Description         *string    `json:"description,omitempty" db:"description"`
+	EventTypes          []string   `json:"eventTypes" db:"event_types"`
+	Secret              string     `json:"-" db:"secret"`
+	ConsecutiveFailures int        `json:"consecutiveFailures" db:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabledAt,omitempty" db:"disabled_at"`
+	DisabledReason      *string    `json:"disabledReason,omitempty" db:"disabled_reason"`
+}
+
+// Subscribes reports whether the webhook's filters select eventType; filters
+// are exact types or <resource>.*, and no filters select every event
+func (w *Webhook) Subscribes(eventType string) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	resource, _, _ := strings.Cut(eventType, ".")
+	for _, f := range w.EventTypes {
+		if f == eventType || f == resource+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidEventFilter reports whether f names a known event type or resource
+func ValidEventFilter(f string) bool {
+	for _, t := range EventTypes {
+		resource, _, _ := strings.Cut(t, ".")
+		if f == t || f == resource+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is one event sent to one webhook, with the outcome of its latest attempt
+type Delivery struct {
+	model.BaseWithId
+	model.BaseWithCreatedAt
+	WebhookID     string          `json:"webhookId" db:"webhook_id"`
+	EventID       string          `json:"eventId" db:"event_id"`
+	EventType     string          `json:"eventType" db:"event_type"`
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	RedeliveryOf  *string         `json:"redeliveryOf,omitempty" db:"redelivery_of"`
+	Status        DeliveryStatus  `json:"status" db:"status"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	ResponseCode  *int            `json:"responseCode,omitempty" db:"response_code"`
+	ResponseBody  *string         `json:"responseBody,omitempty" db:"response_body"`
+	Error         *string         `json:"error,omitempty" db:"error"`
+	DurationMs    *int            `json:"durationMs,omitempty" db:"duration_ms"`
+	LastAttemptAt *time.Time      `json:"lastAttemptAt,omitempty" db:"last_attempt_at"`
+	DeliveredAt   *time.Time      `json:"deliveredAt,omitempty" db:"delivered_at"`
+}
+
+// Attempt is the outcome of one POST of a delivery
+type Attempt struct {
+	ResponseCode int
+	ResponseBody string
+	Err          error
+	Duration     time.Duration
+}
+
+// Succeeded reports whether the receiver accepted the delivery
+func (a *Attempt) Succeeded() bool {
+	return a.Err == nil && a.ResponseCode >= 200 && a.ResponseCode < 300
+}
+
+// Event is the JSON body POSTed to webhooks. It describes an audit entry and
+// never carries secret payloads or keys.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      EventData `json:"data"`
+}
+
+type EventData struct {
+	Action    audit.Action    `json:"action"`
+	ActorType audit.ActorType `json:"actorType"`
+	ActorID   string          `json:"actorId"`
+	OrgID     *string         `json:"orgId,omitempty"`
+	VaultID   *string         `json:"vaultId,omitempty"`
+	SecretID  *string         `json:"secretId,omitempty"`
+	IPAddress *string         `json:"ipAddress,omitempty"`
+	Metadata  map[string]any  `json:"metadata,omitempty"`
+}
+
+// NewEvent - Webhook event describing an audit entry
+func NewEvent(log *audit.AuditLog) *Event {
+	return &Event{
+		ID:        log.ID,
+		Type:      EventType(log),
+		CreatedAt: log.CreatedAt,
+		Data: EventData{
+			Action:    log.Action,
+			ActorType: log.ActorType,
+			ActorID:   log.UserID,
+			OrgID:     log.OrgID,
+			VaultID:   log.VaultID,
+			SecretID:  log.SecretID,
+			IPAddress: log.IPAddress,
+			Metadata:  audit.RedactMetadata(log.Metadata),
+		},
+	}
+}
+
+// EventType - Name an audit entry as <resource>.<action>, e.g. secret.update.
+// Plain actions apply to the secret when the entry names one and to the vault otherwise.
+func EventType(log *audit.AuditLog) string {
+	action := string(log.Action)
+	if resource, verb, ok := strings.Cut(action, "_"); ok {
+		return resource + "." + verb
+	}
+	if log.SecretID != nil || log.Metadata["secretId"] != nil {
+		return "secret." + action
+	}
+	return "vault." + action
+}
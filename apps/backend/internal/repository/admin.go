@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model/admin"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// auditCheckSampleSize bounds the offending IDs returned per audit check
+const auditCheckSampleSize = 5
+
+// AdminRepository runs the cross-tenant reports behind the operator commands.
+// It is never used on the request path.
+type AdminRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewAdminRepository(s *server.Server) *AdminRepository {
+	return &AdminRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *AdminRepository) WithTx(tx pgx.Tx) *AdminRepository {
+	return &AdminRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *AdminRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
+// ListUsers - List users, most recently active first
+func (r *AdminRepository) ListUsers(ctx context.Context, limit int) ([]*admin.UserSummary, error) {
+	query := `
+		SELECT u.id, u.external_auth_id, u.email,
+			(SELECT COUNT(*) FROM vaults v WHERE v.user_id = u.id),
+			(SELECT COUNT(*) FROM devices d WHERE d.user_id = u.id),
+			(SELECT MAX(a.created_at) FROM audit_logs a WHERE a.user_id = u.id) AS last_active_at
+		FROM users u
+		ORDER BY last_active_at DESC NULLS LAST, u.created_at
+		LIMIT $1
+	`
+	rows, err := r.db(ctx).Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var users []*admin.UserSummary
+	for rows.Next() {
+		var u admin.UserSummary
+		if err := rows.Scan(&u.ID, &u.ExternalAuthID, &u.Email, &u.Vaults, &u.Devices, &u.LastActiveAt); err != nil {
+			return nil, err
+		}
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
+
+// VaultStats - Count the secrets, members and attachments of every vault
+func (r *AdminRepository) VaultStats(ctx context.Context) ([]*admin.VaultStats, error) {
+	query := `
+		SELECT v.id, v.name, v.user_id, v.org_id, v.created_at,
+			(SELECT COUNT(*) FROM secrets s WHERE s.vault_id = v.id),
+			(SELECT COUNT(*) FROM vault_members m WHERE m.vault_id = v.id),
+			COALESCE(att.count, 0), COALESCE(att.bytes, 0),
+			(SELECT MAX(a.created_at) FROM audit_logs a WHERE a.vault_id = v.id)
+		FROM vaults v
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS count, SUM(sa.size_bytes) AS bytes
+			FROM secret_attachments sa
+			JOIN secrets s ON s.id = sa.secret_id
+			WHERE s.vault_id = v.id
+		) att ON TRUE
+		ORDER BY v.created_at
+	`
+	rows, err := r.db(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var stats []*admin.VaultStats
+	for rows.Next() {
+		var s admin.VaultStats
+		if err := rows.Scan(&s.ID, &s.Name, &s.UserID, &s.OrgID, &s.CreatedAt,
+			&s.Secrets, &s.Members, &s.Attachments, &s.AttachmentBytes, &s.LastActivityAt); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &s)
+	}
+	return stats, rows.Err()
+}
+
+// auditChecks find audit records that are missing or inconsistent. Each
+// query selects offending IDs with their total count, looking only at rows
+// created since $1.
+var auditChecks = []struct {
+	name, description, query string
+}{
+	{
+		name:        "vault_create_logged",
+		description: "vaults without a create entry",
+		query: `
+			SELECT v.id::text, COUNT(*) OVER ()
+			FROM vaults v
+			WHERE v.created_at >= $1
+				AND NOT EXISTS (
+					SELECT 1 FROM audit_logs a
+					WHERE a.vault_id = v.id AND a.secret_id IS NULL AND a.action = 'create'
+				)
+			ORDER BY v.created_at
+			LIMIT $2`,
+	},
+	{
+		name:        "secret_create_logged",
+		description: "secrets without a create or copy entry",
+		query: `
+			SELECT s.id::text, COUNT(*) OVER ()
+			FROM secrets s
+			WHERE s.created_at >= $1
+				AND NOT EXISTS (
+					SELECT 1 FROM audit_logs a
+					WHERE a.secret_id = s.id AND a.action IN ('create', 'secret_copy')
+				)
+			ORDER BY s.created_at
+			LIMIT $2`,
+	},
+	{
+		name:        "no_future_timestamps",
+		description: "entries dated in the future",
+		query: `
+			SELECT a.id::text, COUNT(*) OVER ()
+			FROM audit_logs a
+			WHERE a.created_at >= $1 AND a.created_at > NOW() + INTERVAL '5 minutes'
+			ORDER BY a.created_at
+			LIMIT $2`,
+	},
+	{
+		name:        "actors_exist",
+		description: "entries whose user or service account no longer exists",
+		query: `
+			SELECT a.id::text, COUNT(*) OVER ()
+			FROM audit_logs a
+			WHERE a.created_at >= $1 AND a.user_id IS NULL AND a.service_account_id IS NULL
+			ORDER BY a.created_at
+			LIMIT $2`,
+	},
+}
+
+// VerifyAudit - Run the audit log consistency checks over rows created since
+func (r *AdminRepository) VerifyAudit(ctx context.Context, since time.Time) ([]*admin.AuditCheck, error) {
+	results := make([]*admin.AuditCheck, 0, len(auditChecks))
+	for _, check := range auditChecks {
+		result := &admin.AuditCheck{Name: check.name, Description: check.description}
+		rows, err := r.db(ctx).Query(ctx, check.query, since, auditCheckSampleSize)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id, &result.Count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			result.Sample = append(result.Sample, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+// Uploads that never completed stop counting against the quota after this long
+const attachmentReservationTTL = "1 hour"
+
+type AttachmentRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewAttachmentRepository(s *server.Server) *AttachmentRepository {
+	return &AttachmentRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *AttachmentRepository) WithTx(tx pgx.Tx) *AttachmentRepository {
+	return &AttachmentRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *AttachmentRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
+// Reserve - Insert a pending attachment if it fits in the owner's quota.
+// Concurrent reservations for the same owner are serialized with an advisory lock.
+// Returns false if the quota would be exceeded.
+func (r *AttachmentRepository) Reserve(ctx context.Context, a *secret.Attachment, quotaBytes int64) (bool, error) {
+	tx, err := r.db(ctx).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('attachment_quota:' || $1))`, a.OwnerID); err != nil {
+		return false, err
+	}
+	usageQuery := `
+		SELECT COALESCE(SUM(size_bytes), 0)
+		FROM secret_attachments
+		WHERE owner_id = $1
+			AND (completed_at IS NOT NULL OR created_at > NOW() - INTERVAL '` + attachmentReservationTTL + `')
+	`
+	var used int64
+	if err := tx.QueryRow(ctx, usageQuery, a.OwnerID).Scan(&used); err != nil {
+		return false, err
+	}
+	if used+a.SizeBytes > quotaBytes {
+		return false, nil
+	}
+	insertQuery := `
+		INSERT INTO secret_attachments (secret_id, owner_id, file_name, content_type, size_bytes, storage_key, encryption_version)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(ctx, insertQuery, a.SecretID, a.OwnerID, a.FileName, a.ContentType, a.SizeBytes, a.StorageKey, a.EncryptionVersion).
+		Scan(&a.ID, &a.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// MarkCompleted - Mark a reserved attachment as fully uploaded
+func (r *AttachmentRepository) MarkCompleted(ctx context.Context, a *secret.Attachment) error {
+	query := `UPDATE secret_attachments SET completed_at = NOW() WHERE id = $1 RETURNING completed_at`
+	return r.db(ctx).QueryRow(ctx, query, a.ID).Scan(&a.CompletedAt)
+}
+
+// GetByID - Get a completed attachment of a secret
+func (r *AttachmentRepository) GetByID(ctx context.Context, secretID, attachmentID string) (*secret.Attachment, error) {
+	query := `
+		SELECT id, secret_id, owner_id, file_name, content_type, size_bytes, storage_key, encryption_version, completed_at, created_at
+		FROM secret_attachments
+		WHERE id = $1 AND secret_id = $2 AND completed_at IS NOT NULL
+	`
+	a, err := scanAttachment(r.db(ctx).QueryRow(ctx, query, attachmentID, secretID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListBySecretID - List completed attachments of a secret
+func (r *AttachmentRepository) ListBySecretID(ctx context.Context, secretID string) ([]*secret.Attachment, error) {
+	query := `
+		SELECT id, secret_id, owner_id, file_name, content_type, size_bytes, storage_key, encryption_version, completed_at, created_at
+		FROM secret_attachments
+		WHERE secret_id = $1 AND completed_at IS NOT NULL
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, secretID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var attachments []*secret.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+// Delete - Delete an attachment row
+func (r *AttachmentRepository) Delete(ctx context.Context, attachmentID string) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM secret_attachments WHERE id = $1`, attachmentID)
+	return err
+}
+
+// ListStorageKeysBySecretID - Storage keys of every attachment of a secret, including pending ones
+func (r *AttachmentRepository) ListStorageKeysBySecretID(ctx context.Context, secretID string) ([]string, error) {
+	return r.listStorageKeys(ctx, `SELECT storage_key FROM secret_attachments WHERE secret_id = $1`, secretID)
+}
+
+// ListStorageKeysByVaultID - Storage keys of every attachment of every secret in a vault
+func (r *AttachmentRepository) ListStorageKeysByVaultID(ctx context.Context, vaultID string) ([]string, error) {
+	query := `
+		SELECT a.storage_key
+		FROM secret_attachments a
+		JOIN secrets s ON s.id = a.secret_id
+		WHERE s.vault_id = $1
+	`
+	return r.listStorageKeys(ctx, query, vaultID)
+}
+
+// UsageByOwnerID - Total bytes counted against an owner's quota
+func (r *AttachmentRepository) UsageByOwnerID(ctx context.Context, ownerID string) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(size_bytes), 0)
+		FROM secret_attachments
+		WHERE owner_id = $1
+			AND (completed_at IS NOT NULL OR created_at > NOW() - INTERVAL '` + attachmentReservationTTL + `')
+	`
+	var used int64
+	err := r.db(ctx).QueryRow(ctx, query, ownerID).Scan(&used)
+	return used, err
+}
+
+func (r *AttachmentRepository) listStorageKeys(ctx context.Context, query string, arg string) ([]string, error) {
+	rows, err := r.db(ctx).Query(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func scanAttachment(row pgx.Row) (*secret.Attachment, error) {
+	var a secret.Attachment
+	err := row.Scan(
+		&a.ID, &a.SecretID, &a.OwnerID, &a.FileName, &a.ContentType, &a.SizeBytes,
+		&a.StorageKey, &a.EncryptionVersion, &a.CompletedAt, &a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -2,52 +2,199 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/Sameer16536/psvault/internal/model/audit"
 	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
 )
 
 type AuditRepository struct {
 	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
 }
 
 func NewAuditRepository(s *server.Server) *AuditRepository {
 	return &AuditRepository{server: s}
 }
 
-// Log - Create an audit log entry
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *AuditRepository) WithTx(tx pgx.Tx) *AuditRepository {
+	return &AuditRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *AuditRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
+const auditLogColumns = `id, COALESCE(user_id::text, service_account_id::text, ''), actor_type, org_id, vault_id, secret_id, action, host(ip_address), user_agent, metadata, created_at`
+
+// Log - Create an audit log entry. UserID is stored as the user or the service account by ActorType.
+// When OrgID is unset the entry inherits the organization of its vault.
 func (r *AuditRepository) Log(ctx context.Context, log *audit.AuditLog) error {
 	query := `
-		INSERT INTO audit_logs (user_id, vault_id, secret_id, action, ip_address, user_agent)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at
+		INSERT INTO audit_logs (user_id, service_account_id, actor_type, org_id, vault_id, secret_id, action, ip_address, user_agent, metadata)
+		VALUES (
+			CASE WHEN $2::actor_type <> 'service_account' THEN NULLIF($1::text, '')::uuid END,
+			CASE WHEN $2::actor_type = 'service_account' THEN NULLIF($1::text, '')::uuid END,
+			$2, COALESCE($3, (SELECT org_id FROM vaults WHERE id = $4)), $4, $5, $6, $7::text::inet, $8, $9
+		)
+		RETURNING id, org_id, created_at
 	`
-	return r.server.DB.Pool.QueryRow(ctx, query,
-		log.UserID, log.VaultID, log.SecretID, log.Action, log.IPAddress, log.UserAgent,
-	).Scan(&log.ID, &log.CreatedAt)
+	if log.ActorType == "" {
+		log.ActorType = audit.ActorTypeUser
+	}
+	return r.db(ctx).QueryRow(ctx, query,
+		log.UserID, log.ActorType, log.OrgID, log.VaultID, log.SecretID, log.Action, log.IPAddress, log.UserAgent, log.Metadata,
+	).Scan(&log.ID, &log.OrgID, &log.CreatedAt)
 }
 
-// ListByUserID - List audit logs for a user
+// ListByUserID - List audit logs for a user or service account
 func (r *AuditRepository) ListByUserID(ctx context.Context, userID string, limit int) ([]*audit.AuditLog, error) {
 	query := `
-		SELECT id, user_id, vault_id, secret_id, action, ip_address, user_agent, created_at
+		SELECT ` + auditLogColumns + `
 		FROM audit_logs
-		WHERE user_id = $1
+		WHERE user_id = $1 OR service_account_id = $1
 		ORDER BY created_at DESC
 		LIMIT $2
 	`
-	rows, err := r.server.DB.Pool.Query(ctx, query, userID, limit)
+	rows, err := r.db(ctx).Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAuditLogs(rows)
+}
+
+// ListByOrgID - List audit logs for everything that happened in an organization's vaults
+func (r *AuditRepository) ListByOrgID(ctx context.Context, orgID string, limit int) ([]*audit.AuditLog, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE org_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db(ctx).Query(ctx, query, orgID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanAuditLogs(rows)
+}
+
+// StreamByUserID - Call fn with each audit log of a user or service account created
+// from from up to to, oldest first, reading them as fn consumes them
+func (r *AuditRepository) StreamByUserID(ctx context.Context, userID string, from, to time.Time, fn func(*audit.AuditLog) error) error {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE (user_id = $1 OR service_account_id = $1) AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at, id
+	`
+	return r.stream(ctx, fn, query, userID, from, to)
+}
+
+// StreamByOrgID - Call fn with each audit log of an organization created from
+// from up to to, oldest first, reading them as fn consumes them
+func (r *AuditRepository) StreamByOrgID(ctx context.Context, orgID string, from, to time.Time, fn func(*audit.AuditLog) error) error {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE org_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at, id
+	`
+	return r.stream(ctx, fn, query, orgID, from, to)
+}
+
+func (r *AuditRepository) stream(ctx context.Context, fn func(*audit.AuditLog) error, query string, args ...any) error {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ListAfter - List up to limit audit logs following cp (all when nil) and created
+// before until, in (created_at, id) order. Not scoped: used by the forwarder only.
+func (r *AuditRepository) ListAfter(ctx context.Context, cp *audit.Checkpoint, until time.Time, limit int) ([]*audit.AuditLog, error) {
+	if cp == nil {
+		cp = &audit.Checkpoint{ID: "00000000-0000-0000-0000-000000000000"}
+	}
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE (created_at, id) > ($1, $2::uuid) AND created_at < $3
+		ORDER BY created_at, id
+		LIMIT $4
+	`
+	rows, err := r.db(ctx).Query(ctx, query, cp.CreatedAt, cp.ID, until, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
+	return scanAuditLogs(rows)
+}
+
+// GetForwardCheckpoint - Get a forwarder's checkpoint; nil before it has sent anything
+func (r *AuditRepository) GetForwardCheckpoint(ctx context.Context, name string) (*audit.Checkpoint, error) {
+	var cp audit.Checkpoint
+	err := r.db(ctx).QueryRow(ctx, `SELECT last_created_at, last_id FROM audit_forward_checkpoints WHERE name = $1`, name).
+		Scan(&cp.CreatedAt, &cp.ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// SaveForwardCheckpoint - Move a forwarder's checkpoint forward to cp; a
+// checkpoint already past cp is left as it is
+func (r *AuditRepository) SaveForwardCheckpoint(ctx context.Context, name string, cp *audit.Checkpoint) error {
+	query := `
+		INSERT INTO audit_forward_checkpoints (name, last_created_at, last_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE
+		SET last_created_at = EXCLUDED.last_created_at, last_id = EXCLUDED.last_id
+		WHERE (audit_forward_checkpoints.last_created_at, audit_forward_checkpoints.last_id)
+			< (EXCLUDED.last_created_at, EXCLUDED.last_id)
+	`
+	_, err := r.db(ctx).Exec(ctx, query, name, cp.CreatedAt, cp.ID)
+	return err
+}
+
+func scanAuditLog(row pgx.Row) (*audit.AuditLog, error) {
+	var log audit.AuditLog
+	if err := row.Scan(&log.ID, &log.UserID, &log.ActorType, &log.OrgID, &log.VaultID, &log.SecretID, &log.Action, &log.IPAddress, &log.UserAgent, &log.Metadata, &log.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+func scanAuditLogs(rows pgx.Rows) ([]*audit.AuditLog, error) {
 	var logs []*audit.AuditLog
 	for rows.Next() {
-		var log audit.AuditLog
-		if err := rows.Scan(&log.ID, &log.UserID, &log.VaultID, &log.SecretID, &log.Action, &log.IPAddress, &log.UserAgent, &log.CreatedAt); err != nil {
+		log, err := scanAuditLog(rows)
+		if err != nil {
 			return nil, err
 		}
-		logs = append(logs, &log)
+		logs = append(logs, log)
 	}
 	return logs, rows.Err()
 }
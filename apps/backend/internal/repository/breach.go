@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Sameer16536/psvault/internal/lib/breach"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type BreachRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewBreachRepository(s *server.Server) *BreachRepository {
+	return &BreachRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *BreachRepository) WithTx(tx pgx.Tx) *BreachRepository {
+	return &BreachRepository{server: r.server, tx: tx}
+}
+
+func (r *BreachRepository) db() Querier {
+	return querier(r.server, r.tx)
+}
+
+// Range - List the corpus entries whose hash starts with prefix (upper-case hex)
+func (r *BreachRepository) Range(ctx context.Context, prefix string) ([]breach.Entry, error) {
+	query := `SELECT suffix, count FROM breached_passwords WHERE prefix = $1 ORDER BY suffix`
+	rows, err := r.db().Query(ctx, query, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []breach.Entry
+	for rows.Next() {
+		var suffix string
+		var e breach.Entry
+		if err := rows.Scan(&suffix, &e.Count); err != nil {
+			return nil, err
+		}
+		e.Hash = prefix + suffix
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Import - Insert a batch of corpus entries, updating the count of hashes already present
+func (r *BreachRepository) Import(ctx context.Context, entries []breach.Entry) error {
+	tx, err := r.db().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	// COPY cannot upsert, so stage the batch and merge it
+	stageQuery := `CREATE TEMP TABLE breached_passwords_import (LIKE breached_passwords) ON COMMIT DROP`
+	if _, err := tx.Exec(ctx, stageQuery); err != nil {
+		return err
+	}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"breached_passwords_import"}, []string{"prefix", "suffix", "count"},
+		pgx.CopyFromSlice(len(entries), func(i int) ([]any, error) {
+			return []any{entries[i].Prefix(), entries[i].Suffix(), entries[i].Count}, nil
+		}))
+	if err != nil {
+		return err
+	}
+	mergeQuery := `
+		INSERT INTO breached_passwords (prefix, suffix, count)
+		SELECT prefix, suffix, count FROM breached_passwords_import
+		ON CONFLICT (prefix, suffix) DO UPDATE SET count = EXCLUDED.count
+	`
+	if _, err := tx.Exec(ctx, mergeQuery); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Truncate - Remove the whole corpus
+func (r *BreachRepository) Truncate(ctx context.Context) error {
+	_, err := r.db().Exec(ctx, `TRUNCATE breached_passwords`)
+	return err
+}
+
+// Count - Count the hashes in the corpus
+func (r *BreachRepository) Count(ctx context.Context) (int64, error) {
+	var n int64
+	err := r.db().QueryRow(ctx, `SELECT COUNT(*) FROM breached_passwords`).Scan(&n)
+	return n, err
+}
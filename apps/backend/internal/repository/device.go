@@ -6,16 +6,30 @@ import (
 
 	"github.com/Sameer16536/psvault/internal/model/device"
 	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
 )
 
 type DeviceRepository struct {
 	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
 }
 
 func NewDeviceRepository(s *server.Server) *DeviceRepository {
 	return &DeviceRepository{server: s}
 }
 
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *DeviceRepository) WithTx(tx pgx.Tx) *DeviceRepository {
+	return &DeviceRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *DeviceRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
 // Register - Register a new device or update if exists
 func (r *DeviceRepository) Register(ctx context.Context, d *device.Device) error {
 	query := `
@@ -25,7 +39,7 @@ func (r *DeviceRepository) Register(ctx context.Context, d *device.Device) error
 		DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at
 		RETURNING id, created_at, updated_at
 	`
-	return r.server.DB.Pool.QueryRow(ctx, query, d.UserID, d.DeviceFingerprint, d.LastSeenAt).
+	return r.db(ctx).QueryRow(ctx, query, d.UserID, d.DeviceFingerprint, d.LastSeenAt).
 		Scan(&d.ID, &d.CreatedAt, &d.UpdatedAt)
 }
 
@@ -37,7 +51,7 @@ func (r *DeviceRepository) ListByUserID(ctx context.Context, userID string) ([]*
 		WHERE user_id = $1
 		ORDER BY last_seen_at DESC
 	`
-	rows, err := r.server.DB.Pool.Query(ctx, query, userID)
+	rows, err := r.db(ctx).Query(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -56,13 +70,13 @@ func (r *DeviceRepository) ListByUserID(ctx context.Context, userID string) ([]*
 // UpdateLastSeen - Update device last seen timestamp
 func (r *DeviceRepository) UpdateLastSeen(ctx context.Context, id string) error {
 	query := `UPDATE devices SET last_seen_at = $1 WHERE id = $2`
-	_, err := r.server.DB.Pool.Exec(ctx, query, time.Now(), id)
+	_, err := r.db(ctx).Exec(ctx, query, time.Now(), id)
 	return err
 }
 
 // Delete - Delete a device
 func (r *DeviceRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM devices WHERE id = $1`
-	_, err := r.server.DB.Pool.Exec(ctx, query, id)
+	_, err := r.db(ctx).Exec(ctx, query, id)
 	return err
 }
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/psvault/internal/model/folder"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type FolderRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewFolderRepository(s *server.Server) *FolderRepository {
+	return &FolderRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *FolderRepository) WithTx(tx pgx.Tx) *FolderRepository {
+	return &FolderRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *FolderRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
+// Create - Create a new folder
+func (r *FolderRepository) Create(ctx context.Context, f *folder.Folder) error {
+	query := `
+		INSERT INTO folders (vault_id, parent_id, name)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db(ctx).QueryRow(ctx, query, f.VaultID, f.ParentID, f.Name).
+		Scan(&f.ID, &f.CreatedAt, &f.UpdatedAt)
+}
+
+// GetByID - Get folder by ID
+func (r *FolderRepository) GetByID(ctx context.Context, id string) (*folder.Folder, error) {
+	query := `
+		SELECT id, vault_id, parent_id, name, is_trash, created_at, updated_at
+		FROM folders
+		WHERE id = $1
+	`
+	f, err := scanFolder(r.db(ctx).QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// ListTree - List every folder of a vault below parentID (or the whole vault when nil),
+// parents before children
+func (r *FolderRepository) ListTree(ctx context.Context, vaultID string, parentID *string) ([]*folder.Folder, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT id, vault_id, parent_id, name, is_trash, created_at, updated_at, 1 AS depth
+			FROM folders
+			WHERE vault_id = $1 AND parent_id IS NOT DISTINCT FROM $2::uuid
+			UNION ALL
+			SELECT f.id, f.vault_id, f.parent_id, f.name, f.is_trash, f.created_at, f.updated_at, t.depth + 1
+			FROM folders f
+			INNER JOIN tree t ON f.parent_id = t.id
+		)
+		SELECT id, vault_id, parent_id, name, is_trash, created_at, updated_at
+		FROM tree
+		ORDER BY depth ASC, is_trash ASC, lower(name) ASC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, vaultID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var folders []*folder.Folder
+	for rows.Next() {
+		f, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, f)
+	}
+	return folders, rows.Err()
+}
+
+// ListSecretSummaries - List the secrets of a vault with their folder and title
+func (r *FolderRepository) ListSecretSummaries(ctx context.Context, vaultID string) ([]*folder.SecretSummary, error) {
+	query := `
+		SELECT s.id, s.folder_id, s.type, COALESCE(m.title, ''), s.updated_at
+		FROM secrets s
+		LEFT JOIN secret_metadata m ON s.id = m.secret_id
+		WHERE s.vault_id = $1
+		ORDER BY lower(m.title) ASC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var summaries []*folder.SecretSummary
+	for rows.Next() {
+		var s folder.SecretSummary
+		if err := rows.Scan(&s.ID, &s.FolderID, &s.Type, &s.Title, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, &s)
+	}
+	return summaries, rows.Err()
+}
+
+// Rename - Rename a folder
+func (r *FolderRepository) Rename(ctx context.Context, f *folder.Folder) error {
+	query := `UPDATE folders SET name = $1 WHERE id = $2 RETURNING updated_at`
+	return r.db(ctx).QueryRow(ctx, query, f.Name, f.ID).Scan(&f.UpdatedAt)
+}
+
+// Move - Move a folder under parentID, or to the top level when nil.
+// Returns false without moving if parentID is the folder itself or one of its descendants.
+func (r *FolderRepository) Move(ctx context.Context, f *folder.Folder, parentID *string) (bool, error) {
+	tx, err := r.db(ctx).Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+	// Serialize moves within a vault so two concurrent moves cannot form a cycle
+	if err := lockVaultFolders(ctx, tx, f.VaultID); err != nil {
+		return false, err
+	}
+	if parentID != nil {
+		cycleQuery := `
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id FROM folders WHERE id = $1
+				UNION ALL
+				SELECT p.id, p.parent_id
+				FROM folders p
+				INNER JOIN ancestors a ON p.id = a.parent_id
+			)
+			SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)
+		`
+		var cycle bool
+		if err := tx.QueryRow(ctx, cycleQuery, *parentID, f.ID).Scan(&cycle); err != nil {
+			return false, err
+		}
+		if cycle {
+			return false, nil
+		}
+	}
+	query := `UPDATE folders SET parent_id = $1 WHERE id = $2 RETURNING parent_id, updated_at`
+	if err := tx.QueryRow(ctx, query, parentID, f.ID).Scan(&f.ParentID, &f.UpdatedAt); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// Delete - Delete a folder after moving its child folders and secrets into targetID
+// (the top level of the vault when nil)
+func (r *FolderRepository) Delete(ctx context.Context, f *folder.Folder, targetID *string) error {
+	tx, err := r.db(ctx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := lockVaultFolders(ctx, tx, f.VaultID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE folders SET parent_id = $1 WHERE parent_id = $2`, targetID, f.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `UPDATE secrets SET folder_id = $1 WHERE folder_id = $2`, targetID, f.ID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM folders WHERE id = $1`, f.ID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetOrCreateTrash - Get the trash folder of a vault, creating it on first use
+func (r *FolderRepository) GetOrCreateTrash(ctx context.Context, vaultID string) (*folder.Folder, error) {
+	insertQuery := `
+		INSERT INTO folders (vault_id, name, is_trash)
+		VALUES ($1, $2, TRUE)
+		ON CONFLICT (vault_id) WHERE is_trash DO NOTHING
+	`
+	if _, err := r.db(ctx).Exec(ctx, insertQuery, vaultID, folder.TrashName); err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT id, vault_id, parent_id, name, is_trash, created_at, updated_at
+		FROM folders
+		WHERE vault_id = $1 AND is_trash
+	`
+	return scanFolder(r.db(ctx).QueryRow(ctx, query, vaultID))
+}
+
+func lockVaultFolders(ctx context.Context, tx pgx.Tx, vaultID string) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('folders:' || $1))`, vaultID)
+	return err
+}
+
+func scanFolder(row pgx.Row) (*folder.Folder, error) {
+	var f folder.Folder
+	if err := row.Scan(&f.ID, &f.VaultID, &f.ParentID, &f.Name, &f.IsTrash, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model/outbox"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type OutboxRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewOutboxRepository(s *server.Server) *OutboxRepository {
+	return &OutboxRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *OutboxRepository) WithTx(tx pgx.Tx) *OutboxRepository {
+	return &OutboxRepository{server: r.server, tx: tx}
+}
+
+func (r *OutboxRepository) db() Querier {
+	return querier(r.server, r.tx)
+}
+
+// Enqueue - Record a message for the relay; a message whose idempotency key
+// was already recorded is dropped. Requests may write but not read the
+// outbox, so the ID is assigned here rather than returned.
+func (r *OutboxRepository) Enqueue(ctx context.Context, m *outbox.Message) error {
+	m.ID = uuid.New()
+	query := `
+		INSERT INTO outbox (id, kind, topic, payload, idempotency_key)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db().Exec(ctx, query, m.ID, m.Kind, m.Topic, m.Payload, m.IdempotencyKey)
+	return err
+}
+
+// ClaimPending - Claim up to limit messages due for publishing, oldest first.
+// Claimed messages count an attempt and are hidden from other relays until
+// leaseUntil, after which they are claimed again unless marked published.
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit int, leaseUntil time.Time) ([]*outbox.Message, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE outbox
+			SET attempts = attempts + 1, available_at = $2
+			WHERE id IN (
+				SELECT id FROM outbox
+				WHERE published_at IS NULL AND failed_at IS NULL AND available_at <= NOW()
+				ORDER BY available_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, created_at, kind, topic, payload, idempotency_key, attempts, last_error, available_at
+		)
+		SELECT * FROM claimed ORDER BY created_at
+	`
+	rows, err := r.db().Query(ctx, query, limit, leaseUntil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var messages []*outbox.Message
+	for rows.Next() {
+		var m outbox.Message
+		if err := rows.Scan(
+			&m.ID, &m.CreatedAt, &m.Kind, &m.Topic, &m.Payload, &m.IdempotencyKey, &m.Attempts, &m.LastError, &m.AvailableAt,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &m)
+	}
+	return messages, rows.Err()
+}
+
+// MarkPublished - Record that a message was published
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db().Exec(ctx, `UPDATE outbox SET published_at = NOW(), last_error = NULL WHERE id = $1`, id)
+	return err
+}
+
+// MarkRetry - Record a failed attempt and when to try again
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id uuid.UUID, lastError string, retryAt time.Time) error {
+	_, err := r.db().Exec(ctx, `UPDATE outbox SET last_error = $2, available_at = $3 WHERE id = $1`, id, lastError, retryAt)
+	return err
+}
+
+// MarkFailed - Record a failed final attempt; the message is kept for inspection but not retried
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	_, err := r.db().Exec(ctx, `UPDATE outbox SET last_error = $2, failed_at = NOW() WHERE id = $1`, id, lastError)
+	return err
+}
+
+// PurgePublished - Delete messages published before the given time
+func (r *OutboxRepository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db().Exec(ctx, `DELETE FROM outbox WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
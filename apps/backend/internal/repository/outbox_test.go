@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/model/outbox"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: Messages are written once per key, claimed once per lease and retried until published
+func TestOutboxRepository_Relay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	repos := repository.NewRepositories(srv)
+	ctx := repository.Unscoped(context.Background())
+
+	key := "digest:user_alice:2026-06-01"
+	for range 2 {
+		m, err := outbox.NewTask("email:expiry_digest", map[string]string{"user_id": "user_alice"})
+		require.NoError(t, err)
+		m.IdempotencyKey = &key
+		require.NoError(t, repos.Outbox.Enqueue(ctx, m))
+	}
+	event, err := outbox.NewEvent(outbox.TopicAuditLogged, map[string]string{"action": "create"})
+	require.NoError(t, err)
+	require.NoError(t, repos.Outbox.Enqueue(ctx, event))
+
+	claimed, err := repos.Outbox.ClaimPending(ctx, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, key, claimed[0].DeliveryID())
+	assert.Equal(t, 1, claimed[0].Attempts)
+	assert.JSONEq(t, `{"user_id":"user_alice"}`, string(claimed[0].Payload))
+	assert.Equal(t, event.ID.String(), claimed[1].DeliveryID())
+
+	// Leased messages are not claimed again
+	again, err := repos.Outbox.ClaimPending(ctx, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	require.NoError(t, repos.Outbox.MarkPublished(ctx, claimed[0].ID))
+	require.NoError(t, repos.Outbox.MarkRetry(ctx, claimed[1].ID, "redis unavailable", time.Now().Add(-time.Second)))
+
+	retried, err := repos.Outbox.ClaimPending(ctx, 10, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, retried, 1)
+	assert.Equal(t, 2, retried[0].Attempts)
+	require.NotNil(t, retried[0].LastError)
+	assert.Equal(t, "redis unavailable", *retried[0].LastError)
+
+	require.NoError(t, repos.Outbox.MarkFailed(ctx, retried[0].ID, "redis unavailable"))
+	purged, err := repos.Outbox.PurgePublished(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type PasswordHealthRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewPasswordHealthRepository(s *server.Server) *PasswordHealthRepository {
+	return &PasswordHealthRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *PasswordHealthRepository) WithTx(tx pgx.Tx) *PasswordHealthRepository {
+	return &PasswordHealthRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *PasswordHealthRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
+// Upsert - Set the health signals of a secret, replacing any previous ones
+func (r *PasswordHealthRepository) Upsert(ctx context.Context, h *secret.PasswordHealth) error {
+	query := `
+		INSERT INTO secret_health (secret_id, strength_score, reuse_fingerprint, last_rotated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (secret_id) DO UPDATE
+		SET strength_score = EXCLUDED.strength_score,
+			reuse_fingerprint = EXCLUDED.reuse_fingerprint,
+			last_rotated_at = EXCLUDED.last_rotated_at
+		RETURNING updated_at
+	`
+	return r.db(ctx).QueryRow(ctx, query, h.SecretID, h.StrengthScore, h.ReuseFingerprint, h.LastRotatedAt).
+		Scan(&h.UpdatedAt)
+}
+
+// ListByVaultID - List every secret of a vault with its health signals, if any
+func (r *PasswordHealthRepository) ListByVaultID(ctx context.Context, vaultID string) ([]*secret.PasswordHealthRow, error) {
+	query := `
+		SELECT s.id, COALESCE(m.title, ''), s.type, s.folder_id,
+			h.strength_score, h.reuse_fingerprint,
+			COALESCE(h.last_rotated_at, s.rotated_at), s.last_accessed_at
+		FROM secrets s
+		LEFT JOIN secret_metadata m ON s.id = m.secret_id
+		LEFT JOIN secret_health h ON s.id = h.secret_id
+		WHERE s.vault_id = $1
+		ORDER BY lower(m.title) ASC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var results []*secret.PasswordHealthRow
+	for rows.Next() {
+		var h secret.PasswordHealthRow
+		if err := rows.Scan(
+			&h.SecretID, &h.Title, &h.Type, &h.FolderID,
+			&h.StrengthScore, &h.ReuseFingerprint,
+			&h.LastRotatedAt, &h.LastAccessedAt,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, &h)
+	}
+	return results, rows.Err()
+}
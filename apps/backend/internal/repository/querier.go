@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Querier is what repositories run statements on: the pool, or a pgx.Tx
+// inside a unit of work. Begin on a pgx.Tx starts a savepoint, so
+// multi-statement methods nest inside a caller's transaction.
+type Querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// batcher is a Querier that can also send batches, as *pgxpool.Pool and pgx.Tx can
+type batcher interface {
+	Querier
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// querier returns tx when a repository is bound to one, otherwise the pool
+func querier(s *server.Server, tx pgx.Tx) batcher {
+	if tx != nil {
+		return tx
+	}
+	return s.DB.Pool
+}
@@ -1,19 +1,92 @@
 package repository
 
-import "github.com/Sameer16536/psvault/internal/server"
+import (
+	"context"
+
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
 
 type Repositories struct {
-	Vault  *VaultRepository
-	Secret *SecretRepository
-	Device *DeviceRepository
-	Audit  *AuditRepository
+	User           *UserRepository
+	Vault          *VaultRepository
+	VaultMember    *VaultMemberRepository
+	Secret         *SecretRepository
+	Device         *DeviceRepository
+	Audit          *AuditRepository
+	ServiceAccount *ServiceAccountRepository
+	Share          *ShareRepository
+	Attachment     *AttachmentRepository
+	SecretType     *SecretTypeRepository
+	Folder         *FolderRepository
+	PasswordHealth *PasswordHealthRepository
+	Breach         *BreachRepository
+	Admin          *AdminRepository
+	Outbox         *OutboxRepository
+	Webhook        *WebhookRepository
+
+	server *server.Server
+	// tx is set on sets returned by ForTx
+	tx pgx.Tx
 }
 
 func NewRepositories(s *server.Server) *Repositories {
 	return &Repositories{
-		Vault:  NewVaultRepository(s),
-		Secret: NewSecretRepository(s),
-		Device: NewDeviceRepository(s),
-		Audit:  NewAuditRepository(s),
+		User:           NewUserRepository(s),
+		Vault:          NewVaultRepository(s),
+		VaultMember:    NewVaultMemberRepository(s),
+		Secret:         NewSecretRepository(s),
+		Device:         NewDeviceRepository(s),
+		Audit:          NewAuditRepository(s),
+		ServiceAccount: NewServiceAccountRepository(s),
+		Share:          NewShareRepository(s),
+		Attachment:     NewAttachmentRepository(s),
+		SecretType:     NewSecretTypeRepository(s),
+		Folder:         NewFolderRepository(s),
+		PasswordHealth: NewPasswordHealthRepository(s),
+		Breach:         NewBreachRepository(s),
+		Admin:          NewAdminRepository(s),
+		Outbox:         NewOutboxRepository(s),
+		Webhook:        NewWebhookRepository(s),
+		server:         s,
+	}
+}
+
+// ForTx - Copy of every repository running its queries in tx, which the caller commits or rolls back
+func (r *Repositories) ForTx(tx pgx.Tx) *Repositories {
+	return &Repositories{
+		User:           r.User.WithTx(tx),
+		Vault:          r.Vault.WithTx(tx),
+		VaultMember:    r.VaultMember.WithTx(tx),
+		Secret:         r.Secret.WithTx(tx),
+		Device:         r.Device.WithTx(tx),
+		Audit:          r.Audit.WithTx(tx),
+		ServiceAccount: r.ServiceAccount.WithTx(tx),
+		Share:          r.Share.WithTx(tx),
+		Attachment:     r.Attachment.WithTx(tx),
+		SecretType:     r.SecretType.WithTx(tx),
+		Folder:         r.Folder.WithTx(tx),
+		PasswordHealth: r.PasswordHealth.WithTx(tx),
+		Breach:         r.Breach.WithTx(tx),
+		Admin:          r.Admin.WithTx(tx),
+		Outbox:         r.Outbox.WithTx(tx),
+		Webhook:        r.Webhook.WithTx(tx),
+		server:         r.server,
+		tx:             tx,
+	}
+}
+
+// WithTx - Run fn as a unit of work: the repositories passed to fn share one
+// transaction, committed when fn returns nil and rolled back otherwise.
+// Inside another unit of work fn runs in a savepoint of it.
+func (r *Repositories) WithTx(ctx context.Context, fn func(repos *Repositories) error) error {
+	tx, err := querier(r.server, r.tx).Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := fn(r.ForTx(tx)); err != nil {
+		return err
 	}
+	return tx.Commit(ctx)
 }
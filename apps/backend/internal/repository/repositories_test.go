@@ -0,0 +1,104 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: A unit of work commits its writes together or not at all
+func TestRepositories_WithTx(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	repos := repository.NewRepositories(srv)
+	ctx := repository.Unscoped(context.Background())
+
+	userID, err := repos.User.Resolve(ctx, "user_alice")
+	require.NoError(t, err)
+
+	createWithAudit := func(repos *repository.Repositories, name string) (*vault.Vault, error) {
+		v := &vault.Vault{UserID: userID, Name: name}
+		if err := repos.Vault.Create(ctx, v); err != nil {
+			return nil, err
+		}
+		vaultID := v.ID.String()
+		return v, repos.Audit.Log(ctx, &audit.AuditLog{UserID: userID, ActorType: audit.ActorTypeUser, VaultID: &vaultID, Action: audit.ActionCreate})
+	}
+
+	t.Run("rolls back when fn fails", func(t *testing.T) {
+		var created *vault.Vault
+		errFailed := errors.New("failed")
+		err := repos.WithTx(ctx, func(repos *repository.Repositories) error {
+			v, err := createWithAudit(repos, "Rolled back")
+			if err != nil {
+				return err
+			}
+			created = v
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+
+		v, err := repos.Vault.GetByID(ctx, created.ID.String())
+		require.NoError(t, err)
+		assert.Nil(t, v)
+		logs, err := repos.Audit.ListByUserID(ctx, userID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, logs)
+	})
+
+	t.Run("commits when fn succeeds", func(t *testing.T) {
+		var created *vault.Vault
+		err := repos.WithTx(ctx, func(repos *repository.Repositories) error {
+			var err error
+			created, err = createWithAudit(repos, "Committed")
+			return err
+		})
+		require.NoError(t, err)
+
+		v, err := repos.Vault.GetByID(ctx, created.ID.String())
+		require.NoError(t, err)
+		assert.NotNil(t, v)
+		logs, err := repos.Audit.ListByUserID(ctx, userID, 10)
+		require.NoError(t, err)
+		assert.Len(t, logs, 1)
+	})
+
+	t.Run("runs in a caller's transaction", func(t *testing.T) {
+		var created *vault.Vault
+		err := tt.WithRollbackTransaction(ctx, testDB, func(tx pgx.Tx) error {
+			txRepos := repos.ForTx(tx)
+			// A nested unit of work commits into the outer transaction only
+			err := txRepos.WithTx(ctx, func(repos *repository.Repositories) error {
+				var err error
+				created, err = createWithAudit(repos, "Test fixture")
+				return err
+			})
+			require.NoError(t, err)
+			v, err := txRepos.Vault.GetByID(ctx, created.ID.String())
+			require.NoError(t, err)
+			assert.NotNil(t, v)
+			return nil
+		})
+		require.NoError(t, err)
+
+		v, err := repos.Vault.GetByID(ctx, created.ID.String())
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+}
@@ -0,0 +1,217 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/device"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantRows are the rows seeded for one user
+type tenantRows struct {
+	userID, vaultID, secretID, deviceID string
+}
+
+func seedTenant(t *testing.T, ctx context.Context, repos *repository.Repositories, clerkID string) tenantRows {
+	t.Helper()
+	userID, err := repos.User.Resolve(ctx, clerkID)
+	require.NoError(t, err)
+
+	v := &vault.Vault{UserID: userID, Name: "Personal"}
+	require.NoError(t, repos.Vault.Create(ctx, v))
+	vaultID := v.ID.String()
+
+	s := &secret.Secret{VaultID: vaultID, Type: secret.SecretTypePassword, EncryptedPayload: []byte("ciphertext"), EncryptionVersion: 1}
+	require.NoError(t, repos.Secret.Create(ctx, s, &secret.SecretMetadata{Title: "Email", Tags: []string{"mine"}}))
+
+	d := &device.Device{UserID: userID, DeviceFingerprint: "laptop", LastSeenAt: time.Now()}
+	require.NoError(t, repos.Device.Register(ctx, d))
+
+	require.NoError(t, repos.Audit.Log(ctx, &audit.AuditLog{UserID: userID, VaultID: &vaultID, Action: audit.ActionCreate}))
+
+	return tenantRows{userID: userID, vaultID: vaultID, secretID: s.ID.String(), deviceID: d.ID.String()}
+}
+
+// Test: Queries made for one user return nothing of another's, even by ID
+func TestRowLevelSecurity_CrossTenantReads(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	repos := repository.NewRepositories(srv)
+	ctx := repository.Unscoped(context.Background())
+
+	// Seeded unscoped, as jobs and admin commands run
+	alice := seedTenant(t, ctx, repos, "user_alice")
+	bob := seedTenant(t, ctx, repos, "user_bob")
+	aliceCtx := actor.WithActor(ctx, &actor.Actor{ID: alice.userID, Type: actor.TypeUser})
+
+	v, err := repos.Vault.GetByID(aliceCtx, alice.vaultID)
+	require.NoError(t, err)
+	require.NotNil(t, v)
+	s, err := repos.Secret.GetByID(aliceCtx, alice.secretID)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+
+	v, err = repos.Vault.GetByID(aliceCtx, bob.vaultID)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	vaults, err := repos.Vault.ListByUserID(aliceCtx, bob.userID)
+	require.NoError(t, err)
+	assert.Empty(t, vaults)
+
+	s, err = repos.Secret.GetByID(aliceCtx, bob.secretID)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	secrets, err := repos.Secret.ListByVaultID(aliceCtx, bob.vaultID)
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+
+	devices, err := repos.Device.ListByUserID(aliceCtx, bob.userID)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+
+	logs, err := repos.Audit.ListByUserID(aliceCtx, bob.userID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+
+	summaries, err := repos.Folder.ListSecretSummaries(aliceCtx, bob.vaultID)
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+	summaries, err = repos.Folder.ListSecretSummaries(aliceCtx, alice.vaultID)
+	require.NoError(t, err)
+	assert.Len(t, summaries, 1)
+
+	health, err := repos.PasswordHealth.ListByVaultID(aliceCtx, bob.vaultID)
+	require.NoError(t, err)
+	assert.Empty(t, health)
+	health, err = repos.PasswordHealth.ListByVaultID(aliceCtx, alice.vaultID)
+	require.NoError(t, err)
+	assert.Len(t, health, 1)
+
+	// Writes to hidden rows match nothing
+	require.NoError(t, repos.Device.Delete(aliceCtx, bob.deviceID))
+	require.NoError(t, repos.Secret.SetTags(aliceCtx, bob.secretID, []string{"stolen"}))
+	devices, err = repos.Device.ListByUserID(ctx, bob.userID)
+	require.NoError(t, err)
+	assert.Len(t, devices, 1)
+	s, err = repos.Secret.GetByID(ctx, bob.secretID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mine"}, s.Metadata.Tags)
+
+	// Sharing the vault makes its secrets visible
+	require.NoError(t, repos.VaultMember.Upsert(ctx, &vault.Member{
+		VaultID:    bob.vaultID,
+		MemberType: audit.ActorTypeUser,
+		MemberID:   alice.userID,
+		Role:       vault.MemberRoleRead,
+		GrantedBy:  bob.userID,
+	}))
+	s, err = repos.Secret.GetByID(aliceCtx, bob.secretID)
+	require.NoError(t, err)
+	assert.NotNil(t, s)
+}
+
+// Test: Without an actor, only contexts marked Unscoped reach tenant tables
+func TestRowLevelSecurity_NoActor(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	repos := repository.NewRepositories(srv)
+	alice := seedTenant(t, repository.Unscoped(context.Background()), repos, "user_alice")
+	ctx := context.Background()
+
+	_, err := repos.Secret.GetByID(ctx, alice.secretID)
+	assert.ErrorIs(t, err, repository.ErrNoActor)
+	_, err = repos.Folder.ListSecretSummaries(ctx, alice.vaultID)
+	assert.ErrorIs(t, err, repository.ErrNoActor)
+	_, err = repos.PasswordHealth.ListByVaultID(ctx, alice.vaultID)
+	assert.ErrorIs(t, err, repository.ErrNoActor)
+	assert.ErrorIs(t, repos.Device.Delete(ctx, alice.deviceID), repository.ErrNoActor)
+	err = repos.WithTx(ctx, func(repos *repository.Repositories) error {
+		return repos.Secret.SetTags(ctx, alice.secretID, nil)
+	})
+	assert.ErrorIs(t, err, repository.ErrNoActor)
+
+	s, err := repos.Secret.GetByID(repository.Unscoped(ctx), alice.secretID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mine"}, s.Metadata.Tags)
+}
+
+// Test: Memberships are matched on the principal's type as well as its ID, so a
+// grant to a service account reaches no user that happens to share its ID
+func TestRowLevelSecurity_MemberType(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	repos := repository.NewRepositories(srv)
+	ctx := repository.Unscoped(context.Background())
+
+	alice := seedTenant(t, ctx, repos, "user_alice")
+	bob := seedTenant(t, ctx, repos, "user_bob")
+	// A service account with alice's ID, granted bob's vault
+	_, err := testDB.Pool.Exec(ctx, `
+		INSERT INTO service_accounts (id, org_id, name, created_by, client_secret_hash)
+		VALUES ($1, 'org_1', 'deploy-bot', $2, '\x00')
+	`, alice.userID, bob.userID)
+	require.NoError(t, err)
+	require.NoError(t, repos.VaultMember.Upsert(ctx, &vault.Member{
+		VaultID: bob.vaultID, MemberType: audit.ActorTypeServiceAccount, MemberID: alice.userID, Role: vault.MemberRoleRead, GrantedBy: bob.userID,
+	}))
+	_, err = testDB.Pool.Exec(ctx, `UPDATE secrets SET expires_at = NOW() WHERE id = $1`, bob.secretID)
+	require.NoError(t, err)
+
+	userCtx := actor.WithActor(ctx, &actor.Actor{ID: alice.userID, Type: actor.TypeUser})
+	botCtx := actor.WithActor(ctx, &actor.Actor{ID: alice.userID, Type: actor.TypeServiceAccount, OrgID: "org_1"})
+	until := time.Now().Add(time.Hour)
+
+	v, err := repos.Vault.GetByID(userCtx, bob.vaultID)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+	v, err = repos.Vault.GetByID(botCtx, bob.vaultID)
+	require.NoError(t, err)
+	assert.NotNil(t, v)
+
+	// The membership clause of the queries agrees, even without row-level security
+	found, err := repos.Secret.Search(ctx, audit.ActorTypeUser, alice.userID, "", map[string]interface{}{"vault_id": bob.vaultID})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+	found, err = repos.Secret.Search(ctx, audit.ActorTypeServiceAccount, alice.userID, "", map[string]interface{}{"vault_id": bob.vaultID})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, bob.secretID, found[0].Secret.ID.String())
+
+	due, err := repos.Secret.ListExpiring(ctx, audit.ActorTypeUser, alice.userID, "", &bob.vaultID, until)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = repos.Secret.ListExpiring(botCtx, audit.ActorTypeServiceAccount, alice.userID, "", &bob.vaultID, until)
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+}
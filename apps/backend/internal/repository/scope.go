@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TenantRole is the database role row-level security policies apply to
+const TenantRole = "psvault_tenant"
+
+// scopeQuery switches the current transaction to TenantRole acting for a
+// principal; the settings end with the transaction
+const scopeQuery = `SELECT set_config('role', $1, true), set_config('app.current_user', $2, true),
+	set_config('app.current_actor_type', $3, true), set_config('app.current_org', $4, true)`
+
+// tenantDB runs every statement in a transaction restricted by row-level
+// security to one principal. Single statements are sent in a batch after
+// scopeQuery, which Postgres runs as one implicit transaction.
+type tenantDB struct {
+	db        batcher
+	userID    string
+	actorType string
+	orgID     string
+}
+
+// ErrNoActor is returned by repositories restricted by row-level security when
+// ctx carries neither an actor nor the Unscoped mark, so a request path that
+// forgot to set its actor fails instead of reaching every tenant
+var ErrNoActor = errors.New("no actor in context for a tenant-scoped query")
+
+type unscopedKey struct{}
+
+// Unscoped marks ctx as belonging to a job or admin command, which acts for no
+// single principal; repositories then run without row-level security
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+func isUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}
+
+// scoped returns db restricted to the actor in ctx. Contexts marked Unscoped
+// get db unrestricted; any other context without an actor gets ErrNoActor.
+func scoped(ctx context.Context, db batcher) Querier {
+	a, ok := actor.FromContext(ctx)
+	if !ok || a.ID == "" {
+		if isUnscoped(ctx) {
+			return db
+		}
+		return noActorDB{}
+	}
+	// Service accounts reach organization vaults only through membership
+	orgID := ""
+	if a.Type == actor.TypeUser {
+		orgID = a.OrgID
+	}
+	return &tenantDB{db: db, userID: a.ID, actorType: string(a.Type), orgID: orgID}
+}
+
+func (t *tenantDB) batch(sql string, args []any) *pgx.Batch {
+	b := &pgx.Batch{}
+	b.Queue(scopeQuery, TenantRole, t.userID, t.actorType, t.orgID)
+	b.Queue(sql, args...)
+	return b
+}
+
+// Begin starts a transaction, or a savepoint inside one, scoped for its whole length
+func (t *tenantDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, scopeQuery, TenantRole, t.userID, t.actorType, t.orgID); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (t *tenantDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	results := t.db.SendBatch(ctx, t.batch(sql, args))
+	defer results.Close()
+	if _, err := results.Exec(); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	tag, err := results.Exec()
+	if err != nil {
+		return tag, err
+	}
+	return tag, results.Close()
+}
+
+func (t *tenantDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	results := t.db.SendBatch(ctx, t.batch(sql, args))
+	if _, err := results.Exec(); err != nil {
+		results.Close()
+		return nil, err
+	}
+	rows, err := results.Query()
+	if err != nil {
+		results.Close()
+		return nil, err
+	}
+	return &tenantRows{Rows: rows, results: results}, nil
+}
+
+func (t *tenantDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, err := t.Query(ctx, sql, args...)
+	return &tenantRow{rows: rows, err: err}
+}
+
+// tenantRows releases the batch, and with it the connection, when closed
+type tenantRows struct {
+	pgx.Rows
+	results pgx.BatchResults
+	err     error
+}
+
+func (r *tenantRows) Close() {
+	r.Rows.Close()
+	if err := r.results.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *tenantRows) Err() error {
+	if err := r.Rows.Err(); err != nil {
+		return err
+	}
+	return r.err
+}
+
+// tenantRow scans the first row like pgx.Row, returning pgx.ErrNoRows when there is none
+type tenantRow struct {
+	rows pgx.Rows
+	err  error
+}
+
+func (r *tenantRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	defer r.rows.Close()
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	r.rows.Close()
+	return r.rows.Err()
+}
+
+// noActorDB fails every statement with ErrNoActor
+type noActorDB struct{}
+
+func (noActorDB) Begin(context.Context) (pgx.Tx, error) {
+	return nil, ErrNoActor
+}
+
+func (noActorDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, ErrNoActor
+}
+
+func (noActorDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, ErrNoActor
+}
+
+func (noActorDB) QueryRow(context.Context, string, ...any) pgx.Row {
+	return &tenantRow{err: ErrNoActor}
+}
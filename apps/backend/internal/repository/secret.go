@@ -5,19 +5,42 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Sameer16536/psvault/internal/lib/searchquery"
+	"github.com/Sameer16536/psvault/internal/model/audit"
 	"github.com/Sameer16536/psvault/internal/model/secret"
 	"github.com/Sameer16536/psvault/internal/server"
 	"github.com/jackc/pgx/v5"
 )
 
+// rotationDueExpression is when a secret is next due for rotation (NULL without a policy)
+const rotationDueExpression = `s.rotated_at + make_interval(days => s.rotate_every_days)`
+
+// secretDueCondition matches secrets expiring or due for rotation by the time in param
+func secretDueCondition(param string) string {
+	return "(s.expires_at <= " + param + " OR " + rotationDueExpression + " <= " + param + ")"
+}
+
 type SecretRepository struct {
 	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
 }
 
 func NewSecretRepository(s *server.Server) *SecretRepository {
 	return &SecretRepository{server: s}
 }
 
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *SecretRepository) WithTx(tx pgx.Tx) *SecretRepository {
+	return &SecretRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *SecretRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
 // SecretWithMetadata - Combined secret and metadata
 type SecretWithMetadata struct {
 	Secret   *secret.Secret
@@ -26,30 +49,30 @@ type SecretWithMetadata struct {
 
 // Create - Create a new secret with metadata (transaction)
 func (r *SecretRepository) Create(ctx context.Context, s *secret.Secret, m *secret.SecretMetadata) error {
-	tx, err := r.server.DB.Pool.Begin(ctx)
+	tx, err := r.db(ctx).Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
 	// Insert secret
 	secretQuery := `
-		INSERT INTO secrets (vault_id, type, encrypted_payload, encryption_version)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, created_at, updated_at
+		INSERT INTO secrets (vault_id, folder_id, type, encrypted_payload, encryption_version, expires_at, rotate_every_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at, rotated_at
 	`
-	err = tx.QueryRow(ctx, secretQuery, s.VaultID, s.Type, s.EncryptedPayload, s.EncryptionVersion).
-		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+	err = tx.QueryRow(ctx, secretQuery, s.VaultID, s.FolderID, s.Type, s.EncryptedPayload, s.EncryptionVersion, s.ExpiresAt, s.RotateEveryDays).
+		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt, &s.RotatedAt)
 	if err != nil {
 		return err
 	}
 	// Insert metadata
 	metadataQuery := `
-		INSERT INTO secret_metadata (secret_id, title, domain, tags)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO secret_metadata (secret_id, title, domain, tags, attributes, custom_fields)
+		VALUES ($1, $2, $3, $4, COALESCE($5, '{}'::jsonb), COALESCE($6, '{}'::jsonb))
 		RETURNING id, created_at, updated_at
 	`
 	m.SecretID = s.ID.String()
-	err = tx.QueryRow(ctx, metadataQuery, m.SecretID, m.Title, m.Domain, m.Tags).
+	err = tx.QueryRow(ctx, metadataQuery, m.SecretID, m.Title, m.Domain, m.Tags, m.Attributes, m.CustomFields).
 		Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
 	if err != nil {
 		return err
@@ -61,19 +84,19 @@ func (r *SecretRepository) Create(ctx context.Context, s *secret.Secret, m *secr
 func (r *SecretRepository) GetByID(ctx context.Context, id string) (*SecretWithMetadata, error) {
 	query := `
 		SELECT 
-			s.id, s.vault_id, s.type, s.encrypted_payload, s.encryption_version, 
-			s.last_accessed_at, s.created_at, s.updated_at,
-			m.id, m.title, m.domain, m.tags, m.created_at, m.updated_at
+			s.id, s.vault_id, s.folder_id, s.type, s.encrypted_payload, s.encryption_version, 
+			s.last_accessed_at, s.created_at, s.updated_at, s.expires_at, s.rotate_every_days, s.rotated_at,
+			m.id, m.title, m.domain, m.tags, m.attributes, m.custom_fields, m.created_at, m.updated_at
 		FROM secrets s
 		LEFT JOIN secret_metadata m ON s.id = m.secret_id
 		WHERE s.id = $1
 	`
 	var s secret.Secret
 	var m secret.SecretMetadata
-	err := r.server.DB.Pool.QueryRow(ctx, query, id).Scan(
-		&s.ID, &s.VaultID, &s.Type, &s.EncryptedPayload, &s.EncryptionVersion,
-		&s.LastAccessedAt, &s.CreatedAt, &s.UpdatedAt,
-		&m.ID, &m.Title, &m.Domain, &m.Tags, &m.CreatedAt, &m.UpdatedAt,
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(
+		&s.ID, &s.VaultID, &s.FolderID, &s.Type, &s.EncryptedPayload, &s.EncryptionVersion,
+		&s.LastAccessedAt, &s.CreatedAt, &s.UpdatedAt, &s.ExpiresAt, &s.RotateEveryDays, &s.RotatedAt,
+		&m.ID, &m.Title, &m.Domain, &m.Tags, &m.Attributes, &m.CustomFields, &m.CreatedAt, &m.UpdatedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -89,9 +112,9 @@ func (r *SecretRepository) GetByID(ctx context.Context, id string) (*SecretWithM
 func (r *SecretRepository) ListByVaultID(ctx context.Context, vaultID string) ([]*SecretWithMetadata, error) {
 	query := `
 		SELECT 
-			s.id, s.vault_id, s.type, s.encrypted_payload, s.encryption_version, 
-			s.last_accessed_at, s.created_at, s.updated_at,
-			m.id, m.title, m.domain, m.tags, m.created_at, m.updated_at
+			s.id, s.vault_id, s.folder_id, s.type, s.encrypted_payload, s.encryption_version, 
+			s.last_accessed_at, s.created_at, s.updated_at, s.expires_at, s.rotate_every_days, s.rotated_at,
+			m.id, m.title, m.domain, m.tags, m.attributes, m.custom_fields, m.created_at, m.updated_at
 		FROM secrets s
 		LEFT JOIN secret_metadata m ON s.id = m.secret_id
 		WHERE s.vault_id = $1
@@ -100,20 +123,25 @@ func (r *SecretRepository) ListByVaultID(ctx context.Context, vaultID string) ([
 	return r.querySecrets(ctx, query, vaultID)
 }
 
-// Search - Search secrets with filters
-func (r *SecretRepository) Search(ctx context.Context, userID string, filters map[string]interface{}) ([]*SecretWithMetadata, error) {
+// Search - Search secrets with filters. A parsed searchquery.Node under "query" adds
+// full-text and qualified terms, and ranks results by relevance.
+// Visible vaults are the user's personal vaults, vaults of orgID (if set) and vaults shared with the
+// principal (memberType, userID) through membership.
+func (r *SecretRepository) Search(ctx context.Context, memberType audit.ActorType, userID, orgID string, filters map[string]interface{}) ([]*SecretWithMetadata, error) {
 	query := `
 		SELECT 
-			s.id, s.vault_id, s.type, s.encrypted_payload, s.encryption_version, 
-			s.last_accessed_at, s.created_at, s.updated_at,
-			m.id, m.title, m.domain, m.tags, m.created_at, m.updated_at
+			s.id, s.vault_id, s.folder_id, s.type, s.encrypted_payload, s.encryption_version, 
+			s.last_accessed_at, s.created_at, s.updated_at, s.expires_at, s.rotate_every_days, s.rotated_at,
+			m.id, m.title, m.domain, m.tags, m.attributes, m.custom_fields, m.created_at, m.updated_at
 		FROM secrets s
 		LEFT JOIN secret_metadata m ON s.id = m.secret_id
 		INNER JOIN vaults v ON s.vault_id = v.id
-		WHERE v.user_id = $1
+		WHERE ((v.org_id IS NULL AND v.user_id = $1) OR (v.org_id = NULLIF($2, '')) OR EXISTS (
+			SELECT 1 FROM vault_members vm WHERE vm.vault_id = v.id AND vm.member_type = $3 AND vm.member_id = $1
+		))
 	`
-	args := []interface{}{userID}
-	argCount := 1
+	args := []interface{}{userID, orgID, memberType}
+	argCount := 3
 	if vaultID, ok := filters["vault_id"].(string); ok && vaultID != "" {
 		argCount++
 		query += fmt.Sprintf(" AND s.vault_id = $%d", argCount)
@@ -139,20 +167,114 @@ func (r *SecretRepository) Search(ctx context.Context, userID string, filters ma
 		query += fmt.Sprintf(" AND m.tags && $%d", argCount)
 		args = append(args, tags)
 	}
-	query += " ORDER BY s.created_at DESC"
+	orderBy := "s.created_at DESC"
+	if node, ok := filters["query"].(searchquery.Node); ok && node != nil {
+		compiler := &searchCompiler{args: args}
+		query += " AND " + compiler.compile(node, false)
+		if rank := compiler.rankExpression(); rank != "" {
+			orderBy = rank + " DESC, " + orderBy
+		}
+		args = compiler.args
+	}
+	query += " ORDER BY " + orderBy
 	return r.querySecrets(ctx, query, args...)
 }
 
+// ListExpiring - List secrets visible to the user that expire or are due for rotation by until,
+// including those already expired or overdue, soonest first
+func (r *SecretRepository) ListExpiring(ctx context.Context, memberType audit.ActorType, userID, orgID string, vaultID *string, until time.Time) ([]*SecretWithMetadata, error) {
+	query := `
+		SELECT 
+			s.id, s.vault_id, s.folder_id, s.type, s.encrypted_payload, s.encryption_version, 
+			s.last_accessed_at, s.created_at, s.updated_at, s.expires_at, s.rotate_every_days, s.rotated_at,
+			m.id, m.title, m.domain, m.tags, m.attributes, m.custom_fields, m.created_at, m.updated_at
+		FROM secrets s
+		LEFT JOIN secret_metadata m ON s.id = m.secret_id
+		INNER JOIN vaults v ON s.vault_id = v.id
+		WHERE ((v.org_id IS NULL AND v.user_id = $1) OR (v.org_id = NULLIF($2, '')) OR EXISTS (
+			SELECT 1 FROM vault_members vm WHERE vm.vault_id = v.id AND vm.member_type = $5 AND vm.member_id = $1
+		))
+			AND ($3::uuid IS NULL OR s.vault_id = $3)
+			AND ` + secretDueCondition("$4") + `
+		ORDER BY LEAST(s.expires_at, ` + rotationDueExpression + `) ASC
+	`
+	return r.querySecrets(ctx, query, userID, orgID, vaultID, until, memberType)
+}
+
+// ListDigest - List every secret that expires or is due for rotation by until, with the
+// Clerk user ID of its vault's owner and the vault name, ordered by owner
+func (r *SecretRepository) ListDigest(ctx context.Context, until time.Time) ([]*secret.ExpiryDigestRow, error) {
+	query := `
+		SELECT u.external_auth_id, v.name, COALESCE(m.title, ''),
+			s.id, s.vault_id, s.type, s.expires_at, s.rotate_every_days, s.rotated_at
+		FROM secrets s
+		LEFT JOIN secret_metadata m ON s.id = m.secret_id
+		INNER JOIN vaults v ON s.vault_id = v.id
+		INNER JOIN users u ON v.user_id = u.id
+		WHERE ` + secretDueCondition("$1") + `
+		ORDER BY u.external_auth_id, LEAST(s.expires_at, ` + rotationDueExpression + `) ASC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var results []*secret.ExpiryDigestRow
+	for rows.Next() {
+		var row secret.ExpiryDigestRow
+		var s secret.Secret
+		if err := rows.Scan(
+			&row.OwnerID, &row.VaultName, &row.Title,
+			&s.ID, &s.VaultID, &s.Type, &s.ExpiresAt, &s.RotateEveryDays, &s.RotatedAt,
+		); err != nil {
+			return nil, err
+		}
+		row.Secret = &s
+		results = append(results, &row)
+	}
+	return results, rows.Err()
+}
+
+// SetFolder - Move a secret into a folder, or to the top level of its vault when folderID is nil
+func (r *SecretRepository) SetFolder(ctx context.Context, id string, folderID *string) error {
+	query := `UPDATE secrets SET folder_id = $1 WHERE id = $2`
+	_, err := r.db(ctx).Exec(ctx, query, folderID, id)
+	return err
+}
+
+// MoveToVault - Move a secret to another vault with its payload re-encrypted for that vault.
+// The reuse fingerprint is keyed to the old vault, so it is cleared.
+func (r *SecretRepository) MoveToVault(ctx context.Context, s *secret.Secret) error {
+	query := `
+		WITH cleared AS (
+			UPDATE secret_health SET reuse_fingerprint = NULL WHERE secret_id = $5
+		)
+		UPDATE secrets
+		SET vault_id = $1, folder_id = $2, encrypted_payload = $3, encryption_version = $4
+		WHERE id = $5
+		RETURNING updated_at
+	`
+	return r.db(ctx).QueryRow(ctx, query, s.VaultID, s.FolderID, s.EncryptedPayload, s.EncryptionVersion, s.ID).
+		Scan(&s.UpdatedAt)
+}
+
+// SetTags - Replace the tags of a secret
+func (r *SecretRepository) SetTags(ctx context.Context, id string, tags []string) error {
+	query := `UPDATE secret_metadata SET tags = $1 WHERE secret_id = $2`
+	_, err := r.db(ctx).Exec(ctx, query, tags, id)
+	return err
+}
+
 // UpdateLastAccessed - Update last accessed timestamp
 func (r *SecretRepository) UpdateLastAccessed(ctx context.Context, id string) error {
 	query := `UPDATE secrets SET last_accessed_at = $1 WHERE id = $2`
-	_, err := r.server.DB.Pool.Exec(ctx, query, time.Now(), id)
+	_, err := r.db(ctx).Exec(ctx, query, time.Now(), id)
 	return err
 }
 
-// Update - Update secret and metadata
+// Update - Update secret and metadata. Storing a different payload counts as a rotation.
 func (r *SecretRepository) Update(ctx context.Context, s *secret.Secret, m *secret.SecretMetadata) error {
-	tx, err := r.server.DB.Pool.Begin(ctx)
+	tx, err := r.db(ctx).Begin(ctx)
 	if err != nil {
 		return err
 	}
@@ -160,23 +282,24 @@ func (r *SecretRepository) Update(ctx context.Context, s *secret.Secret, m *secr
 	// Update secret
 	secretQuery := `
 		UPDATE secrets
-		SET encrypted_payload = $1, encryption_version = $2
-		WHERE id = $3
-		RETURNING updated_at
+		SET encrypted_payload = $1, encryption_version = $2, expires_at = $3, rotate_every_days = $4,
+			rotated_at = CASE WHEN encrypted_payload IS DISTINCT FROM $1 THEN CURRENT_TIMESTAMP ELSE rotated_at END
+		WHERE id = $5
+		RETURNING updated_at, rotated_at
 	`
-	err = tx.QueryRow(ctx, secretQuery, s.EncryptedPayload, s.EncryptionVersion, s.ID).
-		Scan(&s.UpdatedAt)
+	err = tx.QueryRow(ctx, secretQuery, s.EncryptedPayload, s.EncryptionVersion, s.ExpiresAt, s.RotateEveryDays, s.ID).
+		Scan(&s.UpdatedAt, &s.RotatedAt)
 	if err != nil {
 		return err
 	}
 	// Update metadata
 	metadataQuery := `
 		UPDATE secret_metadata
-		SET title = $1, domain = $2, tags = $3
-		WHERE secret_id = $4
+		SET title = $1, domain = $2, tags = $3, attributes = COALESCE($4, '{}'::jsonb), custom_fields = COALESCE($5, '{}'::jsonb)
+		WHERE secret_id = $6
 		RETURNING updated_at
 	`
-	err = tx.QueryRow(ctx, metadataQuery, m.Title, m.Domain, m.Tags, s.ID).
+	err = tx.QueryRow(ctx, metadataQuery, m.Title, m.Domain, m.Tags, m.Attributes, m.CustomFields, s.ID).
 		Scan(&m.UpdatedAt)
 	if err != nil {
 		return err
@@ -187,13 +310,13 @@ func (r *SecretRepository) Update(ctx context.Context, s *secret.Secret, m *secr
 // Delete - Delete a secret (cascade deletes metadata)
 func (r *SecretRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM secrets WHERE id = $1`
-	_, err := r.server.DB.Pool.Exec(ctx, query, id)
+	_, err := r.db(ctx).Exec(ctx, query, id)
 	return err
 }
 
 // Helper function to query secrets
 func (r *SecretRepository) querySecrets(ctx context.Context, query string, args ...interface{}) ([]*SecretWithMetadata, error) {
-	rows, err := r.server.DB.Pool.Query(ctx, query, args...)
+	rows, err := r.db(ctx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -203,9 +326,9 @@ func (r *SecretRepository) querySecrets(ctx context.Context, query string, args
 		var s secret.Secret
 		var m secret.SecretMetadata
 		if err := rows.Scan(
-			&s.ID, &s.VaultID, &s.Type, &s.EncryptedPayload, &s.EncryptionVersion,
-			&s.LastAccessedAt, &s.CreatedAt, &s.UpdatedAt,
-			&m.ID, &m.Title, &m.Domain, &m.Tags, &m.CreatedAt, &m.UpdatedAt,
+			&s.ID, &s.VaultID, &s.FolderID, &s.Type, &s.EncryptedPayload, &s.EncryptionVersion,
+			&s.LastAccessedAt, &s.CreatedAt, &s.UpdatedAt, &s.ExpiresAt, &s.RotateEveryDays, &s.RotatedAt,
+			&m.ID, &m.Title, &m.Domain, &m.Tags, &m.Attributes, &m.CustomFields, &m.CreatedAt, &m.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Sameer16536/psvault/internal/lib/searchquery"
+)
+
+// searchCompiler turns a parsed search query into a SQL condition over
+// secrets (s) and secret_metadata (m), appending its parameters to args
+type searchCompiler struct {
+	args []interface{}
+	// tsquery text of every free-text term that is not negated, for ranking
+	rank []string
+}
+
+func (c *searchCompiler) arg(v interface{}) string {
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *searchCompiler) compile(n searchquery.Node, negated bool) string {
+	switch n := n.(type) {
+	case searchquery.And:
+		return c.compileAll(n.Children, " AND ", negated)
+	case searchquery.Or:
+		return c.compileAll(n.Children, " OR ", negated)
+	case searchquery.Not:
+		return "NOT " + c.compile(n.Child, !negated)
+	case searchquery.Term:
+		// Metadata columns can be NULL; a term must still be false, not NULL, under NOT
+		return "COALESCE(" + c.compileTerm(n, negated) + ", FALSE)"
+	default:
+		return "FALSE"
+	}
+}
+
+func (c *searchCompiler) compileAll(children []searchquery.Node, op string, negated bool) string {
+	parts := make([]string, len(children))
+	for i, child := range children {
+		parts[i] = c.compile(child, negated)
+	}
+	return "(" + strings.Join(parts, op) + ")"
+}
+
+func (c *searchCompiler) compileTerm(t searchquery.Term, negated bool) string {
+	switch t.Field {
+	case "":
+		tsquery := toTSQuery(t.Value, t.Phrase)
+		if tsquery == "" {
+			return "TRUE"
+		}
+		if !negated {
+			c.rank = append(c.rank, tsquery)
+		}
+		return fmt.Sprintf("m.search_vector @@ to_tsquery('simple', %s)", c.arg(tsquery))
+	case "tag":
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM unnest(m.tags) AS t(tag) WHERE lower(t.tag) = lower(%s))", c.arg(t.Value))
+	case "type":
+		return fmt.Sprintf("s.type = %s", c.arg(strings.ToLower(t.Value)))
+	case "title":
+		return fmt.Sprintf("m.title ILIKE %s", c.arg("%"+escapeLike(t.Value)+"%"))
+	case "domain":
+		return fmt.Sprintf("m.domain ILIKE %s", c.arg("%"+escapeLike(t.Value)+"%"))
+	case "vault":
+		return fmt.Sprintf("s.vault_id::text = %s", c.arg(strings.ToLower(t.Value)))
+	default:
+		// Any other qualifier names a custom field or a type attribute
+		key := c.arg(t.Field)
+		return fmt.Sprintf("lower(COALESCE(m.custom_fields ->> %s, m.attributes ->> %s)) = lower(%s)", key, key, c.arg(t.Value))
+	}
+}
+
+// rankExpression returns an ORDER BY expression ranking matches of the
+// free-text terms, or "" if the query has none
+func (c *searchCompiler) rankExpression() string {
+	if len(c.rank) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("ts_rank(m.search_vector, to_tsquery('simple', %s))", c.arg(strings.Join(c.rank, " | ")))
+}
+
+// toTSQuery builds tsquery text matching value: a prefix match for a single
+// word, or the words in sequence for a phrase. Each word is quoted so user
+// input is never interpreted as tsquery operators.
+func toTSQuery(value string, phrase bool) string {
+	var lexemes []string
+	for _, word := range strings.Fields(value) {
+		if strings.IndexFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		word = strings.ReplaceAll(word, `\`, `\\`)
+		word = strings.ReplaceAll(word, `'`, `''`)
+		lexemes = append(lexemes, "'"+word+"'")
+	}
+	if len(lexemes) == 0 {
+		return ""
+	}
+	if phrase {
+		return strings.Join(lexemes, " <-> ")
+	}
+	for i := range lexemes {
+		lexemes[i] += ":*"
+	}
+	return strings.Join(lexemes, " & ")
+}
+
+// escapeLike escapes LIKE wildcards so value matches literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
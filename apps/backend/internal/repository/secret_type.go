@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type SecretTypeRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewSecretTypeRepository(s *server.Server) *SecretTypeRepository {
+	return &SecretTypeRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *SecretTypeRepository) WithTx(tx pgx.Tx) *SecretTypeRepository {
+	return &SecretTypeRepository{server: r.server, tx: tx}
+}
+
+func (r *SecretTypeRepository) db() Querier {
+	return querier(r.server, r.tx)
+}
+
+// List - List all registered secret types
+func (r *SecretTypeRepository) List(ctx context.Context) ([]*secret.TypeDefinition, error) {
+	query := `
+		SELECT name, display_name, description, fields, created_at, updated_at
+		FROM secret_types
+		ORDER BY name ASC
+	`
+	rows, err := r.db().Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var types []*secret.TypeDefinition
+	for rows.Next() {
+		var t secret.TypeDefinition
+		if err := rows.Scan(&t.Name, &t.DisplayName, &t.Description, &t.Fields, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		types = append(types, &t)
+	}
+	return types, rows.Err()
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model/serviceaccount"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type ServiceAccountRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewServiceAccountRepository(s *server.Server) *ServiceAccountRepository {
+	return &ServiceAccountRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *ServiceAccountRepository) WithTx(tx pgx.Tx) *ServiceAccountRepository {
+	return &ServiceAccountRepository{server: r.server, tx: tx}
+}
+
+func (r *ServiceAccountRepository) db() Querier {
+	return querier(r.server, r.tx)
+}
+
+// Create - Create a new service account
+func (r *ServiceAccountRepository) Create(ctx context.Context, sa *serviceaccount.ServiceAccount) error {
+	query := `
+		INSERT INTO service_accounts (org_id, name, description, created_by, client_secret_hash)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db().QueryRow(ctx, query, sa.OrgID, sa.Name, sa.Description, sa.CreatedBy, sa.ClientSecretHash).
+		Scan(&sa.ID, &sa.CreatedAt, &sa.UpdatedAt)
+}
+
+// GetByID - Get service account by ID
+func (r *ServiceAccountRepository) GetByID(ctx context.Context, id string) (*serviceaccount.ServiceAccount, error) {
+	query := `
+		SELECT id, org_id, name, description, COALESCE(created_by::text, ''), client_secret_hash, last_used_at, disabled_at, created_at, updated_at
+		FROM service_accounts
+		WHERE id = $1
+	`
+	var sa serviceaccount.ServiceAccount
+	err := r.db().QueryRow(ctx, query, id).Scan(
+		&sa.ID, &sa.OrgID, &sa.Name, &sa.Description, &sa.CreatedBy, &sa.ClientSecretHash,
+		&sa.LastUsedAt, &sa.DisabledAt, &sa.CreatedAt, &sa.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sa, nil
+}
+
+// ListByOrgID - List all service accounts in an organization
+func (r *ServiceAccountRepository) ListByOrgID(ctx context.Context, orgID string) ([]*serviceaccount.ServiceAccount, error) {
+	query := `
+		SELECT id, org_id, name, description, COALESCE(created_by::text, ''), client_secret_hash, last_used_at, disabled_at, created_at, updated_at
+		FROM service_accounts
+		WHERE org_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db().Query(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var accounts []*serviceaccount.ServiceAccount
+	for rows.Next() {
+		var sa serviceaccount.ServiceAccount
+		if err := rows.Scan(
+			&sa.ID, &sa.OrgID, &sa.Name, &sa.Description, &sa.CreatedBy, &sa.ClientSecretHash,
+			&sa.LastUsedAt, &sa.DisabledAt, &sa.CreatedAt, &sa.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, &sa)
+	}
+	return accounts, rows.Err()
+}
+
+// Disable - Disable a service account and revoke its outstanding tokens (transaction)
+func (r *ServiceAccountRepository) Disable(ctx context.Context, id string) error {
+	tx, err := r.db().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, `UPDATE service_accounts SET disabled_at = $1 WHERE id = $2 AND disabled_at IS NULL`, time.Now(), id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM service_account_tokens WHERE service_account_id = $1`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM vault_members WHERE service_account_id = $1`, id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// CreateToken - Store a newly issued access token
+func (r *ServiceAccountRepository) CreateToken(ctx context.Context, t *serviceaccount.Token) error {
+	query := `
+		INSERT INTO service_account_tokens (service_account_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	return r.db().QueryRow(ctx, query, t.ServiceAccountID, t.TokenHash, t.ExpiresAt).
+		Scan(&t.ID, &t.CreatedAt)
+}
+
+// GetByTokenHash - Resolve an unexpired access token to its enabled service account
+func (r *ServiceAccountRepository) GetByTokenHash(ctx context.Context, tokenHash []byte) (*serviceaccount.ServiceAccount, error) {
+	query := `
+		SELECT sa.id, sa.org_id, sa.name, sa.description, COALESCE(sa.created_by::text, ''), sa.client_secret_hash,
+			sa.last_used_at, sa.disabled_at, sa.created_at, sa.updated_at
+		FROM service_account_tokens t
+		INNER JOIN service_accounts sa ON sa.id = t.service_account_id
+		WHERE t.token_hash = $1 AND t.expires_at > NOW() AND sa.disabled_at IS NULL
+	`
+	var sa serviceaccount.ServiceAccount
+	err := r.db().QueryRow(ctx, query, tokenHash).Scan(
+		&sa.ID, &sa.OrgID, &sa.Name, &sa.Description, &sa.CreatedBy, &sa.ClientSecretHash,
+		&sa.LastUsedAt, &sa.DisabledAt, &sa.CreatedAt, &sa.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sa, nil
+}
+
+// UpdateLastUsed - Update service account last used timestamp
+func (r *ServiceAccountRepository) UpdateLastUsed(ctx context.Context, id string) error {
+	query := `UPDATE service_accounts SET last_used_at = $1 WHERE id = $2`
+	_, err := r.db().Exec(ctx, query, time.Now(), id)
+	return err
+}
+
+// DeleteExpiredTokens - Remove access tokens past their expiry
+func (r *ServiceAccountRepository) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	tag, err := r.db().Exec(ctx, `DELETE FROM service_account_tokens WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
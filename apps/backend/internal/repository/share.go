@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/psvault/internal/model/share"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type ShareRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewShareRepository(s *server.Server) *ShareRepository {
+	return &ShareRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *ShareRepository) WithTx(tx pgx.Tx) *ShareRepository {
+	return &ShareRepository{server: r.server, tx: tx}
+}
+
+func (r *ShareRepository) db() Querier {
+	return querier(r.server, r.tx)
+}
+
+// Create - Create a new share link
+func (r *ShareRepository) Create(ctx context.Context, sh *share.Share) error {
+	query := `
+		INSERT INTO shares (owner_id, encrypted_payload, max_views, views_remaining, expires_at, password_hash)
+		VALUES ($1, $2, $3, $3, $4, $5)
+		RETURNING id, views_remaining, created_at
+	`
+	return r.db().QueryRow(ctx, query, sh.OwnerID, sh.EncryptedPayload, sh.MaxViews, sh.ExpiresAt, sh.PasswordHash).
+		Scan(&sh.ID, &sh.ViewsRemaining, &sh.CreatedAt)
+}
+
+// GetActive - Get a share that still has views left and has not expired, without its payload
+func (r *ShareRepository) GetActive(ctx context.Context, id string) (*share.Share, error) {
+	query := `
+		SELECT id, owner_id, max_views, views_remaining, expires_at, password_hash, purged_at, created_at
+		FROM shares
+		WHERE id = $1 AND purged_at IS NULL AND views_remaining > 0 AND expires_at > NOW()
+	`
+	var sh share.Share
+	err := r.db().QueryRow(ctx, query, id).Scan(
+		&sh.ID, &sh.OwnerID, &sh.MaxViews, &sh.ViewsRemaining, &sh.ExpiresAt, &sh.PasswordHash, &sh.PurgedAt, &sh.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+// Consume - Atomically use one view of a share and return its payload.
+// The payload is purged in the same statement when the last view is used.
+// Returns nil if the share is exhausted, expired or purged.
+func (r *ShareRepository) Consume(ctx context.Context, id string) (*share.Share, error) {
+	query := `
+		WITH target AS (
+			SELECT id, encrypted_payload
+			FROM shares
+			WHERE id = $1 AND purged_at IS NULL AND views_remaining > 0 AND expires_at > NOW()
+			FOR UPDATE
+		)
+		UPDATE shares s
+		SET views_remaining = s.views_remaining - 1,
+			encrypted_payload = CASE WHEN s.views_remaining = 1 THEN NULL ELSE s.encrypted_payload END,
+			purged_at = CASE WHEN s.views_remaining = 1 THEN NOW() ELSE NULL END
+		FROM target
+		WHERE s.id = target.id
+		RETURNING s.id, s.owner_id, target.encrypted_payload, s.max_views, s.views_remaining, s.expires_at, s.purged_at, s.created_at
+	`
+	var sh share.Share
+	err := r.db().QueryRow(ctx, query, id).Scan(
+		&sh.ID, &sh.OwnerID, &sh.EncryptedPayload, &sh.MaxViews, &sh.ViewsRemaining, &sh.ExpiresAt, &sh.PurgedAt, &sh.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sh, nil
+}
+
+// RecordFailedPassword - Count a wrong password given for a share, purging the
+// share once maxAttempts are reached. Reports whether the share is now purged.
+func (r *ShareRepository) RecordFailedPassword(ctx context.Context, id string, maxAttempts int) (bool, error) {
+	query := `
+		UPDATE shares
+		SET failed_password_attempts = failed_password_attempts + 1,
+			encrypted_payload = CASE WHEN failed_password_attempts + 1 >= $2 THEN NULL ELSE encrypted_payload END,
+			purged_at = CASE WHEN failed_password_attempts + 1 >= $2 THEN NOW() ELSE NULL END
+		WHERE id = $1 AND purged_at IS NULL
+		RETURNING purged_at IS NOT NULL
+	`
+	var purged bool
+	err := r.db().QueryRow(ctx, query, id, maxAttempts).Scan(&purged)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return purged, err
+}
+
+// ListByOwnerID - List share links created by a user
+func (r *ShareRepository) ListByOwnerID(ctx context.Context, ownerID string) ([]*share.Share, error) {
+	query := `
+		SELECT id, owner_id, max_views, views_remaining, expires_at, password_hash, purged_at, created_at
+		FROM shares
+		WHERE owner_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db().Query(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var shares []*share.Share
+	for rows.Next() {
+		var sh share.Share
+		if err := rows.Scan(
+			&sh.ID, &sh.OwnerID, &sh.MaxViews, &sh.ViewsRemaining, &sh.ExpiresAt, &sh.PasswordHash, &sh.PurgedAt, &sh.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		shares = append(shares, &sh)
+	}
+	return shares, rows.Err()
+}
+
+// Revoke - Purge a share owned by ownerID; reports whether a live share was revoked
+func (r *ShareRepository) Revoke(ctx context.Context, id, ownerID string) (bool, error) {
+	query := `
+		UPDATE shares
+		SET encrypted_payload = NULL, purged_at = NOW()
+		WHERE id = $1 AND owner_id = $2 AND purged_at IS NULL
+	`
+	tag, err := r.db().Exec(ctx, query, id, ownerID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SweepExpired - Purge the payloads of expired shares, then delete shares purged
+// more than a week ago (kept that long so owners can still see them listed)
+func (r *ShareRepository) SweepExpired(ctx context.Context) (purged, deleted int64, err error) {
+	tag, err := r.db().Exec(ctx, `
+		UPDATE shares
+		SET encrypted_payload = NULL, purged_at = NOW()
+		WHERE purged_at IS NULL AND expires_at <= NOW()
+	`)
+	if err != nil {
+		return 0, 0, err
+	}
+	purged = tag.RowsAffected()
+	tag, err = r.db().Exec(ctx, `DELETE FROM shares WHERE purged_at <= NOW() - INTERVAL '7 days'`)
+	if err != nil {
+		return purged, 0, err
+	}
+	return purged, tag.RowsAffected(), nil
+}
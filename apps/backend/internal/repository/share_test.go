@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/model/share"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupShareRepository(t *testing.T) (*tt.TestDB, *repository.ShareRepository, func()) {
+	t.Helper()
+	testDB, cleanup := tt.SetupTestDB(t)
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	return testDB, repository.NewShareRepository(srv), cleanup
+}
+
+// Helper to create a share link
+func createTestShare(t *testing.T, ctx context.Context, repo *repository.ShareRepository, maxViews int, expiresAt time.Time) *share.Share {
+	t.Helper()
+	sh := &share.Share{OwnerID: "owner", EncryptedPayload: []byte("payload"), MaxViews: maxViews, ExpiresAt: expiresAt}
+	require.NoError(t, repo.Create(ctx, sh), "setup: failed to create share")
+	return sh
+}
+
+// Test: Concurrent views never use more views than a share has; the last one purges the payload
+func TestShareRepository_Consume_Concurrent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, repo, cleanup := setupShareRepository(t)
+	defer cleanup()
+	ctx := context.Background()
+	sh := createTestShare(t, ctx, repo, 2, time.Now().Add(time.Hour))
+
+	const requests = 8
+	consumed := make([]*share.Share, requests)
+	errs := make([]error, requests)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			consumed[i], errs[i] = repo.Consume(ctx, sh.ID.String())
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	var remaining []int
+	for i := range requests {
+		require.NoError(t, errs[i])
+		if consumed[i] != nil {
+			assert.Equal(t, []byte("payload"), consumed[i].EncryptedPayload)
+			remaining = append(remaining, consumed[i].ViewsRemaining)
+		}
+	}
+	assert.ElementsMatch(t, []int{1, 0}, remaining)
+
+	var payload []byte
+	var purgedAt *time.Time
+	require.NoError(t, testDB.Pool.QueryRow(ctx, `SELECT encrypted_payload, purged_at FROM shares WHERE id = $1`, sh.ID).Scan(&payload, &purgedAt))
+	assert.Nil(t, payload)
+	assert.NotNil(t, purgedAt)
+
+	active, err := repo.GetActive(ctx, sh.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, active)
+}
+
+// Test: Expired shares cannot be viewed
+func TestShareRepository_Consume_Expired(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, repo, cleanup := setupShareRepository(t)
+	defer cleanup()
+	ctx := context.Background()
+	sh := createTestShare(t, ctx, repo, 3, time.Now().Add(-time.Minute))
+
+	active, err := repo.GetActive(ctx, sh.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, active)
+	consumed, err := repo.Consume(ctx, sh.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, consumed)
+}
+
+// Test: A share is purged when its wrong passwords reach the limit
+func TestShareRepository_RecordFailedPassword(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, repo, cleanup := setupShareRepository(t)
+	defer cleanup()
+	ctx := context.Background()
+	sh := createTestShare(t, ctx, repo, 1, time.Now().Add(time.Hour))
+
+	for range 2 {
+		purged, err := repo.RecordFailedPassword(ctx, sh.ID.String(), 3)
+		require.NoError(t, err)
+		assert.False(t, purged)
+	}
+	purged, err := repo.RecordFailedPassword(ctx, sh.ID.String(), 3)
+	require.NoError(t, err)
+	assert.True(t, purged)
+
+	consumed, err := repo.Consume(ctx, sh.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, consumed)
+
+	// Purged shares are left alone
+	purged, err = repo.RecordFailedPassword(ctx, sh.ID.String(), 3)
+	require.NoError(t, err)
+	assert.False(t, purged)
+}
+
+// Test: The sweep purges expired shares and deletes ones purged over a week ago
+func TestShareRepository_SweepExpired(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, repo, cleanup := setupShareRepository(t)
+	defer cleanup()
+	ctx := context.Background()
+	live := createTestShare(t, ctx, repo, 1, time.Now().Add(time.Hour))
+	expired := createTestShare(t, ctx, repo, 1, time.Now().Add(-time.Minute))
+	old := createTestShare(t, ctx, repo, 1, time.Now().Add(-time.Minute))
+	_, err := testDB.Pool.Exec(ctx, `UPDATE shares SET encrypted_payload = NULL, purged_at = NOW() - INTERVAL '8 days' WHERE id = $1`, old.ID)
+	require.NoError(t, err)
+
+	purged, deleted, err := repo.SweepExpired(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, purged)
+	assert.EqualValues(t, 1, deleted)
+
+	shares, err := repo.ListByOwnerID(ctx, "owner")
+	require.NoError(t, err)
+	byID := make(map[string]*share.Share)
+	for _, sh := range shares {
+		byID[sh.ID.String()] = sh
+	}
+	require.Len(t, byID, 2)
+	assert.Nil(t, byID[live.ID.String()].PurgedAt)
+	assert.NotNil(t, byID[expired.ID.String()].PurgedAt)
+	assert.NotContains(t, byID, old.ID.String())
+}
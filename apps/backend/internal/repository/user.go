@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/psvault/internal/model/user"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type UserRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewUserRepository(s *server.Server) *UserRepository {
+	return &UserRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *UserRepository) WithTx(tx pgx.Tx) *UserRepository {
+	return &UserRepository{server: r.server, tx: tx}
+}
+
+func (r *UserRepository) db() Querier {
+	return querier(r.server, r.tx)
+}
+
+// Resolve - Get the internal ID of a Clerk user, registering the user on first sight.
+// Concurrent first sign-ins race on the insert; the no-op update makes the
+// loser return the winner's row instead of nothing.
+func (r *UserRepository) Resolve(ctx context.Context, externalAuthID string) (string, error) {
+	var id string
+	err := r.db().QueryRow(ctx, `SELECT id FROM users WHERE external_auth_id = $1`, externalAuthID).Scan(&id)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return id, err
+	}
+	query := `
+		INSERT INTO users (external_auth_id)
+		VALUES ($1)
+		ON CONFLICT (external_auth_id) DO UPDATE SET external_auth_id = EXCLUDED.external_auth_id
+		RETURNING id
+	`
+	err = r.db().QueryRow(ctx, query, externalAuthID).Scan(&id)
+	return id, err
+}
+
+// GetByID - Get user by internal ID
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*user.User, error) {
+	return r.getBy(ctx, "id", id)
+}
+
+// GetByExternalAuthID - Get user by Clerk user ID
+func (r *UserRepository) GetByExternalAuthID(ctx context.Context, externalAuthID string) (*user.User, error) {
+	return r.getBy(ctx, "external_auth_id", externalAuthID)
+}
+
+func (r *UserRepository) getBy(ctx context.Context, column, value string) (*user.User, error) {
+	query := `
+		SELECT id, external_auth_id, email, name, last_login_at, created_at, updated_at
+		FROM users
+		WHERE ` + column + ` = $1
+	`
+	var u user.User
+	err := r.db().QueryRow(ctx, query, value).Scan(
+		&u.ID, &u.ExternalAuthID, &u.Email, &u.Name, &u.LastLoginAt, &u.CreatedAt, &u.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,58 @@
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: Concurrent first sign-ins of one user all resolve to the same, single row
+func TestUserRepository_Resolve_Concurrent(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	repo := repository.NewUserRepository(srv)
+	ctx := context.Background()
+
+	const requests = 16
+	ids := make([]string, requests)
+	errs := make([]error, requests)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			ids[i], errs[i] = repo.Resolve(ctx, "user_first_sign_in")
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for i := range requests {
+		require.NoError(t, errs[i])
+		assert.Equal(t, ids[0], ids[i])
+	}
+
+	var count int
+	require.NoError(t, testDB.Pool.QueryRow(ctx, `SELECT count(*) FROM users WHERE external_auth_id = $1`, "user_first_sign_in").Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// Later requests find the user without writing
+	again, err := repo.Resolve(ctx, "user_first_sign_in")
+	require.NoError(t, err)
+	assert.Equal(t, ids[0], again)
+}
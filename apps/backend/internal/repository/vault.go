@@ -3,26 +3,41 @@ package repository
 import (
 	"context"
 
+	"github.com/Sameer16536/psvault/internal/model/audit"
 	"github.com/Sameer16536/psvault/internal/model/vault"
 	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
 )
 
 type VaultRepository struct {
 	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
 }
 
 func NewVaultRepository(s *server.Server) *VaultRepository {
 	return &VaultRepository{server: s}
 }
 
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *VaultRepository) WithTx(tx pgx.Tx) *VaultRepository {
+	return &VaultRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *VaultRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
 // Create - Create a new vault
 func (r *VaultRepository) Create(ctx context.Context, v *vault.Vault) error {
 	query := `
-		INSERT INTO vaults (user_id, name, description, encrypted_key, key_encryption_version)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO vaults (user_id, org_id, name, description, encrypted_key, key_encryption_version)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, created_at, updated_at
 	`
-	return r.server.DB.Pool.QueryRow(ctx, query, v.UserID, v.Name, v.Description, v.EncryptedKey, v.KeyEncryptionVersion).
+	return r.db(ctx).QueryRow(ctx, query, v.UserID, v.OrgID, v.Name, v.Description, v.EncryptedKey, v.KeyEncryptionVersion).
 		Scan(&v.ID, &v.CreatedAt, &v.UpdatedAt)
 }
 
@@ -30,12 +45,12 @@ func (r *VaultRepository) Create(ctx context.Context, v *vault.Vault) error {
 func (r *VaultRepository) GetByID(ctx context.Context, id string) (*vault.Vault, error) {
 	var v vault.Vault
 	query := `
-		SELECT id, user_id, name, description, encrypted_key, key_encryption_version, created_at, updated_at
+		SELECT id, user_id, org_id, name, description, encrypted_key, key_encryption_version, created_at, updated_at
 		FROM vaults
 		WHERE id = $1
 	`
-	err := r.server.DB.Pool.QueryRow(ctx, query, id).Scan(
-		&v.ID, &v.UserID, &v.Name, &v.Description, &v.EncryptedKey, &v.KeyEncryptionVersion, &v.CreatedAt, &v.UpdatedAt,
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(
+		&v.ID, &v.UserID, &v.OrgID, &v.Name, &v.Description, &v.EncryptedKey, &v.KeyEncryptionVersion, &v.CreatedAt, &v.UpdatedAt,
 	)
 	if err != nil {
 		// pgx returns pgx.ErrNoRows instead of sql.ErrNoRows
@@ -50,12 +65,64 @@ func (r *VaultRepository) GetByID(ctx context.Context, id string) (*vault.Vault,
 // ListByUserID - List all vaults for a user
 func (r *VaultRepository) ListByUserID(ctx context.Context, userID string) ([]*vault.Vault, error) {
 	query := `
-		SELECT id, user_id, name, description, encrypted_key, key_encryption_version, created_at, updated_at
+		SELECT id, user_id, org_id, name, description, encrypted_key, key_encryption_version, created_at, updated_at
 		FROM vaults
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 	`
-	rows, err := r.server.DB.Pool.Query(ctx, query, userID)
+	rows, err := r.db(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vaults []*vault.Vault
+	for rows.Next() {
+		var v vault.Vault
+		if err := rows.Scan(&v.ID, &v.UserID, &v.OrgID, &v.Name, &v.Description, &v.EncryptedKey, &v.KeyEncryptionVersion, &v.CreatedAt, &v.UpdatedAt); err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, &v)
+	}
+	return vaults, rows.Err()
+}
+
+// ListByOrgID - List all vaults owned by an organization
+func (r *VaultRepository) ListByOrgID(ctx context.Context, orgID string) ([]*vault.Vault, error) {
+	query := `
+		SELECT id, user_id, org_id, name, description, encrypted_key, key_encryption_version, created_at, updated_at
+		FROM vaults
+		WHERE org_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var vaults []*vault.Vault
+	for rows.Next() {
+		var v vault.Vault
+		if err := rows.Scan(&v.ID, &v.UserID, &v.OrgID, &v.Name, &v.Description, &v.EncryptedKey, &v.KeyEncryptionVersion, &v.CreatedAt, &v.UpdatedAt); err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, &v)
+	}
+	return vaults, rows.Err()
+}
+
+// ListByMember - List vaults a principal has been granted access to.
+// EncryptedKey is the vault key wrapped for that member rather than the owner's copy.
+func (r *VaultRepository) ListByMember(ctx context.Context, memberType audit.ActorType, memberID string) ([]*vault.Vault, error) {
+	query := `
+		SELECT v.id, v.user_id, v.org_id, v.name, v.description, m.encrypted_key, v.key_encryption_version, v.created_at, v.updated_at
+		FROM vaults v
+		INNER JOIN vault_members m ON m.vault_id = v.id
+		WHERE m.member_type = $1 AND m.member_id = $2
+		ORDER BY v.created_at DESC
+	`
+	rows, err := r.db(ctx).Query(ctx, query, memberType, memberID)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +131,7 @@ func (r *VaultRepository) ListByUserID(ctx context.Context, userID string) ([]*v
 	var vaults []*vault.Vault
 	for rows.Next() {
 		var v vault.Vault
-		if err := rows.Scan(&v.ID, &v.UserID, &v.Name, &v.Description, &v.EncryptedKey, &v.KeyEncryptionVersion, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		if err := rows.Scan(&v.ID, &v.UserID, &v.OrgID, &v.Name, &v.Description, &v.EncryptedKey, &v.KeyEncryptionVersion, &v.CreatedAt, &v.UpdatedAt); err != nil {
 			return nil, err
 		}
 		vaults = append(vaults, &v)
@@ -80,13 +147,13 @@ func (r *VaultRepository) Update(ctx context.Context, v *vault.Vault) error {
 		WHERE id = $3
 		RETURNING updated_at
 	`
-	return r.server.DB.Pool.QueryRow(ctx, query, v.Name, v.Description, v.ID).
+	return r.db(ctx).QueryRow(ctx, query, v.Name, v.Description, v.ID).
 		Scan(&v.UpdatedAt)
 }
 
 // Delete - Delete a vault
 func (r *VaultRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM vaults WHERE id = $1`
-	_, err := r.server.DB.Pool.Exec(ctx, query, id)
+	_, err := r.db(ctx).Exec(ctx, query, id)
 	return err
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type VaultMemberRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewVaultMemberRepository(s *server.Server) *VaultMemberRepository {
+	return &VaultMemberRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *VaultMemberRepository) WithTx(tx pgx.Tx) *VaultMemberRepository {
+	return &VaultMemberRepository{server: r.server, tx: tx}
+}
+
+func (r *VaultMemberRepository) db() Querier {
+	return querier(r.server, r.tx)
+}
+
+// Upsert - Grant a principal access to a vault, replacing any existing grant
+func (r *VaultMemberRepository) Upsert(ctx context.Context, m *vault.Member) error {
+	query := `
+		INSERT INTO vault_members (vault_id, member_type, user_id, service_account_id, role, encrypted_key, granted_by)
+		VALUES (
+			$1, $2,
+			CASE WHEN $2::actor_type = 'user' THEN $3::uuid END,
+			CASE WHEN $2::actor_type = 'service_account' THEN $3::uuid END,
+			$4, $5, NULLIF($6, '')::uuid
+		)
+		ON CONFLICT (vault_id, member_type, member_id)
+		DO UPDATE SET role = EXCLUDED.role, encrypted_key = EXCLUDED.encrypted_key, granted_by = EXCLUDED.granted_by
+		RETURNING id, created_at, updated_at
+	`
+	return r.db().QueryRow(ctx, query, m.VaultID, m.MemberType, m.MemberID, m.Role, m.EncryptedKey, m.GrantedBy).
+		Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt)
+}
+
+// Get - Get a principal's membership of a vault
+func (r *VaultMemberRepository) Get(ctx context.Context, vaultID string, memberType audit.ActorType, memberID string) (*vault.Member, error) {
+	query := `
+		SELECT id, vault_id, member_type, member_id, role, encrypted_key, COALESCE(granted_by::text, ''), created_at, updated_at
+		FROM vault_members
+		WHERE vault_id = $1 AND member_type = $2 AND member_id = $3
+	`
+	var m vault.Member
+	err := r.db().QueryRow(ctx, query, vaultID, memberType, memberID).Scan(
+		&m.ID, &m.VaultID, &m.MemberType, &m.MemberID, &m.Role, &m.EncryptedKey, &m.GrantedBy, &m.CreatedAt, &m.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListByVaultID - List all members of a vault
+func (r *VaultMemberRepository) ListByVaultID(ctx context.Context, vaultID string) ([]*vault.Member, error) {
+	query := `
+		SELECT id, vault_id, member_type, member_id, role, encrypted_key, COALESCE(granted_by::text, ''), created_at, updated_at
+		FROM vault_members
+		WHERE vault_id = $1
+		ORDER BY created_at
+	`
+	rows, err := r.db().Query(ctx, query, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var members []*vault.Member
+	for rows.Next() {
+		var m vault.Member
+		if err := rows.Scan(&m.ID, &m.VaultID, &m.MemberType, &m.MemberID, &m.Role, &m.EncryptedKey, &m.GrantedBy, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, &m)
+	}
+	return members, rows.Err()
+}
+
+// Delete - Revoke a membership; reports whether one was revoked
+func (r *VaultMemberRepository) Delete(ctx context.Context, vaultID, memberID string) (bool, error) {
+	query := `DELETE FROM vault_members WHERE vault_id = $1 AND id = $2`
+	tag, err := r.db().Exec(ctx, query, vaultID, memberID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
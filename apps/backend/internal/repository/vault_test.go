@@ -18,7 +18,7 @@ import (
 func createTestUser(t *testing.T, ctx context.Context, testDB *tt.TestDB, email string) string {
 	t.Helper()
 	userID := uuid.New().String()
-	_, err := testDB.Pool.Exec(ctx, "INSERT INTO users (id, email, created_at, updated_at) VALUES ($1, $2, NOW(), NOW())", userID, email)
+	_, err := testDB.Pool.Exec(ctx, "INSERT INTO users (id, external_auth_id, email) VALUES ($1, $2, $3)", userID, "user_"+userID, email)
 	require.NoError(t, err, "setup: failed to create user")
 	return userID
 }
@@ -35,7 +35,7 @@ func TestVaultRepository_Create_Success(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "test@example.com")
 
@@ -77,7 +77,7 @@ func TestVaultRepository_Create_MinimalFields(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "minimal@example.com")
 
@@ -104,7 +104,7 @@ func TestVaultRepository_Create_InvalidUser(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	v := &vault.Vault{
 		UserID:       uuid.New().String(), // Non-existent user
@@ -128,7 +128,7 @@ func TestVaultRepository_GetByID_Success(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "getbyid@example.com")
 
@@ -160,7 +160,7 @@ func TestVaultRepository_GetByID_NotFound(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	retrieved, err := repo.GetByID(ctx, uuid.New().String())
 	assert.NoError(t, err, "GetByID returns nil, nil for not found")
@@ -179,7 +179,7 @@ func TestVaultRepository_ListByUserID_MultipleVaults(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "list@example.com")
 
@@ -211,7 +211,7 @@ func TestVaultRepository_ListByUserID_Empty(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "empty@example.com")
 
@@ -232,7 +232,7 @@ func TestVaultRepository_Update_Success(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "update@example.com")
 
@@ -271,7 +271,7 @@ func TestVaultRepository_Delete_Success(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "delete@example.com")
 
@@ -304,7 +304,7 @@ func TestVaultRepository_EncryptedKeyPersistence(t *testing.T) {
 	db := &database.Database{Pool: testDB.Pool}
 	srv := &server.Server{DB: db}
 	repo := repository.NewVaultRepository(srv)
-	ctx := context.Background()
+	ctx := repository.Unscoped(context.Background())
 
 	userID := createTestUser(t, ctx, testDB, "crypto@example.com")
 
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Sameer16536/psvault/internal/model/webhook"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/jackc/pgx/v5"
+)
+
+type WebhookRepository struct {
+	server *server.Server
+	// tx is set on copies returned by WithTx
+	tx pgx.Tx
+}
+
+func NewWebhookRepository(s *server.Server) *WebhookRepository {
+	return &WebhookRepository{server: s}
+}
+
+// WithTx - Copy of the repository that runs its queries in tx
+func (r *WebhookRepository) WithTx(tx pgx.Tx) *WebhookRepository {
+	return &WebhookRepository{server: r.server, tx: tx}
+}
+
+// db runs queries in the repository's transaction, if any, restricted to the
+// actor in ctx by row-level security
+func (r *WebhookRepository) db(ctx context.Context) Querier {
+	return scoped(ctx, querier(r.server, r.tx))
+}
+
+const webhookColumns = `id, user_id, org_id, url, description, event_types, secret, consecutive_failures, disabled_at, disabled_reason, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*webhook.Webhook, error) {
+	var w webhook.Webhook
+	err := row.Scan(
+		&w.ID, &w.UserID, &w.OrgID, &w.URL, &w.Description, &w.EventTypes, &w.Secret,
+		&w.ConsecutiveFailures, &w.DisabledAt, &w.DisabledReason, &w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *WebhookRepository) list(ctx context.Context, query string, args ...any) ([]*webhook.Webhook, error) {
+	rows, err := r.db(ctx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var webhooks []*webhook.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+// Create - Create a webhook
+func (r *WebhookRepository) Create(ctx context.Context, w *webhook.Webhook) error {
+	if w.EventTypes == nil {
+		w.EventTypes = []string{}
+	}
+	query := `
+		INSERT INTO webhooks (user_id, org_id, url, description, event_types, secret)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db(ctx).QueryRow(ctx, query, w.UserID, w.OrgID, w.URL, w.Description, w.EventTypes, w.Secret).
+		Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+// GetByID - Get a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*webhook.Webhook, error) {
+	w, err := scanWebhook(r.db(ctx).QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return w, err
+}
+
+// ListByUserID - List a user's personal webhooks
+func (r *WebhookRepository) ListByUserID(ctx context.Context, userID string) ([]*webhook.Webhook, error) {
+	return r.list(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE user_id = $1 AND org_id IS NULL ORDER BY created_at`, userID)
+}
+
+// ListByOrgID - List an organization's webhooks
+func (r *WebhookRepository) ListByOrgID(ctx context.Context, orgID string) ([]*webhook.Webhook, error) {
+	return r.list(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE org_id = $1 ORDER BY created_at`, orgID)
+}
+
+// ListSubscribers - List the enabled webhooks in scope of an audit entry: those
+// of its organization, or for personal vaults those of the vault owner (the
+// actor when the vault no longer exists). Event type filters are left to the caller.
+func (r *WebhookRepository) ListSubscribers(ctx context.Context, orgID *string, actorID string, vaultID *string) ([]*webhook.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + ` FROM webhooks w
+		WHERE w.disabled_at IS NULL AND (
+			w.org_id = $1::text
+			OR (
+				w.org_id IS NULL AND $1::text IS NULL
+				AND w.user_id = COALESCE((SELECT v.user_id FROM vaults v WHERE v.id = $3::uuid), NULLIF($2, '')::uuid)
+			)
+		)
+	`
+	return r.list(ctx, query, orgID, actorID, vaultID)
+}
+
+// Update - Update a webhook's settings and failure state
+func (r *WebhookRepository) Update(ctx context.Context, w *webhook.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $2, description = $3, event_types = $4, consecutive_failures = $5, disabled_at = $6, disabled_reason = $7
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	return r.db(ctx).QueryRow(ctx, query, w.ID, w.URL, w.Description, w.EventTypes, w.ConsecutiveFailures, w.DisabledAt, w.DisabledReason).
+		Scan(&w.UpdatedAt)
+}
+
+// Delete - Delete a webhook and its delivery log
+func (r *WebhookRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db(ctx).Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	return err
+}
+
+// RecordSuccess - Reset a webhook's count of failed attempts in a row
+func (r *WebhookRepository) RecordSuccess(ctx context.Context, id string) error {
+	_, err := r.db(ctx).Exec(ctx, `UPDATE webhooks SET consecutive_failures = 0 WHERE id = $1 AND consecutive_failures > 0`, id)
+	return err
+}
+
+// RecordFailure - Count a failed attempt, disabling the webhook with reason once
+// disableAfter attempts in a row have failed. Reports whether it is now disabled.
+func (r *WebhookRepository) RecordFailure(ctx context.Context, id string, disableAfter int, reason string) (bool, error) {
+	query := `
+		UPDATE webhooks
+		SET consecutive_failures = consecutive_failures + 1,
+			disabled_at = CASE WHEN disabled_at IS NULL AND consecutive_failures + 1 >= $2 THEN NOW() ELSE disabled_at END,
+			disabled_reason = CASE WHEN disabled_at IS NULL AND consecutive_failures + 1 >= $2 THEN $3 ELSE disabled_reason END
+		WHERE id = $1
+		RETURNING disabled_at IS NOT NULL
+	`
+	var disabled bool
+	err := r.db(ctx).QueryRow(ctx, query, id, disableAfter, reason).Scan(&disabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, nil
+	}
+	return disabled, err
+}
+
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, redelivery_of, status, attempts, response_code, response_body, error, duration_ms, last_attempt_at, delivered_at, created_at`
+
+func scanDelivery(row pgx.Row) (*webhook.Delivery, error) {
+	var d webhook.Delivery
+	err := row.Scan(
+		&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.RedeliveryOf, &d.Status, &d.Attempts,
+		&d.ResponseCode, &d.ResponseBody, &d.Error, &d.DurationMs, &d.LastAttemptAt, &d.DeliveredAt, &d.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// CreateDelivery - Record an event for a webhook. An event already recorded for
+// the webhook is returned as it is, so fanning an event out again is harmless.
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, redelivery_of)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (webhook_id, event_id) WHERE redelivery_of IS NULL
+		DO UPDATE SET event_type = webhook_deliveries.event_type
+		RETURNING id, status, attempts, created_at
+	`
+	return r.db(ctx).QueryRow(ctx, query, d.WebhookID, d.EventID, d.EventType, d.Payload, d.RedeliveryOf).
+		Scan(&d.ID, &d.Status, &d.Attempts, &d.CreatedAt)
+}
+
+// GetDelivery - Get a delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*webhook.Delivery, error) {
+	d, err := scanDelivery(r.db(ctx).QueryRow(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return d, err
+}
+
+// ListDeliveries - List a webhook's deliveries, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID string, limit int) ([]*webhook.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC LIMIT $2`
+	rows, err := r.db(ctx).Query(ctx, query, webhookID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var deliveries []*webhook.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt - Record the outcome of a delivery attempt and the resulting status
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, d *webhook.Delivery, status webhook.DeliveryStatus, a *webhook.Attempt) error {
+	var code *int
+	var body, errMsg *string
+	if a.ResponseCode != 0 {
+		code = &a.ResponseCode
+	}
+	if a.ResponseBody != "" {
+		body = &a.ResponseBody
+	}
+	if a.Err != nil {
+		msg := a.Err.Error()
+		errMsg = &msg
+	}
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, response_code = $3, response_body = $4, error = $5,
+			duration_ms = $6, last_attempt_at = NOW(),
+			delivered_at = CASE WHEN $2 = 'succeeded' THEN NOW() ELSE delivered_at END
+		WHERE id = $1
+		RETURNING status, attempts, response_code, response_body, error, duration_ms, last_attempt_at, delivered_at
+	`
+	return r.db(ctx).QueryRow(ctx, query, d.ID, status, code, body, errMsg, int(a.Duration.Milliseconds())).
+		Scan(&d.Status, &d.Attempts, &d.ResponseCode, &d.ResponseBody, &d.Error, &d.DurationMs, &d.LastAttemptAt, &d.DeliveredAt)
+}
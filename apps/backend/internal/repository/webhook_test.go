@@ -0,0 +1,78 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/model/webhook"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: Events reach the webhooks of the vault's owner or organization, once per event
+func TestWebhookRepository_Subscribers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, cleanup := tt.SetupTestDB(t)
+	defer cleanup()
+
+	srv := &server.Server{DB: &database.Database{Pool: testDB.Pool}}
+	repos := repository.NewRepositories(srv)
+	ctx := repository.Unscoped(context.Background())
+
+	aliceID, err := repos.User.Resolve(ctx, "user_alice")
+	require.NoError(t, err)
+	bobID, err := repos.User.Resolve(ctx, "user_bob")
+	require.NoError(t, err)
+	orgID := "org_acme"
+
+	personal := &vault.Vault{UserID: aliceID, Name: "Personal"}
+	require.NoError(t, repos.Vault.Create(ctx, personal))
+	personalID := personal.ID.String()
+
+	aliceHook := &webhook.Webhook{UserID: aliceID, URL: "https://alice.example.com/hook", Secret: "whsec_a"}
+	bobHook := &webhook.Webhook{UserID: bobID, URL: "https://bob.example.com/hook", Secret: "whsec_b"}
+	orgHook := &webhook.Webhook{UserID: aliceID, OrgID: &orgID, URL: "https://siem.example.com/hook", Secret: "whsec_o", EventTypes: []string{"secret.*"}}
+	for _, w := range []*webhook.Webhook{aliceHook, bobHook, orgHook} {
+		require.NoError(t, repos.Webhook.Create(ctx, w))
+	}
+
+	// Bob acting in Alice's shared personal vault notifies Alice
+	subs, err := repos.Webhook.ListSubscribers(ctx, nil, bobID, &personalID)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, aliceHook.ID, subs[0].ID)
+	assert.Equal(t, []string{}, subs[0].EventTypes)
+
+	subs, err = repos.Webhook.ListSubscribers(ctx, &orgID, bobID, nil)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, []string{"secret.*"}, subs[0].EventTypes)
+
+	// Fanning the same event out twice records one delivery
+	eventID := uuid.NewString()
+	first := &webhook.Delivery{WebhookID: aliceHook.ID.String(), EventID: eventID, EventType: "vault.update", Payload: []byte(`{"type":"vault.update"}`)}
+	require.NoError(t, repos.Webhook.CreateDelivery(ctx, first))
+	again := &webhook.Delivery{WebhookID: aliceHook.ID.String(), EventID: eventID, EventType: "vault.update", Payload: []byte(`{"type":"vault.update"}`)}
+	require.NoError(t, repos.Webhook.CreateDelivery(ctx, again))
+	assert.Equal(t, first.ID, again.ID)
+	assert.Equal(t, webhook.DeliveryStatusPending, again.Status)
+
+	// Repeated failures disable the webhook, which then receives nothing
+	for i := 1; i <= 3; i++ {
+		disabled, err := repos.Webhook.RecordFailure(ctx, aliceHook.ID.String(), 3, "HTTP 500")
+		require.NoError(t, err)
+		assert.Equal(t, i == 3, disabled)
+	}
+	subs, err = repos.Webhook.ListSubscribers(ctx, nil, aliceID, &personalID)
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+}
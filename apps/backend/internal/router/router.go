@@ -1,16 +1,15 @@
 package router
 
 import (
-	"net/http"
+	"fmt"
 
+	"github.com/Sameer16536/psvault/internal/config"
 	"github.com/Sameer16536/psvault/internal/handler"
 	"github.com/Sameer16536/psvault/internal/middleware"
 	"github.com/Sameer16536/psvault/internal/server"
 	"github.com/Sameer16536/psvault/internal/service"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
-	echoMiddleware "github.com/labstack/echo/v4/middleware"
-	"golang.org/x/time/rate"
 )
 
 // CustomValidator wraps the validator
@@ -26,37 +25,27 @@ func (cv *CustomValidator) Validate(i interface{}) error {
 	return nil
 }
 
-func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services) *echo.Echo {
-	middlewares := middleware.NewMiddlewares(s)
+func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services) (*echo.Echo, error) {
+	middlewares := middleware.NewMiddlewares(s, services)
+
+	limits := s.Config.RateLimit
+	if limits == nil {
+		limits = config.DefaultRateLimitConfig()
+	}
 
 	router := echo.New()
 
-	// Register validator
-	router.Validator = &CustomValidator{validator: validator.New()}
+	// Register validator; secret types are checked against the registry
+	validate := validator.New()
+	if err := validate.RegisterValidation("secret_type", services.SecretType.ValidateTag); err != nil {
+		return nil, fmt.Errorf("failed to register secret_type validation: %w", err)
+	}
+	router.Validator = &CustomValidator{validator: validate}
 
 	router.HTTPErrorHandler = middlewares.Global.GlobalErrorHandler
 
 	// global middlewares
 	router.Use(
-		echoMiddleware.RateLimiterWithConfig(echoMiddleware.RateLimiterConfig{
-			Store: echoMiddleware.NewRateLimiterMemoryStore(rate.Limit(20)),
-			DenyHandler: func(c echo.Context, identifier string, err error) error {
-				// Record rate limit hit metrics
-				if rateLimitMiddleware := middlewares.RateLimit; rateLimitMiddleware != nil {
-					rateLimitMiddleware.RecordRateLimitHit(c.Path())
-				}
-
-				s.Logger.Warn().
-					Str("request_id", middleware.GetRequestID(c)).
-					Str("identifier", identifier).
-					Str("path", c.Path()).
-					Str("method", c.Request().Method).
-					Str("ip", c.RealIP()).
-					Msg("rate limit exceeded")
-
-				return echo.NewHTTPError(http.StatusTooManyRequests, "Rate limit exceeded")
-			},
-		}),
 		middlewares.Global.CORS(),
 		middlewares.Global.Secure(),
 		middleware.RequestID(),
@@ -65,6 +54,7 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 		middlewares.ContextEnhancer.EnhanceContext(),
 		middlewares.Global.RequestLogger(),
 		middlewares.Global.Recover(),
+		middlewares.RateLimit.Limit("global", limits.Global),
 	)
 
 	// register system routes
@@ -73,32 +63,125 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *service.Services
 	// register versioned routes
 	api := router.Group("/api")
 
+	// Authenticated groups are limited per user
+	apiLimit := middlewares.RateLimit.Limit("api", limits.API)
+
+	// Step-up authentication for sensitive operations
+	stepUp := s.Config.StepUp
+	if stepUp == nil {
+		stepUp = config.DefaultStepUpConfig()
+	}
+	sensitive := middlewares.Auth.RequireRecentAuth(stepUp.SensitiveMaxAge)
+	bulkReveal := middlewares.Auth.RequireRecentAuth(stepUp.BulkRevealMaxAge)
+
 	// Vault routes
 	vaults := api.Group("/vaults")
-	vaults.Use(middlewares.Auth.RequireAuth)
+	vaults.Use(middlewares.Auth.RequireAuth, apiLimit)
 	vaults.POST("", h.Vault.Create)
 	vaults.GET("", h.Vault.List)
 	vaults.GET("/:id", h.Vault.GetByID)
 	vaults.PUT("/:id", h.Vault.Update)
-	vaults.DELETE("/:id", h.Vault.Delete)
+	vaults.DELETE("/:id", h.Vault.Delete, sensitive)
 	// Vault-specific secrets
-	vaults.GET("/:vaultId/secrets", h.Secret.List)
+	vaults.GET("/:vaultId/secrets", h.Secret.List, bulkReveal)
+	// Vault membership
+	vaults.POST("/:id/members", h.Vault.AddMember, sensitive)
+	vaults.GET("/:id/members", h.Vault.ListMembers)
+	vaults.DELETE("/:id/members/:memberId", h.Vault.RemoveMember, sensitive)
+	// Vault folders
+	vaults.POST("/:id/folders", h.Folder.Create)
+	vaults.GET("/:id/folders", h.Folder.Tree)
+	// Password health report
+	vaults.GET("/:id/health", h.PasswordHealth.Report)
 
 	// Secret routes
 	secrets := api.Group("/secrets")
-	secrets.Use(middlewares.Auth.RequireAuth)
+	secrets.Use(middlewares.Auth.RequireAuth, apiLimit)
 	secrets.POST("", h.Secret.Create)
-	secrets.GET("/search", h.Secret.Search)
-	secrets.GET("/:id", h.Secret.GetByID)
+	secrets.POST("/batch", h.Secret.Batch, sensitive)
+	secrets.GET("/search", h.Secret.Search, middlewares.RateLimit.Limit("search", limits.Search), bulkReveal)
+	secrets.GET("/expiring", h.Secret.ListExpiring)
+	secrets.GET("/:id", h.Secret.GetByID, middlewares.RateLimit.Limit("secret_reveal", limits.SecretReveal))
 	secrets.PUT("/:id", h.Secret.Update)
 	secrets.DELETE("/:id", h.Secret.Delete)
+	secrets.PUT("/:id/folder", h.Folder.MoveSecret)
+	secrets.POST("/:id/transfer", h.Secret.Transfer, sensitive)
+	secrets.PUT("/:id/health", h.PasswordHealth.Set)
+	// Secret attachments
+	secrets.POST("/:id/attachments", h.Attachment.Upload)
+	secrets.GET("/:id/attachments", h.Attachment.List)
+	secrets.GET("/:id/attachments/:attachmentId", h.Attachment.Download, middlewares.RateLimit.Limit("secret_reveal", limits.SecretReveal))
+	secrets.DELETE("/:id/attachments/:attachmentId", h.Attachment.Delete)
+
+	// Attachment storage usage
+	attachments := api.Group("/attachments")
+	attachments.Use(middlewares.Auth.RequireAuth, apiLimit)
+	attachments.GET("/usage", h.Attachment.Usage)
+
+	// Folder routes
+	folders := api.Group("/folders")
+	folders.Use(middlewares.Auth.RequireAuth, apiLimit)
+	folders.PATCH("/:id", h.Folder.Rename)
+	folders.POST("/:id/move", h.Folder.Move)
+	folders.DELETE("/:id", h.Folder.Delete)
+
+	// Secret type registry
+	secretTypes := api.Group("/secret-types")
+	secretTypes.Use(middlewares.Auth.RequireAuth, apiLimit)
+	secretTypes.GET("", h.SecretType.List)
+
+	// Breached password range lookups
+	breach := api.Group("/breach")
+	breach.Use(middlewares.Auth.RequireAuth, apiLimit)
+	breach.GET("/range/:prefix", h.Breach.Range)
 
 	// Device routes
 	devices := api.Group("/devices")
-	devices.Use(middlewares.Auth.RequireAuth)
+	devices.Use(middlewares.Auth.RequireAuth, apiLimit)
 	devices.POST("", h.Device.Register)
 	devices.GET("", h.Device.List)
 	devices.DELETE("/:id", h.Device.Delete)
 
-	return router
+	// Service account routes
+	serviceAccounts := api.Group("/service-accounts")
+	serviceAccounts.Use(middlewares.Auth.RequireAuth, apiLimit)
+	serviceAccounts.POST("", h.ServiceAccount.Create, sensitive)
+	serviceAccounts.GET("", h.ServiceAccount.List)
+	serviceAccounts.DELETE("/:id", h.ServiceAccount.Disable, sensitive)
+
+	// Webhook routes; a new endpoint or filter changes where vault events are sent
+	webhooks := api.Group("/webhooks")
+	webhooks.Use(middlewares.Auth.RequireAuth, apiLimit)
+	webhooks.POST("", h.Webhook.Create, sensitive)
+	webhooks.GET("", h.Webhook.List)
+	webhooks.GET("/:id", h.Webhook.GetByID)
+	webhooks.PATCH("/:id", h.Webhook.Update, sensitive)
+	webhooks.DELETE("/:id", h.Webhook.Delete)
+	webhooks.GET("/:id/deliveries", h.Webhook.ListDeliveries)
+	webhooks.POST("/:id/deliveries/:deliveryId/redeliver", h.Webhook.Redeliver)
+
+	// Audit log routes
+	auditLogs := api.Group("/audit-logs")
+	auditLogs.Use(middlewares.Auth.RequireAuth, apiLimit)
+	auditLogs.GET("", h.Audit.List)
+	auditLogs.GET("/export", h.Audit.Export, sensitive)
+
+	// Organization routes (act on the session's active organization)
+	orgs := api.Group("/orgs")
+	orgs.Use(middlewares.Auth.RequireAuth, apiLimit)
+	orgs.GET("/audit-logs", h.Audit.ListOrg)
+	orgs.GET("/audit-logs/export", h.Audit.ExportOrg, sensitive)
+
+	// Share link routes; opening a link is unauthenticated and limited per IP
+	api.GET("/shares/:id", h.Share.Consume, middlewares.RateLimit.Limit("share_view", limits.ShareView))
+	shares := api.Group("/shares")
+	shares.Use(middlewares.Auth.RequireAuth, apiLimit)
+	shares.POST("", h.Share.Create)
+	shares.GET("", h.Share.List)
+	shares.DELETE("/:id", h.Share.Revoke)
+
+	// Client-credentials exchange for service accounts (unauthenticated)
+	api.POST("/auth/token", h.ServiceAccount.Token, middlewares.RateLimit.Limit("auth", limits.Auth))
+
+	return router, nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/Sameer16536/psvault/internal/config"
 	"github.com/Sameer16536/psvault/internal/database"
 	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/lib/storage"
 	loggerPkg "github.com/Sameer16536/psvault/internal/logger"
 	"github.com/newrelic/go-agent/v3/integrations/nrredis-v9"
 	"github.com/redis/go-redis/v9"
@@ -24,6 +25,7 @@ type Server struct {
 	Redis         *redis.Client
 	httpServer    *http.Server
 	Job           *job.JobService
+	Storage       storage.Storage
 }
 
 func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.LoggerService) (*Server, error) {
@@ -51,13 +53,19 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		// Don't fail startup if Redis is unavailable
 	}
 
-	// job service
+	// job service; the workers are started by StartJobs so admin commands
+	// can enqueue and inspect tasks without processing them
 	jobService := job.NewJobService(logger, cfg)
 	jobService.InitHandlers(cfg, logger)
 
-	// Start job server
-	if err := jobService.Start(); err != nil {
-		return nil, err
+	// Blob storage for secret attachments
+	storageCfg := cfg.Storage
+	if storageCfg == nil {
+		storageCfg = config.DefaultStorageConfig()
+	}
+	blobStorage, err := storage.New(context.Background(), storageCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize attachment storage: %w", err)
 	}
 
 	server := &Server{
@@ -67,6 +75,7 @@ func New(cfg *config.Config, logger *zerolog.Logger, loggerService *loggerPkg.Lo
 		DB:            db,
 		Redis:         redisClient,
 		Job:           jobService,
+		Storage:       blobStorage,
 	}
 
 	// Start metrics collection
@@ -98,9 +107,16 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
+// StartJobs starts the background job workers and the periodic task scheduler
+func (s *Server) StartJobs() error {
+	return s.Job.Start()
+}
+
 func (s *Server) Shutdown(ctx context.Context) error {
-	if err := s.httpServer.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to shutdown HTTP server: %w", err)
+	if s.httpServer != nil {
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shutdown HTTP server: %w", err)
+		}
 	}
 
 	if err := s.DB.Close(); err != nil {
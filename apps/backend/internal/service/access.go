@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/outbox"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/hibiken/asynq"
+)
+
+// vaultAccess is the level of access a principal holds on a vault
+type vaultAccess int
+
+const (
+	vaultAccessNone vaultAccess = iota
+	vaultAccessRead
+	vaultAccessWrite
+	vaultAccessOwner
+)
+
+// memberTypeFromContext returns the vault_members principal type of the caller in ctx
+func memberTypeFromContext(ctx context.Context) audit.ActorType {
+	if actor.IsServiceAccount(ctx) {
+		return audit.ActorTypeServiceAccount
+	}
+	return audit.ActorTypeUser
+}
+
+// orgRoleMember is the Clerk role key for regular organization members
+const orgRoleMember = "org:member"
+
+// orgVaultAccess maps the caller's role in a vault's organization to an access level.
+// Admins manage org vaults, members read and write secrets, any other role is read-only.
+func orgVaultAccess(a *actor.Actor) vaultAccess {
+	switch a.OrgRole {
+	case actor.OrgRoleAdmin:
+		return vaultAccessOwner
+	case orgRoleMember:
+		return vaultAccessWrite
+	default:
+		return vaultAccessRead
+	}
+}
+
+// resolveVaultAccess works out what the principal in ctx may do with v.
+// Personal vaults grant their owner full access and org vaults grant access by
+// org role while that organization is active; everyone else needs a vault_members grant.
+func resolveVaultAccess(ctx context.Context, repos *repository.Repositories, v *vault.Vault, userID string) (vaultAccess, error) {
+	memberType := memberTypeFromContext(ctx)
+	if v.OrgID != nil {
+		if a, ok := actor.FromContext(ctx); ok && a.Type == actor.TypeUser && a.OrgID == *v.OrgID {
+			return orgVaultAccess(a), nil
+		}
+	} else if memberType == audit.ActorTypeUser && v.UserID == userID {
+		return vaultAccessOwner, nil
+	}
+	m, err := repos.VaultMember.Get(ctx, v.ID.String(), memberType, userID)
+	if err != nil {
+		return vaultAccessNone, fmt.Errorf("failed to get vault membership: %w", err)
+	}
+	if m == nil {
+		return vaultAccessNone, nil
+	}
+	if m.Role == vault.MemberRoleWrite {
+		return vaultAccessWrite, nil
+	}
+	return vaultAccessRead, nil
+}
+
+// loadVaultAccess loads a vault and the access the principal in ctx holds on it.
+// The vault is nil if it does not exist or row-level security hides it.
+func loadVaultAccess(ctx context.Context, repos *repository.Repositories, userID, vaultID string) (*vault.Vault, vaultAccess, error) {
+	v, err := repos.Vault.GetByID(ctx, vaultID)
+	if err != nil {
+		return nil, vaultAccessNone, fmt.Errorf("failed to get vault: %w", err)
+	}
+	if v == nil {
+		return nil, vaultAccessNone, nil
+	}
+	access, err := resolveVaultAccess(ctx, repos, v, userID)
+	if err != nil {
+		return nil, vaultAccessNone, err
+	}
+	return v, access, nil
+}
+
+// checkVaultAccess requires at least the given access. Vaults the principal
+// cannot reach at all are reported as not found, so their existence is not revealed.
+func checkVaultAccess(v *vault.Vault, access, required vaultAccess) error {
+	if v == nil || access == vaultAccessNone {
+		return errs.NewNotFoundError("Vault not found", false, nil)
+	}
+	if access < required {
+		return errs.NewForbiddenError("Insufficient access to this vault", false)
+	}
+	return nil
+}
+
+// authorizeVault loads a vault the principal in ctx holds at least the given access on
+func authorizeVault(ctx context.Context, repos *repository.Repositories, userID, vaultID string, required vaultAccess) (*vault.Vault, error) {
+	v, access, err := loadVaultAccess(ctx, repos, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVaultAccess(v, access, required); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// newAuditLog builds an audit entry attributed to the actor in ctx
+func newAuditLog(ctx context.Context, userID string, vaultID, secretID *string, action audit.Action) *audit.AuditLog {
+	log := &audit.AuditLog{
+		UserID:    userID,
+		ActorType: audit.ActorTypeUser,
+		VaultID:   vaultID,
+		SecretID:  secretID,
+		Action:    action,
+	}
+	if a, ok := actor.FromContext(ctx); ok {
+		log.ActorType = audit.ActorType(a.Type)
+		if a.IPAddress != "" {
+			log.IPAddress = &a.IPAddress
+		}
+		if a.UserAgent != "" {
+			log.UserAgent = &a.UserAgent
+		}
+	}
+	return log
+}
+
+// withAudit runs mutate and records the audit entry it returns as one unit of
+// work, so a change is never committed without its audit record. The entry is
+// also published as an outbox.TopicAuditLogged event and fanned out to
+// webhooks once committed.
+func withAudit(ctx context.Context, repos *repository.Repositories, mutate func(repos *repository.Repositories) (*audit.AuditLog, error)) (*audit.AuditLog, error) {
+	var log *audit.AuditLog
+	err := repos.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		if log, err = mutate(repos); err != nil {
+			return err
+		}
+		return recordAudit(ctx, repos, log)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+// recordAudit writes an audit entry and its outbox messages with repos, for
+// units of work that record more than one entry; see withAudit
+func recordAudit(ctx context.Context, repos *repository.Repositories, log *audit.AuditLog) error {
+	if err := repos.Audit.Log(ctx, log); err != nil {
+		return fmt.Errorf("failed to log audit: %w", err)
+	}
+	event, err := outbox.NewEvent(outbox.TopicAuditLogged, log)
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", err)
+	}
+	if err := repos.Outbox.Enqueue(ctx, event); err != nil {
+		return fmt.Errorf("failed to enqueue audit event: %w", err)
+	}
+	fanout, err := outbox.NewTask(job.TaskWebhookFanout, log)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook fan-out: %w", err)
+	}
+	if err := repos.Outbox.Enqueue(ctx, fanout); err != nil {
+		return fmt.Errorf("failed to enqueue webhook fan-out: %w", err)
+	}
+	return nil
+}
+
+// unscopedTask wraps a job handler to run without row-level security. Jobs act
+// for no single principal, and repositories refuse contexts that carry neither
+// an actor nor repository.Unscoped.
+func unscopedTask(handler func(context.Context, *asynq.Task) error) func(context.Context, *asynq.Task) error {
+	return func(ctx context.Context, t *asynq.Task) error {
+		return handler(repository.Unscoped(ctx), t)
+	}
+}
+
+// activeOrgID returns the organization a user is currently acting in, or "" for
+// personal context and service accounts (which reach org vaults only through membership)
+func activeOrgID(ctx context.Context) string {
+	if a, ok := actor.FromContext(ctx); ok && a.Type == actor.TypeUser {
+		return a.OrgID
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgVaultAccess(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+		want vaultAccess
+	}{
+		{"admin", actor.OrgRoleAdmin, vaultAccessOwner},
+		{"member", orgRoleMember, vaultAccessWrite},
+		{"custom role", "org:viewer", vaultAccessRead},
+		{"no role", "", vaultAccessRead},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, orgVaultAccess(&actor.Actor{Type: actor.TypeUser, OrgID: "org_1", OrgRole: tc.role}))
+		})
+	}
+}
+
+// Test: Owners of personal vaults and users acting in a vault's organization
+// are resolved without a membership lookup
+func TestResolveVaultAccess_OwnerAndOrg(t *testing.T) {
+	personal := &vault.Vault{UserID: "user-1"}
+	orgVault := &vault.Vault{UserID: "user-2", OrgID: tt.Ptr("org_1")}
+
+	tests := []struct {
+		name  string
+		vault *vault.Vault
+		actor *actor.Actor
+		want  vaultAccess
+	}{
+		{"personal vault owner", personal, &actor.Actor{ID: "user-1", Type: actor.TypeUser}, vaultAccessOwner},
+		{"personal vault owner in an org", personal, &actor.Actor{ID: "user-1", Type: actor.TypeUser, OrgID: "org_1", OrgRole: orgRoleMember}, vaultAccessOwner},
+		{"org admin", orgVault, &actor.Actor{ID: "user-1", Type: actor.TypeUser, OrgID: "org_1", OrgRole: actor.OrgRoleAdmin}, vaultAccessOwner},
+		{"org member", orgVault, &actor.Actor{ID: "user-1", Type: actor.TypeUser, OrgID: "org_1", OrgRole: orgRoleMember}, vaultAccessWrite},
+		{"org creator as member", orgVault, &actor.Actor{ID: "user-2", Type: actor.TypeUser, OrgID: "org_1", OrgRole: orgRoleMember}, vaultAccessWrite},
+		{"org viewer", orgVault, &actor.Actor{ID: "user-1", Type: actor.TypeUser, OrgID: "org_1", OrgRole: "org:viewer"}, vaultAccessRead},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := actor.WithActor(context.Background(), tc.actor)
+			access, err := resolveVaultAccess(ctx, nil, tc.vault, tc.actor.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, access)
+		})
+	}
+}
+
+// Test: Org vaults outside the active organization, other users' personal vaults
+// and service accounts are resolved through vault_members only
+func TestResolveVaultAccess_Membership(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	ctx := repository.Unscoped(context.Background())
+
+	ownerID, _ := createTestActor(t, ctx, repos, "user_owner")
+	userID, _ := createTestActor(t, ctx, repos, "user_alice")
+	personal := &vault.Vault{UserID: ownerID, Name: "Personal", EncryptedKey: []byte("key")}
+	require.NoError(t, repos.Vault.Create(ctx, personal))
+	orgVault := &vault.Vault{UserID: ownerID, OrgID: tt.Ptr("org_1"), Name: "Team", EncryptedKey: []byte("key")}
+	require.NoError(t, repos.Vault.Create(ctx, orgVault))
+	userOwned := &vault.Vault{UserID: userID, Name: "Alice", EncryptedKey: []byte("key")}
+	require.NoError(t, repos.Vault.Create(ctx, userOwned))
+
+	user := &actor.Actor{ID: userID, ExternalID: "user_alice", Type: actor.TypeUser}
+	otherOrg := &actor.Actor{ID: userID, ExternalID: "user_alice", Type: actor.TypeUser, OrgID: "org_2", OrgRole: actor.OrgRoleAdmin}
+	// A service account whose ID happens to equal the user's is a different principal
+	account := &actor.Actor{ID: userID, Type: actor.TypeServiceAccount, OrgID: "org_1"}
+
+	check := func(t *testing.T, a *actor.Actor, v *vault.Vault, want vaultAccess) {
+		t.Helper()
+		access, err := resolveVaultAccess(actor.WithActor(ctx, a), repos, v, a.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, access)
+	}
+
+	tests := []struct {
+		name  string
+		actor *actor.Actor
+		vault *vault.Vault
+	}{
+		{"personal context on org vault", user, orgVault},
+		{"inactive org on org vault", otherOrg, orgVault},
+		{"other user's personal vault", user, personal},
+		{"service account on org vault", account, orgVault},
+		{"service account on user's vault", account, userOwned},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			check(t, tc.actor, tc.vault, vaultAccessNone)
+		})
+	}
+
+	grant := func(v *vault.Vault, memberType audit.ActorType, role vault.MemberRole) {
+		require.NoError(t, repos.VaultMember.Upsert(ctx, &vault.Member{
+			VaultID: v.ID.String(), MemberType: memberType, MemberID: userID, Role: role, GrantedBy: ownerID,
+		}))
+	}
+	grant(orgVault, audit.ActorTypeUser, vault.MemberRoleRead)
+	grant(personal, audit.ActorTypeUser, vault.MemberRoleWrite)
+	grant(orgVault, audit.ActorTypeServiceAccount, vault.MemberRoleWrite)
+
+	t.Run("granted", func(t *testing.T) {
+		check(t, user, orgVault, vaultAccessRead)
+		check(t, otherOrg, orgVault, vaultAccessRead)
+		check(t, user, personal, vaultAccessWrite)
+		check(t, account, orgVault, vaultAccessWrite)
+		check(t, account, personal, vaultAccessNone)
+		check(t, account, userOwned, vaultAccessNone)
+	})
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/redis/go-redis/v9"
+)
+
+// anomalyTimeout bounds the Redis work done on the request path
+const anomalyTimeout = 250 * time.Millisecond
+
+// anomalyRule identifies which detector fired
+type anomalyRule string
+
+const (
+	anomalyRuleRevealVolume anomalyRule = "reveal_volume"
+	anomalyRuleNewClient    anomalyRule = "new_client"
+	anomalyRuleUnusualHour  anomalyRule = "unusual_hour"
+)
+
+var anomalyRuleDescriptions = map[anomalyRule]string{
+	anomalyRuleRevealVolume: "an unusually high number of secrets were revealed",
+	anomalyRuleNewClient:    "your vault was accessed from a new device or network",
+	anomalyRuleUnusualHour:  "your vault was accessed at an unusual time",
+}
+
+// AnomalyService watches audit events for suspicious secret access and
+// applies the configured response. State lives in Redis under anomaly:*.
+type AnomalyService struct {
+	server *server.Server
+	// now is the detectors' clock, replaced in tests
+	now func() time.Time
+}
+
+func NewAnomalyService(s *server.Server) *AnomalyService {
+	return &AnomalyService{server: s, now: time.Now}
+}
+
+func (s *AnomalyService) enabled() bool {
+	cfg := s.server.Config.Anomaly
+	return s.server.Redis != nil && cfg != nil && !cfg.Disabled
+}
+
+// Observe - Feed an audit event to the detectors. Failures are logged and never block the request.
+func (s *AnomalyService) Observe(ctx context.Context, log *audit.AuditLog) {
+	if !s.enabled() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), anomalyTimeout)
+	defer cancel()
+
+	now := s.now().UTC()
+	var fired []anomalyRule
+
+	if log.Action == audit.ActionView && log.SecretID != nil {
+		if ok, err := s.checkRevealVolume(ctx, log.UserID, *log.SecretID, now); err != nil {
+			s.logDetectorError(err, log.UserID, anomalyRuleRevealVolume)
+		} else if ok {
+			fired = append(fired, anomalyRuleRevealVolume)
+		}
+	}
+	if log.IPAddress != nil || log.UserAgent != nil {
+		if ok, err := s.checkNewClient(ctx, log.UserID, deref(log.IPAddress), deref(log.UserAgent)); err != nil {
+			s.logDetectorError(err, log.UserID, anomalyRuleNewClient)
+		} else if ok {
+			fired = append(fired, anomalyRuleNewClient)
+		}
+	}
+	if ok, err := s.checkUnusualHour(ctx, log.UserID, now); err != nil {
+		s.logDetectorError(err, log.UserID, anomalyRuleUnusualHour)
+	} else if ok {
+		fired = append(fired, anomalyRuleUnusualHour)
+	}
+
+	for _, rule := range fired {
+		s.respond(ctx, log, rule, now)
+	}
+}
+
+// LockedUntil - Report whether a principal is locked out and until when
+func (s *AnomalyService) LockedUntil(ctx context.Context, principalID string) (time.Time, bool) {
+	if !s.enabled() {
+		return time.Time{}, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, anomalyTimeout)
+	defer cancel()
+	ttl, err := s.server.Redis.PTTL(ctx, anomalyKey("lock", principalID)).Result()
+	if err != nil || ttl <= 0 {
+		return time.Time{}, false
+	}
+	return s.now().Add(ttl), true
+}
+
+// ReauthRequiredSince - Report whether a principal must re-authenticate, and since when
+func (s *AnomalyService) ReauthRequiredSince(ctx context.Context, principalID string) (time.Time, bool) {
+	if !s.enabled() {
+		return time.Time{}, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, anomalyTimeout)
+	defer cancel()
+	v, err := s.server.Redis.Get(ctx, anomalyKey("reauth", principalID)).Int64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(v, 0), true
+}
+
+// ClearReauth - Drop the re-authentication requirement once the principal has verified again
+func (s *AnomalyService) ClearReauth(ctx context.Context, principalID string) {
+	if !s.enabled() {
+		return
+	}
+	_ = s.server.Redis.Del(ctx, anomalyKey("reauth", principalID)).Err()
+}
+
+// checkRevealVolume records a reveal in a sliding window and reports whether it exceeds the threshold
+func (s *AnomalyService) checkRevealVolume(ctx context.Context, principalID, secretID string, now time.Time) (bool, error) {
+	cfg := s.server.Config.Anomaly
+	key := anomalyKey("reveals", principalID)
+	member := strconv.FormatInt(now.UnixNano(), 10) + ":" + secretID
+
+	pipe := s.server.Redis.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-cfg.RevealWindow).UnixMilli(), 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: member})
+	count := pipe.ZCard(ctx, key)
+	pipe.PExpire(ctx, key, cfg.RevealWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return count.Val() > int64(cfg.RevealThreshold), nil
+}
+
+// checkNewClient remembers IP/user-agent combinations and reports an unseen one.
+// The very first client of a principal is learned silently.
+func (s *AnomalyService) checkNewClient(ctx context.Context, principalID, ipAddress, userAgent string) (bool, error) {
+	cfg := s.server.Config.Anomaly
+	key := anomalyKey("clients", principalID)
+	sum := sha256.Sum256([]byte(ipAddress + "\x00" + userAgent))
+
+	pipe := s.server.Redis.TxPipeline()
+	known := pipe.SCard(ctx, key)
+	added := pipe.SAdd(ctx, key, hex.EncodeToString(sum[:16]))
+	pipe.Expire(ctx, key, cfg.HistoryRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+	return added.Val() == 1 && known.Val() > 0, nil
+}
+
+// checkUnusualHour keeps a per-principal histogram of activity by UTC hour and
+// reports activity in an hour that is rare in the principal's history
+func (s *AnomalyService) checkUnusualHour(ctx context.Context, principalID string, now time.Time) (bool, error) {
+	cfg := s.server.Config.Anomaly
+	key := anomalyKey("hours", principalID)
+	hour := strconv.Itoa(now.Hour())
+
+	pipe := s.server.Redis.TxPipeline()
+	histogram := pipe.HGetAll(ctx, key)
+	pipe.HIncrBy(ctx, key, hour, 1)
+	pipe.Expire(ctx, key, cfg.HistoryRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return false, err
+	}
+
+	var total, inHour int64
+	for h, v := range histogram.Val() {
+		n, _ := strconv.ParseInt(v, 10, 64)
+		total += n
+		if h == hour {
+			inHour = n
+		}
+	}
+	if total < int64(cfg.HourMinSamples) {
+		return false, nil
+	}
+	return float64(inHour)/float64(total) < cfg.HourMinShare, nil
+}
+
+// respond applies the configured response for rule, at most once per cooldown
+func (s *AnomalyService) respond(ctx context.Context, log *audit.AuditLog, rule anomalyRule, now time.Time) {
+	cfg := s.server.Config.Anomaly
+	response := cfg.RevealResponse
+	switch rule {
+	case anomalyRuleNewClient:
+		response = cfg.NewClientResponse
+	case anomalyRuleUnusualHour:
+		response = cfg.HourResponse
+	}
+
+	first, err := s.server.Redis.SetNX(ctx, anomalyKey("alerted", log.UserID, string(rule)), now.Unix(), cfg.AlertCooldown).Result()
+	if err != nil || !first {
+		return
+	}
+
+	s.server.Logger.Warn().
+		Str("user_id", log.UserID).
+		Str("actor_type", string(log.ActorType)).
+		Str("rule", string(rule)).
+		Str("response", string(response)).
+		Str("ip", deref(log.IPAddress)).
+		Str("user_agent", deref(log.UserAgent)).
+		Msg("secret access anomaly detected")
+	if s.server.LoggerService != nil && s.server.LoggerService.GetApplication() != nil {
+		s.server.LoggerService.GetApplication().RecordCustomEvent("AccessAnomaly", map[string]interface{}{
+			"rule":       string(rule),
+			"response":   string(response),
+			"actor_type": string(log.ActorType),
+		})
+	}
+
+	var outcome string
+	switch response {
+	case config.AnomalyResponseLog:
+		return
+	case config.AnomalyResponseNotify:
+		outcome = "No further action was taken."
+	case config.AnomalyResponseReauth:
+		if err := s.server.Redis.Set(ctx, anomalyKey("reauth", log.UserID), now.Unix(), cfg.HistoryRetention).Err(); err != nil {
+			s.logDetectorError(err, log.UserID, rule)
+		}
+		outcome = "You will be asked to sign in again before you can continue."
+	case config.AnomalyResponseLock:
+		if err := s.server.Redis.Set(ctx, anomalyKey("lock", log.UserID), now.Unix(), cfg.LockDuration).Err(); err != nil {
+			s.logDetectorError(err, log.UserID, rule)
+		}
+		outcome = fmt.Sprintf("Access has been locked for %s.", cfg.LockDuration)
+	}
+
+	// Every response above log-only also notifies the user; service accounts have no inbox
+	if log.ActorType == audit.ActorTypeServiceAccount {
+		return
+	}
+	s.notify(ctx, log, rule, outcome, now)
+}
+
+func (s *AnomalyService) notify(ctx context.Context, log *audit.AuditLog, rule anomalyRule, outcome string, now time.Time) {
+	if s.server.Job == nil || s.server.Job.Client == nil {
+		return
+	}
+	// Alerts are addressed to the Clerk user, who holds the email address
+	a, ok := actor.FromContext(ctx)
+	if !ok || a.ExternalID == "" {
+		return
+	}
+	task, err := job.NewSecurityAlertEmailTask(job.SecurityAlertEmailPayload{
+		UserID:     a.ExternalID,
+		Reason:     anomalyRuleDescriptions[rule],
+		IPAddress:  deref(log.IPAddress),
+		UserAgent:  deref(log.UserAgent),
+		DetectedAt: now.Format("2006-01-02 15:04 UTC"),
+		Response:   outcome,
+	})
+	if err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to create security alert task")
+		return
+	}
+	if _, err := s.server.Job.Client.EnqueueContext(ctx, task); err != nil {
+		s.server.Logger.Error().Err(err).Str("user_id", log.UserID).Msg("failed to enqueue security alert")
+	}
+}
+
+func (s *AnomalyService) logDetectorError(err error, principalID string, rule anomalyRule) {
+	s.server.Logger.Warn().Err(err).Str("user_id", principalID).Str("rule", string(rule)).Msg("anomaly detector unavailable")
+}
+
+func anomalyKey(parts ...string) string {
+	key := "anomaly"
+	for _, p := range parts {
+		key += ":" + p
+	}
+	return key
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
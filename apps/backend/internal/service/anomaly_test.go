@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// anomalyClock drives both the detectors' clock and key expiry in miniredis
+type anomalyClock struct {
+	now time.Time
+	mr  *miniredis.Miniredis
+}
+
+func (c *anomalyClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.mr.FastForward(d)
+}
+
+func (c *anomalyClock) set(hour int) {
+	next := time.Date(c.now.Year(), c.now.Month(), c.now.Day(), hour, 0, 0, 0, time.UTC)
+	if !next.After(c.now) {
+		next = next.Add(24 * time.Hour)
+	}
+	c.advance(next.Sub(c.now))
+}
+
+func newTestAnomalyService(t *testing.T, cfg *config.AnomalyConfig) (*AnomalyService, *anomalyClock) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	logger := zerolog.Nop()
+	srv := &server.Server{Config: &config.Config{Anomaly: cfg}, Logger: &logger, Redis: client}
+
+	clock := &anomalyClock{now: time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC), mr: mr}
+	svc := NewAnomalyService(srv)
+	svc.now = func() time.Time { return clock.now }
+	return svc, clock
+}
+
+func revealLog(userID string) *audit.AuditLog {
+	return &audit.AuditLog{UserID: userID, ActorType: audit.ActorTypeUser, Action: audit.ActionView, SecretID: tt.Ptr("secret-1")}
+}
+
+// Test: Reveals are counted in a sliding window; crossing the threshold locks the
+// principal once per cooldown
+func TestAnomalyService_RevealVolume(t *testing.T) {
+	cfg := config.DefaultAnomalyConfig()
+	cfg.RevealThreshold = 3
+	cfg.RevealWindow = time.Minute
+	cfg.RevealResponse = config.AnomalyResponseLock
+	svc, clock := newTestAnomalyService(t, cfg)
+	ctx := context.Background()
+
+	for range 3 {
+		svc.Observe(ctx, revealLog("user-1"))
+		clock.advance(10 * time.Second)
+	}
+	_, locked := svc.LockedUntil(ctx, "user-1")
+	assert.False(t, locked)
+
+	// The first reveal has left the window
+	clock.advance(35 * time.Second)
+	svc.Observe(ctx, revealLog("user-1"))
+	clock.advance(time.Second)
+	_, locked = svc.LockedUntil(ctx, "user-1")
+	assert.False(t, locked)
+
+	// Other principals are counted on their own
+	svc.Observe(ctx, revealLog("user-2"))
+	_, locked = svc.LockedUntil(ctx, "user-2")
+	assert.False(t, locked)
+
+	svc.Observe(ctx, revealLog("user-1"))
+	until, locked := svc.LockedUntil(ctx, "user-1")
+	require.True(t, locked)
+	assert.WithinDuration(t, clock.now.Add(cfg.LockDuration), until, time.Second)
+
+	// Alerts are not repeated within the cooldown
+	clock.advance(cfg.LockDuration)
+	for range 5 {
+		svc.Observe(ctx, revealLog("user-1"))
+		clock.advance(time.Second)
+	}
+	_, locked = svc.LockedUntil(ctx, "user-1")
+	assert.False(t, locked)
+
+	clock.advance(cfg.AlertCooldown)
+	for range 4 {
+		svc.Observe(ctx, revealLog("user-1"))
+		clock.advance(time.Second)
+	}
+	_, locked = svc.LockedUntil(ctx, "user-1")
+	assert.True(t, locked)
+}
+
+// Test: The first client is learned silently; a new one alerts the user once per cooldown,
+// and service accounts are never emailed
+func TestAnomalyService_NewClient(t *testing.T) {
+	cfg := config.DefaultAnomalyConfig()
+	cfg.NewClientResponse = config.AnomalyResponseNotify
+	svc, clock := newTestAnomalyService(t, cfg)
+	inspector := tt.SetupTestJobs(t, svc.server)
+
+	observe := func(a *actor.Actor, ip string) {
+		log := &audit.AuditLog{UserID: a.ID, ActorType: audit.ActorType(a.Type), Action: audit.ActionUpdate, IPAddress: &ip, UserAgent: tt.Ptr("curl")}
+		svc.Observe(actor.WithActor(context.Background(), a), log)
+	}
+	alerts := func() []string {
+		t.Helper()
+		tasks, err := inspector.ListPendingTasks("critical")
+		if errors.Is(err, asynq.ErrQueueNotFound) {
+			return nil
+		}
+		require.NoError(t, err)
+		var types []string
+		for _, task := range tasks {
+			types = append(types, task.Type)
+		}
+		return types
+	}
+
+	user := &actor.Actor{ID: "user-1", ExternalID: "user_alice", Type: actor.TypeUser}
+	observe(user, "198.51.100.1")
+	observe(user, "198.51.100.1")
+	assert.Empty(t, alerts())
+
+	observe(user, "198.51.100.2")
+	assert.Equal(t, []string{job.TaskSecurityAlert}, alerts())
+	observe(user, "198.51.100.3")
+	assert.Len(t, alerts(), 1)
+
+	clock.advance(cfg.AlertCooldown)
+	observe(user, "198.51.100.4")
+	assert.Len(t, alerts(), 2)
+
+	account := &actor.Actor{ID: "sa-1", Type: actor.TypeServiceAccount, OrgID: "org_1"}
+	observe(account, "198.51.100.1")
+	observe(account, "198.51.100.2")
+	assert.Len(t, alerts(), 2)
+}
+
+// Test: Activity in an hour that is rare in the principal's history requires
+// re-authentication, once enough history has been seen
+func TestAnomalyService_UnusualHour(t *testing.T) {
+	cfg := config.DefaultAnomalyConfig()
+	cfg.HourMinSamples = 5
+	cfg.HourMinShare = 0.15
+	cfg.HourResponse = config.AnomalyResponseReauth
+	svc, clock := newTestAnomalyService(t, cfg)
+	ctx := context.Background()
+	observe := func() {
+		svc.Observe(ctx, &audit.AuditLog{UserID: "user-1", ActorType: audit.ActorTypeUser, Action: audit.ActionUpdate})
+	}
+
+	// Too little history to judge
+	clock.set(3)
+	observe()
+	for range 4 {
+		clock.set(10)
+		observe()
+	}
+	_, required := svc.ReauthRequiredSince(ctx, "user-1")
+	assert.False(t, required)
+
+	// 10:00 holds most of the history and 03:00 a sixth of it, above the minimum share
+	clock.set(10)
+	observe()
+	clock.set(3)
+	observe()
+	_, required = svc.ReauthRequiredSince(ctx, "user-1")
+	assert.False(t, required)
+
+	clock.set(22)
+	observe()
+	since, required := svc.ReauthRequiredSince(ctx, "user-1")
+	require.True(t, required)
+	assert.Equal(t, clock.now.Unix(), since.Unix())
+
+	svc.ClearReauth(ctx, "user-1")
+	_, required = svc.ReauthRequiredSince(ctx, "user-1")
+	assert.False(t, required)
+}
+
+// Test: A disabled detector records nothing
+func TestAnomalyService_Disabled(t *testing.T) {
+	cfg := config.DefaultAnomalyConfig()
+	cfg.RevealThreshold = 1
+	cfg.Disabled = true
+	svc, clock := newTestAnomalyService(t, cfg)
+
+	for range 3 {
+		svc.Observe(context.Background(), revealLog("user-1"))
+	}
+	_, locked := svc.LockedUntil(context.Background(), "user-1")
+	assert.False(t, locked)
+	assert.Empty(t, clock.mr.Keys())
+}
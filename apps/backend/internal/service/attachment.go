@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/storage"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/google/uuid"
+)
+
+const defaultAttachmentContentType = "application/octet-stream"
+
+type AttachmentService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewAttachmentService(s *server.Server, repos *repository.Repositories) *AttachmentService {
+	return &AttachmentService{server: s, repos: repos}
+}
+
+// Upload - Stream a client-encrypted attachment into storage.
+// The attachment is charged to the vault owner's quota before any bytes are stored.
+func (s *AttachmentService) Upload(ctx context.Context, userID, secretID string, req *secret.UploadAttachmentRequest, body io.Reader, size int64) (*secret.AttachmentResponse, error) {
+	cfg := s.config()
+	if size < 0 {
+		return nil, errs.NewBadRequestError("Content-Length is required", false, nil, nil, nil)
+	}
+	if size > cfg.MaxAttachmentBytes {
+		return nil, errs.NewPayloadTooLargeError(fmt.Sprintf("Attachments are limited to %d bytes", cfg.MaxAttachmentBytes))
+	}
+	v, err := s.authorize(ctx, userID, secretID, vaultAccessWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &secret.Attachment{
+		SecretID:          secretID,
+		OwnerID:           v.UserID,
+		FileName:          req.FileName,
+		ContentType:       defaultAttachmentContentType,
+		SizeBytes:         size,
+		StorageKey:        fmt.Sprintf("secrets/%s/%s", secretID, uuid.NewString()),
+		EncryptionVersion: 1,
+	}
+	if req.ContentType != nil && *req.ContentType != "" {
+		a.ContentType = *req.ContentType
+	}
+	if req.EncryptionVersion != nil {
+		a.EncryptionVersion = *req.EncryptionVersion
+	}
+
+	reserved, err := s.repos.Attachment.Reserve(ctx, a, cfg.UserQuotaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve attachment: %w", err)
+	}
+	if !reserved {
+		return nil, errs.NewQuotaExceededError("Attachment storage quota exceeded")
+	}
+
+	if err := s.server.Storage.Put(ctx, a.StorageKey, body, size); err != nil {
+		s.discard(ctx, a)
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	vaultID := v.ID.String()
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Attachment.MarkCompleted(ctx, a); err != nil {
+			return nil, fmt.Errorf("failed to complete attachment: %w", err)
+		}
+		log := newAuditLog(ctx, userID, &vaultID, &secretID, audit.ActionCreate)
+		log.Metadata = map[string]any{"attachmentId": a.ID.String(), "sizeBytes": a.SizeBytes}
+		return log, nil
+	})
+	if err != nil {
+		s.discard(ctx, a)
+		return nil, err
+	}
+	return secret.ToAttachmentResponse(a), nil
+}
+
+// List - List the attachments of a secret
+func (s *AttachmentService) List(ctx context.Context, userID, secretID string) ([]*secret.AttachmentResponse, error) {
+	if _, err := s.authorize(ctx, userID, secretID, vaultAccessRead); err != nil {
+		return nil, err
+	}
+	attachments, err := s.repos.Attachment.ListBySecretID(ctx, secretID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	responses := make([]*secret.AttachmentResponse, len(attachments))
+	for i, a := range attachments {
+		responses[i] = secret.ToAttachmentResponse(a)
+	}
+	return responses, nil
+}
+
+// Download - Open an attachment for streaming; the caller must close the reader
+func (s *AttachmentService) Download(ctx context.Context, userID, secretID, attachmentID string) (*secret.Attachment, io.ReadCloser, error) {
+	v, err := s.authorize(ctx, userID, secretID, vaultAccessRead)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := s.getAttachment(ctx, secretID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	blob, err := s.server.Storage.Get(ctx, a.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, errs.NewNotFoundError("Attachment not found", false, nil)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	vaultID := v.ID.String()
+	log := newAuditLog(ctx, userID, &vaultID, &secretID, audit.ActionView)
+	log.Metadata = map[string]any{"attachmentId": attachmentID}
+	if err := s.repos.WithTx(ctx, func(repos *repository.Repositories) error {
+		return recordAudit(ctx, repos, log)
+	}); err != nil {
+		_ = blob.Close()
+		return nil, nil, err
+	}
+	return a, blob, nil
+}
+
+// Delete - Delete an attachment and its blob
+func (s *AttachmentService) Delete(ctx context.Context, userID, secretID, attachmentID string) error {
+	v, err := s.authorize(ctx, userID, secretID, vaultAccessWrite)
+	if err != nil {
+		return err
+	}
+	a, err := s.getAttachment(ctx, secretID, attachmentID)
+	if err != nil {
+		return err
+	}
+	vaultID := v.ID.String()
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Attachment.Delete(ctx, attachmentID); err != nil {
+			return nil, fmt.Errorf("failed to delete attachment: %w", err)
+		}
+		log := newAuditLog(ctx, userID, &vaultID, &secretID, audit.ActionDelete)
+		log.Metadata = map[string]any{"attachmentId": attachmentID}
+		return log, nil
+	})
+	if err != nil {
+		return err
+	}
+	deleteBlobs(ctx, s.server, []string{a.StorageKey})
+	return nil
+}
+
+// Usage - Attachment storage used by the caller's vaults
+func (s *AttachmentService) Usage(ctx context.Context, userID string) (*secret.AttachmentUsageResponse, error) {
+	used, err := s.repos.Attachment.UsageByOwnerID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment usage: %w", err)
+	}
+	return &secret.AttachmentUsageResponse{UsedBytes: used, QuotaBytes: s.config().UserQuotaBytes}, nil
+}
+
+// authorize - Resolve the vault of a secret and require at least the given access
+func (s *AttachmentService) authorize(ctx context.Context, userID, secretID string, required vaultAccess) (*vault.Vault, error) {
+	notFound := errs.NewNotFoundError("Secret not found", false, nil)
+	result, err := s.repos.Secret.GetByID(ctx, secretID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	if result == nil {
+		return nil, notFound
+	}
+	// Errors name the secret rather than its vault
+	v, access, err := loadVaultAccess(ctx, s.repos, userID, result.Secret.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || access == vaultAccessNone {
+		return nil, notFound
+	}
+	if access < required {
+		return nil, errs.NewForbiddenError("Insufficient access to this secret", false)
+	}
+	return v, nil
+}
+
+func (s *AttachmentService) getAttachment(ctx context.Context, secretID, attachmentID string) (*secret.Attachment, error) {
+	a, err := s.repos.Attachment.GetByID(ctx, secretID, attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	if a == nil {
+		return nil, errs.NewNotFoundError("Attachment not found", false, nil)
+	}
+	return a, nil
+}
+
+// discard - Release the reservation and any partial blob of a failed upload,
+// even when the upload failed because the request was cancelled
+func (s *AttachmentService) discard(ctx context.Context, a *secret.Attachment) {
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repos.Attachment.Delete(ctx, a.ID.String()); err != nil {
+		s.server.Logger.Error().Err(err).Str("attachment_id", a.ID.String()).Msg("failed to release attachment reservation")
+	}
+	deleteBlobs(ctx, s.server, []string{a.StorageKey})
+}
+
+func (s *AttachmentService) config() *config.StorageConfig {
+	if s.server.Config.Storage != nil {
+		return s.server.Config.Storage
+	}
+	return config.DefaultStorageConfig()
+}
+
+// deleteBlobs - Best-effort removal of attachment blobs whose rows are gone.
+// Failures are logged; an orphaned blob is unreachable without its row.
+func deleteBlobs(ctx context.Context, s *server.Server, keys []string) {
+	for _, key := range keys {
+		if err := s.Storage.Delete(ctx, key); err != nil {
+			s.Logger.Error().Err(err).Str("storage_key", key).Msg("failed to delete attachment blob")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+)
+
+// defaultAuditLogLimit is used when a listing request does not set a limit
+const defaultAuditLogLimit = 50
+
+type AuditService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewAuditService(s *server.Server, repos *repository.Repositories) *AuditService {
+	return &AuditService{server: s, repos: repos}
+}
+
+// List - List the caller's own audit trail
+func (s *AuditService) List(ctx context.Context, userID string, req *audit.ListAuditLogsRequest) ([]*audit.AuditLog, error) {
+	logs, err := s.repos.Audit.ListByUserID(ctx, userID, auditLogLimit(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return logs, nil
+}
+
+// ListOrg - List the audit trail of the active organization (org admins only)
+func (s *AuditService) ListOrg(ctx context.Context, req *audit.ListAuditLogsRequest) ([]*audit.AuditLog, error) {
+	a, ok := actor.FromContext(ctx)
+	if !ok || !a.IsOrgAdmin() {
+		return nil, errs.NewForbiddenError("Only organization admins can view organization audit logs", false)
+	}
+	logs, err := s.repos.Audit.ListByOrgID(ctx, a.OrgID, auditLogLimit(req))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization audit logs: %w", err)
+	}
+	return logs, nil
+}
+
+// Export - Export the caller's own audit trail over the requested range. The
+// entries are written to w as they are read, when the returned func is called.
+func (s *AuditService) Export(ctx context.Context, userID string, req *audit.ExportAuditLogsRequest) (func(w io.Writer) error, error) {
+	from, to := req.Range()
+	return func(w io.Writer) error {
+		return exportAuditLogs(w, req.Format, func(fn func(*audit.AuditLog) error) error {
+			return s.repos.Audit.StreamByUserID(ctx, userID, from, to, fn)
+		})
+	}, nil
+}
+
+// ExportOrg - Export the audit trail of the active organization over the
+// requested range (org admins only), like Export
+func (s *AuditService) ExportOrg(ctx context.Context, req *audit.ExportAuditLogsRequest) (func(w io.Writer) error, error) {
+	a, ok := actor.FromContext(ctx)
+	if !ok || !a.IsOrgAdmin() {
+		return nil, errs.NewForbiddenError("Only organization admins can export organization audit logs", false)
+	}
+	from, to := req.Range()
+	return func(w io.Writer) error {
+		return exportAuditLogs(w, req.Format, func(fn func(*audit.AuditLog) error) error {
+			return s.repos.Audit.StreamByOrgID(ctx, a.OrgID, from, to, fn)
+		})
+	}, nil
+}
+
+func exportAuditLogs(w io.Writer, format audit.ExportFormat, stream func(fn func(*audit.AuditLog) error) error) error {
+	ew := audit.NewExportWriter(format, w)
+	if err := stream(ew.Write); err != nil {
+		return fmt.Errorf("failed to export audit logs: %w", err)
+	}
+	return ew.Flush()
+}
+
+func auditLogLimit(req *audit.ListAuditLogsRequest) int {
+	if req.Limit == nil {
+		return defaultAuditLogLimit
+	}
+	return *req.Limit
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/lib/siem"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/hibiken/asynq"
+)
+
+// AuditForwardService sends the audit log to the SIEM configured in
+// audit_forward, in order and at least once: the checkpoint only moves past
+// entries the sink accepted, so after a failure or restart forwarding resumes
+// with the first entry not known to be delivered.
+type AuditForwardService struct {
+	server *server.Server
+	repos  *repository.Repositories
+	sink   siem.Sink
+}
+
+func NewAuditForwardService(s *server.Server, repos *repository.Repositories) *AuditForwardService {
+	svc := &AuditForwardService{server: s, repos: repos}
+	cfg := svc.config()
+	if !cfg.Enabled() {
+		return svc
+	}
+	sink, err := siem.New(cfg)
+	if err != nil {
+		s.Logger.Error().Err(err).Msg("failed to set up audit forwarding")
+		return svc
+	}
+	svc.sink = sink
+	svc.registerJobs()
+	return svc
+}
+
+func (s *AuditForwardService) config() *config.AuditForwardConfig {
+	if s.server.Config != nil && s.server.Config.AuditForward != nil {
+		return s.server.Config.AuditForward
+	}
+	return config.DefaultAuditForwardConfig()
+}
+
+func (s *AuditForwardService) registerJobs() {
+	if s.server.Job == nil {
+		return
+	}
+	interval := s.config().Interval
+	s.server.Job.HandleFunc(job.TaskAuditForward, unscopedTask(s.handleForwardTask))
+	if err := s.server.Job.Schedule(fmt.Sprintf("@every %s", interval), job.NewAuditForwardTask(interval)); err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to schedule audit forwarding")
+	}
+}
+
+func (s *AuditForwardService) handleForwardTask(ctx context.Context, _ *asynq.Task) error {
+	forwarded, err := s.Forward(ctx)
+	if forwarded > 0 {
+		s.server.Logger.Info().
+			Str("type", "audit_forward").
+			Str("sink", s.config().Sink).
+			Int("forwarded", forwarded).
+			Msg("Forwarded audit logs")
+	}
+	return err
+}
+
+// Forward - Send the entries past the checkpoint, batch by batch, moving the
+// checkpoint after each batch. Entries younger than the configured delay are
+// left for the next run. Returns how many entries were sent.
+func (s *AuditForwardService) Forward(ctx context.Context) (int, error) {
+	if s.sink == nil {
+		return 0, nil
+	}
+	cfg := s.config()
+	cp, err := s.repos.Audit.GetForwardCheckpoint(ctx, cfg.Checkpoint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get audit forward checkpoint: %w", err)
+	}
+
+	until := time.Now().Add(-cfg.Delay)
+	forwarded := 0
+	for ctx.Err() == nil {
+		logs, err := s.repos.Audit.ListAfter(ctx, cp, until, cfg.BatchSize)
+		if err != nil {
+			return forwarded, fmt.Errorf("failed to list audit logs to forward: %w", err)
+		}
+		if len(logs) == 0 {
+			break
+		}
+
+		events := make([]siem.Event, 0, len(logs))
+		for _, log := range logs {
+			e, err := forwardEvent(log)
+			if err != nil {
+				return forwarded, err
+			}
+			events = append(events, e)
+		}
+		if err := s.sink.Send(ctx, events); err != nil {
+			return forwarded, fmt.Errorf("failed to forward audit logs: %w", err)
+		}
+
+		last := logs[len(logs)-1]
+		cp = &audit.Checkpoint{CreatedAt: last.CreatedAt, ID: last.ID}
+		if err := s.repos.Audit.SaveForwardCheckpoint(ctx, cfg.Checkpoint, cp); err != nil {
+			return forwarded, fmt.Errorf("failed to save audit forward checkpoint: %w", err)
+		}
+		forwarded += len(logs)
+		if len(logs) < cfg.BatchSize {
+			break
+		}
+	}
+	return forwarded, nil
+}
+
+// forwardEvent - SIEM event for an audit entry, without encrypted metadata values
+func forwardEvent(log *audit.AuditLog) (siem.Event, error) {
+	data, err := json.Marshal(log.Redacted())
+	if err != nil {
+		return siem.Event{}, fmt.Errorf("failed to encode audit log %s: %w", log.ID, err)
+	}
+	fields := []siem.Field{
+		{Name: "id", Value: log.ID},
+		{Name: "actorType", Value: string(log.ActorType)},
+		{Name: "actorId", Value: log.UserID},
+	}
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"orgId", log.OrgID},
+		{"vaultId", log.VaultID},
+		{"secretId", log.SecretID},
+		{"ipAddress", log.IPAddress},
+	} {
+		if f.value != nil {
+			fields = append(fields, siem.Field{Name: f.name, Value: *f.value})
+		}
+	}
+	return siem.Event{
+		ID:     log.ID,
+		Time:   log.CreatedAt,
+		Action: string(log.Action),
+		Fields: fields,
+		Data:   data,
+	}, nil
+}
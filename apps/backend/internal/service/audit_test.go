@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/lib/siem"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testAuditLogs() []*audit.AuditLog {
+	vaultID, ip := "v1", "10.0.0.1"
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*audit.AuditLog{
+		{
+			ID: "a1", UserID: "u1", ActorType: audit.ActorTypeUser, VaultID: &vaultID, IPAddress: &ip,
+			Action: audit.ActionUpdate, CreatedAt: at,
+			Metadata: map[string]any{"title": "db, primary", "encryptedData": "c2VjcmV0"},
+		},
+		{ID: "a2", UserID: "s1", ActorType: audit.ActorTypeServiceAccount, Action: audit.ActionView, CreatedAt: at.Add(time.Second)},
+	}
+}
+
+func streamOf(logs []*audit.AuditLog, failAfter int) func(fn func(*audit.AuditLog) error) error {
+	return func(fn func(*audit.AuditLog) error) error {
+		for i, log := range logs {
+			if i == failAfter {
+				return errors.New("connection reset")
+			}
+			if err := fn(log); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestExportAuditLogs_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportAuditLogs(&buf, "", streamOf(testAuditLogs(), -1)))
+	assert.Equal(t, strings.Join([]string{
+		"id,created_at,action,actor_type,actor_id,org_id,vault_id,secret_id,ip_address,user_agent,metadata",
+		`a1,2026-03-01T12:00:00Z,update,user,u1,,v1,,10.0.0.1,,"{""title"":""db, primary""}"`,
+		"a2,2026-03-01T12:00:01Z,view,service_account,s1,,,,,,",
+		"",
+	}, "\n"), buf.String())
+
+	// An empty range still has the header row
+	buf.Reset()
+	require.NoError(t, exportAuditLogs(&buf, audit.ExportFormatCSV, streamOf(nil, -1)))
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+}
+
+func TestExportAuditLogs_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportAuditLogs(&buf, audit.ExportFormatNDJSON, streamOf(testAuditLogs(), -1)))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var first audit.AuditLog
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "a1", first.ID)
+	assert.Equal(t, map[string]any{"title": "db, primary"}, first.Metadata)
+}
+
+func TestExportAuditLogs_StreamError(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportAuditLogs(&buf, audit.ExportFormatNDJSON, streamOf(testAuditLogs(), 1))
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+func TestForwardEvent(t *testing.T) {
+	logs := testAuditLogs()
+	e, err := forwardEvent(logs[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, "a1", e.ID)
+	assert.Equal(t, "update", e.Action)
+	assert.Equal(t, logs[0].CreatedAt, e.Time)
+	assert.Equal(t, []siem.Field{
+		{Name: "id", Value: "a1"},
+		{Name: "actorType", Value: "user"},
+		{Name: "actorId", Value: "u1"},
+		{Name: "vaultId", Value: "v1"},
+		{Name: "ipAddress", Value: "10.0.0.1"},
+	}, e.Fields)
+	assert.NotContains(t, string(e.Data), "encryptedData")
+	assert.Contains(t, string(e.Data), `"title":"db, primary"`)
+}
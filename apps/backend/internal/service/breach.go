@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/breach"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+)
+
+const (
+	// Padded responses hold a random number of entries in this range, so the
+	// response size does not reveal how many real hashes share the prefix
+	breachPadMin = 800
+	breachPadMax = 1000
+)
+
+type BreachService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewBreachService(s *server.Server, repos *repository.Repositories) *BreachService {
+	return &BreachService{server: s, repos: repos}
+}
+
+// Range - List the breached hashes sharing a 5-character SHA-1 prefix.
+// With pad set, random entries with a count of 0 are mixed in.
+func (s *BreachService) Range(ctx context.Context, prefix string, pad bool) ([]breach.Entry, error) {
+	if !breach.ValidPrefix(prefix) {
+		return nil, errs.NewBadRequestError(fmt.Sprintf("The prefix must be %d hex characters", breach.PrefixLength), false, nil, nil, nil)
+	}
+	prefix = strings.ToUpper(prefix)
+	entries, err := s.repos.Breach.Range(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get breach range: %w", err)
+	}
+	if pad {
+		if entries, err = padBreachRange(prefix, entries); err != nil {
+			return nil, fmt.Errorf("failed to pad breach range: %w", err)
+		}
+	}
+	return entries, nil
+}
+
+func padBreachRange(prefix string, entries []breach.Entry) ([]breach.Entry, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(breachPadMax-breachPadMin+1))
+	if err != nil {
+		return nil, err
+	}
+	target := breachPadMin + int(n.Int64())
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		seen[e.Hash] = true
+	}
+	buf := make([]byte, breach.HashLength/2)
+	for len(entries) < target {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		hash := prefix + strings.ToUpper(hex.EncodeToString(buf))[breach.PrefixLength:]
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		entries = append(entries, breach.Entry{Hash: hash, Count: 0})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Hash < entries[j].Hash })
+	return entries, nil
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/lib/breach"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPadBreachRange(t *testing.T) {
+	known := breach.Entry{Hash: "21BD10018A45C4D1DEF81644B54AB7F969B88D65", Count: 3}
+	entries, err := padBreachRange("21BD1", []breach.Entry{known})
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, len(entries), breachPadMin)
+	assert.LessOrEqual(t, len(entries), breachPadMax)
+	assert.True(t, sort.SliceIsSorted(entries, func(i, j int) bool { return entries[i].Hash < entries[j].Hash }))
+	assert.Contains(t, entries, known)
+	for _, e := range entries {
+		assert.Equal(t, "21BD1", e.Prefix())
+		assert.Len(t, e.Hash, breach.HashLength)
+		if e.Hash != known.Hash {
+			assert.Zero(t, e.Count)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
 	"github.com/Sameer16536/psvault/internal/model/device"
 	"github.com/Sameer16536/psvault/internal/repository"
 	"github.com/Sameer16536/psvault/internal/server"
@@ -21,6 +23,9 @@ func NewDeviceService(s *server.Server, repos *repository.Repositories) *DeviceS
 
 // Register - Register or update a device
 func (s *DeviceService) Register(ctx context.Context, userID string, req *device.RegisterDeviceRequest) (*device.DeviceResponse, error) {
+	if actor.IsServiceAccount(ctx) {
+		return nil, errs.NewForbiddenError("Service accounts cannot register devices", false)
+	}
 	d := &device.Device{
 		UserID:            userID,
 		DeviceFingerprint: req.DeviceFingerprint,
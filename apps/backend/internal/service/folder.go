@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/folder"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+)
+
+type FolderService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewFolderService(s *server.Server, repos *repository.Repositories) *FolderService {
+	return &FolderService{server: s, repos: repos}
+}
+
+// Create - Create a folder in a vault
+func (s *FolderService) Create(ctx context.Context, userID, vaultID string, req *folder.CreateFolderRequest) (*folder.FolderResponse, error) {
+	if _, err := authorizeVault(ctx, s.repos, userID, vaultID, vaultAccessWrite); err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil {
+		if _, err := s.getFolderInVault(ctx, *req.ParentID, vaultID); err != nil {
+			return nil, err
+		}
+	}
+	f := &folder.Folder{VaultID: vaultID, ParentID: req.ParentID, Name: req.Name}
+	_, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Folder.Create(ctx, f); err != nil {
+			return nil, fmt.Errorf("failed to create folder: %w", err)
+		}
+		return folderAuditLog(ctx, userID, f, audit.ActionFolderCreate, map[string]any{"name": f.Name, "parentId": f.ParentID}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return folder.ToFolderResponse(f), nil
+}
+
+// Tree - List the folders and secret titles of a vault, recursively below parentID if set
+func (s *FolderService) Tree(ctx context.Context, userID, vaultID string, req *folder.ListFoldersRequest) (*folder.FolderTreeResponse, error) {
+	if _, err := authorizeVault(ctx, s.repos, userID, vaultID, vaultAccessRead); err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil {
+		if _, err := s.getFolderInVault(ctx, *req.ParentID, vaultID); err != nil {
+			return nil, err
+		}
+	}
+	folders, err := s.repos.Folder.ListTree(ctx, vaultID, req.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list folders: %w", err)
+	}
+	summaries, err := s.repos.Folder.ListSecretSummaries(ctx, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	tree := &folder.FolderTreeResponse{
+		VaultID:  vaultID,
+		ParentID: req.ParentID,
+		Folders:  []*folder.FolderNode{},
+		Secrets:  []*folder.SecretSummary{},
+	}
+	// Folders arrive parents first, so every parent is indexed before its children
+	nodes := make(map[string]*folder.FolderNode, len(folders))
+	for _, f := range folders {
+		node := &folder.FolderNode{
+			FolderResponse: *folder.ToFolderResponse(f),
+			Children:       []*folder.FolderNode{},
+			Secrets:        []*folder.SecretSummary{},
+		}
+		nodes[node.ID] = node
+		if parent, ok := nodes[deref(f.ParentID)]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			tree.Folders = append(tree.Folders, node)
+		}
+	}
+	for _, sum := range summaries {
+		if node, ok := nodes[deref(sum.FolderID)]; ok {
+			node.Secrets = append(node.Secrets, sum)
+		} else if deref(sum.FolderID) == deref(req.ParentID) {
+			tree.Secrets = append(tree.Secrets, sum)
+		}
+	}
+	return tree, nil
+}
+
+// Rename - Rename a folder
+func (s *FolderService) Rename(ctx context.Context, userID, folderID string, req *folder.RenameFolderRequest) (*folder.FolderResponse, error) {
+	f, err := s.getWritableFolder(ctx, userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	oldName := f.Name
+	f.Name = req.Name
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Folder.Rename(ctx, f); err != nil {
+			return nil, fmt.Errorf("failed to rename folder: %w", err)
+		}
+		return folderAuditLog(ctx, userID, f, audit.ActionFolderRename, map[string]any{"from": oldName, "to": f.Name}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return folder.ToFolderResponse(f), nil
+}
+
+// Move - Move a folder under another folder of the same vault, or to the top level
+func (s *FolderService) Move(ctx context.Context, userID, folderID string, req *folder.MoveFolderRequest) (*folder.FolderResponse, error) {
+	f, err := s.getWritableFolder(ctx, userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ParentID != nil {
+		if _, err := s.getFolderInVault(ctx, *req.ParentID, f.VaultID); err != nil {
+			return nil, err
+		}
+	}
+	from := f.ParentID
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		moved, err := repos.Folder.Move(ctx, f, req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move folder: %w", err)
+		}
+		if !moved {
+			return nil, errs.NewBadRequestError("A folder cannot be moved into itself or one of its subfolders", false, nil, nil, nil)
+		}
+		return folderAuditLog(ctx, userID, f, audit.ActionFolderMove, map[string]any{"from": from, "to": f.ParentID}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return folder.ToFolderResponse(f), nil
+}
+
+// Delete - Delete a folder, moving its contents up a level (default) or to the vault's trash
+func (s *FolderService) Delete(ctx context.Context, userID, folderID string, req *folder.DeleteFolderRequest) error {
+	f, err := s.getWritableFolder(ctx, userID, folderID)
+	if err != nil {
+		return err
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = folder.DeleteModeUp
+	}
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		target := f.ParentID
+		if mode == folder.DeleteModeTrash {
+			trash, err := repos.Folder.GetOrCreateTrash(ctx, f.VaultID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get trash folder: %w", err)
+			}
+			trashID := trash.ID.String()
+			target = &trashID
+		}
+		if err := repos.Folder.Delete(ctx, f, target); err != nil {
+			return nil, fmt.Errorf("failed to delete folder: %w", err)
+		}
+		return folderAuditLog(ctx, userID, f, audit.ActionFolderDelete, map[string]any{"name": f.Name, "mode": mode, "movedTo": target}), nil
+	})
+	return err
+}
+
+// MoveSecret - Move a secret into a folder of its vault, or to the top level
+func (s *FolderService) MoveSecret(ctx context.Context, userID, secretID string, req *folder.MoveSecretRequest) error {
+	result, err := s.repos.Secret.GetByID(ctx, secretID)
+	if err != nil {
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+	if result == nil {
+		return errs.NewNotFoundError("Secret not found", false, nil)
+	}
+	vaultID := result.Secret.VaultID
+	if _, err := authorizeVault(ctx, s.repos, userID, vaultID, vaultAccessWrite); err != nil {
+		return err
+	}
+	if req.FolderID != nil {
+		if _, err := s.getFolderInVault(ctx, *req.FolderID, vaultID); err != nil {
+			return err
+		}
+	}
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Secret.SetFolder(ctx, secretID, req.FolderID); err != nil {
+			return nil, fmt.Errorf("failed to move secret: %w", err)
+		}
+		log := newAuditLog(ctx, userID, &vaultID, &secretID, audit.ActionSecretMove)
+		log.Metadata = map[string]any{"from": result.Secret.FolderID, "to": req.FolderID}
+		return log, nil
+	})
+	return err
+}
+
+// getWritableFolder - Get a folder the caller may change; the trash folder itself is fixed
+func (s *FolderService) getWritableFolder(ctx context.Context, userID, folderID string) (*folder.Folder, error) {
+	f, err := s.repos.Folder.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+	if f == nil {
+		return nil, errs.NewNotFoundError("Folder not found", false, nil)
+	}
+	if _, err := authorizeVault(ctx, s.repos, userID, f.VaultID, vaultAccessWrite); err != nil {
+		return nil, err
+	}
+	if f.IsTrash {
+		return nil, errs.NewBadRequestError("The trash folder cannot be renamed, moved or deleted", false, nil, nil, nil)
+	}
+	return f, nil
+}
+
+func (s *FolderService) getFolderInVault(ctx context.Context, folderID, vaultID string) (*folder.Folder, error) {
+	f, err := s.repos.Folder.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folder: %w", err)
+	}
+	if f == nil || f.VaultID != vaultID {
+		return nil, errs.NewNotFoundError("Folder not found", false, nil)
+	}
+	return f, nil
+}
+
+// folderAuditLog builds the audit entry of a change to folder f
+func folderAuditLog(ctx context.Context, userID string, f *folder.Folder, action audit.Action, metadata map[string]any) *audit.AuditLog {
+	metadata["folderId"] = f.ID.String()
+	log := newAuditLog(ctx, userID, &f.VaultID, nil, action)
+	log.Metadata = metadata
+	return log
+}
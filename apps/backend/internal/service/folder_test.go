@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/model/folder"
+	"github.com/Sameer16536/psvault/internal/repository"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper to create a folder through the service
+func createTestFolder(t *testing.T, ctx context.Context, svc *FolderService, userID, vaultID, name string, parentID *string) string {
+	t.Helper()
+	f, err := svc.Create(ctx, userID, vaultID, &folder.CreateFolderRequest{Name: name, ParentID: parentID})
+	require.NoError(t, err, "setup: failed to create folder")
+	return f.ID
+}
+
+// Test: Folders nest into a tree; a folder cannot be moved below itself or into another vault
+func TestFolderService_Move(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := NewFolderService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+
+	userID, userCtx := createTestActor(t, ctx, repos, "user_alice")
+	vaultID := createTestVault(t, ctx, repos, userID, "Personal")
+	otherVaultID := createTestVault(t, ctx, repos, userID, "Work")
+
+	top := createTestFolder(t, userCtx, svc, userID, vaultID, "Top", nil)
+	mid := createTestFolder(t, userCtx, svc, userID, vaultID, "Mid", &top)
+	leaf := createTestFolder(t, userCtx, svc, userID, vaultID, "Leaf", &mid)
+	elsewhere := createTestFolder(t, userCtx, svc, userID, otherVaultID, "Elsewhere", nil)
+
+	_, err := svc.Move(userCtx, userID, top, &folder.MoveFolderRequest{ParentID: &top})
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+	_, err = svc.Move(userCtx, userID, top, &folder.MoveFolderRequest{ParentID: &leaf})
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+	_, err = svc.Move(userCtx, userID, mid, &folder.MoveFolderRequest{ParentID: &elsewhere})
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	tree, err := svc.Tree(userCtx, userID, vaultID, &folder.ListFoldersRequest{})
+	require.NoError(t, err)
+	require.Len(t, tree.Folders, 1)
+	assert.Equal(t, top, tree.Folders[0].ID)
+	require.Len(t, tree.Folders[0].Children, 1)
+	assert.Equal(t, mid, tree.Folders[0].Children[0].ID)
+	require.Len(t, tree.Folders[0].Children[0].Children, 1)
+	assert.Equal(t, leaf, tree.Folders[0].Children[0].Children[0].ID)
+
+	// Moving to the top level detaches the subtree
+	moved, err := svc.Move(userCtx, userID, mid, &folder.MoveFolderRequest{})
+	require.NoError(t, err)
+	assert.Nil(t, moved.ParentID)
+	_, err = svc.Move(userCtx, userID, top, &folder.MoveFolderRequest{ParentID: &leaf})
+	require.NoError(t, err)
+
+	tree, err = svc.Tree(userCtx, userID, vaultID, &folder.ListFoldersRequest{})
+	require.NoError(t, err)
+	require.Len(t, tree.Folders, 1)
+	assert.Equal(t, mid, tree.Folders[0].ID)
+	require.Len(t, tree.Folders[0].Children, 1)
+	require.Len(t, tree.Folders[0].Children[0].Children, 1)
+	assert.Equal(t, top, tree.Folders[0].Children[0].Children[0].ID)
+}
+
+// Test: Deleting a folder moves its subfolders and secrets to its parent, or to the vault's trash
+func TestFolderService_Delete(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := NewFolderService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+
+	userID, userCtx := createTestActor(t, ctx, repos, "user_alice")
+	strangerID, strangerCtx := createTestActor(t, ctx, repos, "user_stranger")
+	vaultID := createTestVault(t, ctx, repos, userID, "Personal")
+
+	top := createTestFolder(t, userCtx, svc, userID, vaultID, "Top", nil)
+	mid := createTestFolder(t, userCtx, svc, userID, vaultID, "Mid", &top)
+	leaf := createTestFolder(t, userCtx, svc, userID, vaultID, "Leaf", &mid)
+	secretID := createTestSecret(t, ctx, repos, vaultID, "GitHub")
+	require.NoError(t, svc.MoveSecret(userCtx, userID, secretID, &folder.MoveSecretRequest{FolderID: &mid}))
+
+	assertHTTPStatus(t, svc.Delete(strangerCtx, strangerID, mid, &folder.DeleteFolderRequest{}), http.StatusNotFound)
+
+	// The default mode moves the contents up a level
+	require.NoError(t, svc.Delete(userCtx, userID, mid, &folder.DeleteFolderRequest{}))
+	tree, err := svc.Tree(userCtx, userID, vaultID, &folder.ListFoldersRequest{})
+	require.NoError(t, err)
+	require.Len(t, tree.Folders, 1)
+	node := tree.Folders[0]
+	assert.Equal(t, top, node.ID)
+	require.Len(t, node.Children, 1)
+	assert.Equal(t, leaf, node.Children[0].ID)
+	require.Len(t, node.Secrets, 1)
+	assert.Equal(t, secretID, node.Secrets[0].ID)
+
+	// A top-level folder's contents move to the top level
+	require.NoError(t, svc.Delete(userCtx, userID, top, &folder.DeleteFolderRequest{Mode: folder.DeleteModeUp}))
+	tree, err = svc.Tree(userCtx, userID, vaultID, &folder.ListFoldersRequest{})
+	require.NoError(t, err)
+	require.Len(t, tree.Folders, 1)
+	assert.Equal(t, leaf, tree.Folders[0].ID)
+	require.Len(t, tree.Secrets, 1)
+	assert.Equal(t, secretID, tree.Secrets[0].ID)
+
+	// The trash mode moves the contents to the trash folder
+	require.NoError(t, svc.MoveSecret(userCtx, userID, secretID, &folder.MoveSecretRequest{FolderID: &leaf}))
+	require.NoError(t, svc.Delete(userCtx, userID, leaf, &folder.DeleteFolderRequest{Mode: folder.DeleteModeTrash}))
+	tree, err = svc.Tree(userCtx, userID, vaultID, &folder.ListFoldersRequest{})
+	require.NoError(t, err)
+	require.Len(t, tree.Folders, 1)
+	trash := tree.Folders[0]
+	assert.True(t, trash.IsTrash)
+	require.Len(t, trash.Secrets, 1)
+	assert.Equal(t, secretID, trash.Secrets[0].ID)
+	assert.Empty(t, tree.Secrets)
+}
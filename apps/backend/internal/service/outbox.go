@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/model/outbox"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	// outboxLease is how long a claimed message is hidden from other relays;
+	// a relay that dies mid-batch leaves its messages to be claimed again
+	outboxLease = time.Minute
+	// outboxBackoffBase and outboxBackoffMax bound the delay before a failed message is retried
+	outboxBackoffBase = 30 * time.Second
+	outboxBackoffMax  = time.Hour
+)
+
+// OutboxService relays messages written to the transactional outbox: tasks to
+// the job queues and events to Redis pub/sub. Delivery is at least once;
+// consumers recognise redeliveries by the task ID or event ID.
+type OutboxService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewOutboxService(s *server.Server, repos *repository.Repositories) *OutboxService {
+	svc := &OutboxService{server: s, repos: repos}
+	svc.registerJobs()
+	return svc
+}
+
+func (s *OutboxService) config() *config.OutboxConfig {
+	if s.server.Config != nil && s.server.Config.Outbox != nil {
+		return s.server.Config.Outbox
+	}
+	return config.DefaultOutboxConfig()
+}
+
+func (s *OutboxService) registerJobs() {
+	if s.server.Job == nil {
+		return
+	}
+	interval := s.config().RelayInterval
+	s.server.Job.HandleFunc(job.TaskOutboxRelay, unscopedTask(s.handleRelayTask))
+	if err := s.server.Job.Schedule(fmt.Sprintf("@every %s", interval), job.NewOutboxRelayTask(interval)); err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to schedule outbox relay")
+	}
+}
+
+// handleRelayTask publishes the messages due, batch by batch, then purges old published ones
+func (s *OutboxService) handleRelayTask(ctx context.Context, _ *asynq.Task) error {
+	cfg := s.config()
+	published, retried, failed := 0, 0, 0
+	for ctx.Err() == nil {
+		messages, err := s.repos.Outbox.ClaimPending(ctx, cfg.BatchSize, time.Now().Add(outboxLease))
+		if err != nil {
+			return fmt.Errorf("failed to claim outbox messages: %w", err)
+		}
+		for _, m := range messages {
+			if err := s.publish(ctx, m); err != nil {
+				if m.Attempts >= cfg.MaxAttempts {
+					failed++
+					err = s.repos.Outbox.MarkFailed(ctx, m.ID, err.Error())
+				} else {
+					retried++
+					err = s.repos.Outbox.MarkRetry(ctx, m.ID, err.Error(), time.Now().Add(outboxRetryDelay(m.Attempts)))
+				}
+				if err != nil {
+					return fmt.Errorf("failed to record outbox attempt: %w", err)
+				}
+				continue
+			}
+			published++
+			if err := s.repos.Outbox.MarkPublished(ctx, m.ID); err != nil {
+				return fmt.Errorf("failed to mark outbox message published: %w", err)
+			}
+		}
+		if len(messages) < cfg.BatchSize {
+			break
+		}
+	}
+
+	purged, err := s.repos.Outbox.PurgePublished(ctx, time.Now().Add(-cfg.Retention))
+	if err != nil {
+		return fmt.Errorf("failed to purge outbox: %w", err)
+	}
+	if published+retried+failed > 0 || purged > 0 {
+		s.server.Logger.Info().
+			Str("type", "outbox_relay").
+			Int("published", published).
+			Int("retried", retried).
+			Int("failed", failed).
+			Int64("purged", purged).
+			Msg("Relayed outbox messages")
+	}
+	return nil
+}
+
+func (s *OutboxService) publish(ctx context.Context, m *outbox.Message) error {
+	switch m.Kind {
+	case outbox.KindTask:
+		// The task ID makes a redelivery a no-op while the first delivery is still queued
+		_, err := s.server.Job.Dispatch(ctx, m.Topic, m.Payload, asynq.TaskID(m.DeliveryID()))
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			return nil
+		}
+		return err
+	case outbox.KindEvent:
+		if s.server.Redis == nil {
+			return fmt.Errorf("redis is not configured")
+		}
+		event, err := json.Marshal(outbox.Event{
+			ID:        m.DeliveryID(),
+			Topic:     m.Topic,
+			Payload:   m.Payload,
+			CreatedAt: m.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return s.server.Redis.Publish(ctx, outbox.EventChannel(m.Topic), event).Err()
+	default:
+		return fmt.Errorf("unknown outbox message kind %q", m.Kind)
+	}
+}
+
+// outboxRetryDelay - Exponential backoff after the given number of attempts
+func outboxRetryDelay(attempts int) time.Duration {
+	return job.ExponentialBackoff(outboxBackoffBase, outboxBackoffMax, attempts-1)
+}
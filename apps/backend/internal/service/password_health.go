@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+)
+
+type PasswordHealthService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewPasswordHealthService(s *server.Server, repos *repository.Repositories) *PasswordHealthService {
+	return &PasswordHealthService{server: s, repos: repos}
+}
+
+// Set - Set the client-computed health signals of a secret
+func (s *PasswordHealthService) Set(ctx context.Context, userID, secretID string, req *secret.SetPasswordHealthRequest) (*secret.PasswordHealth, error) {
+	result, err := s.repos.Secret.GetByID(ctx, secretID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	if result == nil {
+		return nil, errs.NewNotFoundError("Secret not found", false, nil)
+	}
+	if _, err := authorizeVault(ctx, s.repos, userID, result.Secret.VaultID, vaultAccessWrite); err != nil {
+		return nil, err
+	}
+	if req.LastRotatedAt != nil && req.LastRotatedAt.After(time.Now().Add(time.Minute)) {
+		return nil, errs.NewBadRequestError("lastRotatedAt cannot be in the future", false, nil, nil, nil)
+	}
+	h := &secret.PasswordHealth{
+		SecretID:         secretID,
+		StrengthScore:    *req.StrengthScore,
+		ReuseFingerprint: req.ReuseFingerprint,
+		LastRotatedAt:    req.LastRotatedAt,
+	}
+	if err := s.repos.PasswordHealth.Upsert(ctx, h); err != nil {
+		return nil, fmt.Errorf("failed to save password health: %w", err)
+	}
+	return h, nil
+}
+
+// Report - Report the weak, reused and old secrets of a vault
+func (s *PasswordHealthService) Report(ctx context.Context, userID, vaultID string, req *secret.VaultHealthRequest) (*secret.VaultHealthReport, error) {
+	if _, err := authorizeVault(ctx, s.repos, userID, vaultID, vaultAccessRead); err != nil {
+		return nil, err
+	}
+	rows, err := s.repos.PasswordHealth.ListByVaultID(ctx, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list password health: %w", err)
+	}
+	cfg := s.config()
+	policy := secret.HealthPolicy{
+		RotationDays: int(cfg.RotationWindow / (24 * time.Hour)),
+		WeakScore:    cfg.WeakScore,
+	}
+	if req.RotationDays != nil {
+		policy.RotationDays = *req.RotationDays
+	}
+	report := buildHealthReport(rows, policy, time.Now())
+	report.VaultID = vaultID
+	return report, nil
+}
+
+func (s *PasswordHealthService) config() *config.HealthConfig {
+	if s.server.Config.Health != nil {
+		return s.server.Config.Health
+	}
+	return config.DefaultHealthConfig()
+}
+
+// buildHealthReport - Aggregate the health rows of a vault under a policy.
+// Weak and reused only consider secrets with client signals; old applies to every
+// secret, falling back to the payload's storage time when the client never reported a rotation.
+func buildHealthReport(rows []*secret.PasswordHealthRow, policy secret.HealthPolicy, now time.Time) *secret.VaultHealthReport {
+	report := &secret.VaultHealthReport{
+		GeneratedAt: now,
+		Policy:      policy,
+		Reused:      []*secret.ReusedGroup{},
+		Weak:        []*secret.HealthEntry{},
+		Old:         []*secret.HealthEntry{},
+	}
+	cutoff := now.AddDate(0, 0, -policy.RotationDays)
+	groups := make(map[string]*secret.ReusedGroup)
+	var order []string
+
+	for _, row := range rows {
+		report.Summary.Secrets++
+		entry := &secret.HealthEntry{
+			SecretID:       row.SecretID,
+			Title:          row.Title,
+			Type:           row.Type,
+			FolderID:       row.FolderID,
+			StrengthScore:  row.StrengthScore,
+			LastRotatedAt:  row.LastRotatedAt,
+			LastAccessedAt: row.LastAccessedAt,
+		}
+		if row.StrengthScore != nil {
+			report.Summary.WithSignals++
+			if *row.StrengthScore < policy.WeakScore {
+				report.Weak = append(report.Weak, entry)
+			}
+		}
+		if row.ReuseFingerprint != nil {
+			g, ok := groups[*row.ReuseFingerprint]
+			if !ok {
+				g = &secret.ReusedGroup{}
+				groups[*row.ReuseFingerprint] = g
+				order = append(order, *row.ReuseFingerprint)
+			}
+			g.Secrets = append(g.Secrets, entry)
+			g.Count++
+		}
+		if row.LastRotatedAt.Before(cutoff) {
+			report.Old = append(report.Old, entry)
+		}
+	}
+
+	for _, fingerprint := range order {
+		if g := groups[fingerprint]; g.Count > 1 {
+			report.Reused = append(report.Reused, g)
+			report.Summary.Reused += g.Count
+		}
+	}
+	// Largest groups first, then the weakest and oldest entries
+	sort.SliceStable(report.Reused, func(i, j int) bool { return report.Reused[i].Count > report.Reused[j].Count })
+	sort.SliceStable(report.Weak, func(i, j int) bool { return *report.Weak[i].StrengthScore < *report.Weak[j].StrengthScore })
+	sort.SliceStable(report.Old, func(i, j int) bool { return report.Old[i].LastRotatedAt.Before(report.Old[j].LastRotatedAt) })
+	report.Summary.Weak = len(report.Weak)
+	report.Summary.Old = len(report.Old)
+	return report
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildHealthReport(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	score := func(n int) *int { return &n }
+	fp := func(s string) *string { return &s }
+	rows := []*secret.PasswordHealthRow{
+		{SecretID: "a", StrengthScore: score(4), ReuseFingerprint: fp("f1"), LastRotatedAt: now.AddDate(0, 0, -10)},
+		{SecretID: "b", StrengthScore: score(1), ReuseFingerprint: fp("f1"), LastRotatedAt: now.AddDate(0, 0, -200)},
+		{SecretID: "c", StrengthScore: score(2), ReuseFingerprint: fp("f2"), LastRotatedAt: now.AddDate(0, 0, -400)},
+		{SecretID: "d", StrengthScore: score(0), ReuseFingerprint: fp("f1"), LastRotatedAt: now},
+		// No client signals: only the rotation check applies
+		{SecretID: "e", LastRotatedAt: now.AddDate(0, 0, -181)},
+		{SecretID: "f", LastRotatedAt: now},
+	}
+
+	report := buildHealthReport(rows, secret.HealthPolicy{RotationDays: 180, WeakScore: 3}, now)
+
+	assert.Equal(t, secret.HealthSummary{Secrets: 6, WithSignals: 4, Weak: 3, Reused: 3, Old: 3}, report.Summary)
+	if assert.Len(t, report.Reused, 1) {
+		assert.Equal(t, []string{"a", "b", "d"}, entryIDs(report.Reused[0].Secrets))
+	}
+	assert.Equal(t, []string{"d", "b", "c"}, entryIDs(report.Weak))
+	assert.Equal(t, []string{"c", "b", "e"}, entryIDs(report.Old))
+}
+
+func TestBuildHealthReportEmpty(t *testing.T) {
+	report := buildHealthReport(nil, secret.HealthPolicy{RotationDays: 90, WeakScore: 3}, time.Now())
+	assert.Equal(t, secret.HealthSummary{}, report.Summary)
+	assert.NotNil(t, report.Reused)
+	assert.NotNil(t, report.Weak)
+	assert.NotNil(t, report.Old)
+}
+
+func entryIDs(entries []*secret.HealthEntry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.SecretID)
+	}
+	return ids
+}
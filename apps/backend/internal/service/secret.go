@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/searchquery"
 	"github.com/Sameer16536/psvault/internal/model/audit"
 	"github.com/Sameer16536/psvault/internal/model/secret"
 	"github.com/Sameer16536/psvault/internal/repository"
@@ -11,44 +14,57 @@ import (
 )
 
 type SecretService struct {
-	server *server.Server
-	repos  *repository.Repositories
+	server  *server.Server
+	repos   *repository.Repositories
+	anomaly *AnomalyService
+	types   *SecretTypeService
 }
 
-func NewSecretService(s *server.Server, repos *repository.Repositories) *SecretService {
-	return &SecretService{server: s, repos: repos}
+func NewSecretService(s *server.Server, repos *repository.Repositories, anomaly *AnomalyService, types *SecretTypeService) *SecretService {
+	svc := &SecretService{server: s, repos: repos, anomaly: anomaly, types: types}
+	svc.registerJobs()
+	return svc
 }
 
 // Create - Create a new secret with metadata
 func (s *SecretService) Create(ctx context.Context, userID string, req *secret.CreateSecretRequest) (*secret.SecretResponse, error) {
-	// Verify vault ownership
-	vault, err := s.repos.Vault.GetByID(ctx, req.VaultID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get vault: %w", err)
+	if _, err := authorizeVault(ctx, s.repos, userID, req.VaultID, vaultAccessWrite); err != nil {
+		return nil, err
 	}
-	if vault == nil {
-		return nil, fmt.Errorf("vault not found")
+	if err := s.validateAttributes(ctx, s.repos, req.Type, req.Metadata.Attributes); err != nil {
+		return nil, err
 	}
-	if vault.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access to vault")
+	if err := s.checkFolder(ctx, s.repos, req.FolderID, req.VaultID); err != nil {
+		return nil, err
 	}
 	sec := &secret.Secret{
 		VaultID:           req.VaultID,
+		FolderID:          req.FolderID,
 		Type:              req.Type,
 		EncryptedPayload:  req.EncryptedPayload,
 		EncryptionVersion: req.EncryptionVersion,
 	}
-	meta := &secret.SecretMetadata{
-		Title:  req.Metadata.Title,
-		Domain: req.Metadata.Domain,
-		Tags:   req.Metadata.Tags,
+	if req.Policy != nil {
+		req.Policy.Apply(sec)
 	}
-	if err := s.repos.Secret.Create(ctx, sec, meta); err != nil {
-		return nil, fmt.Errorf("failed to create secret: %w", err)
+	meta := &secret.SecretMetadata{
+		Title:        req.Metadata.Title,
+		Domain:       req.Metadata.Domain,
+		Tags:         req.Metadata.Tags,
+		Attributes:   req.Metadata.Attributes,
+		CustomFields: req.Metadata.CustomFields,
+	}
+	log, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Secret.Create(ctx, sec, meta); err != nil {
+			return nil, fmt.Errorf("failed to create secret: %w", err)
+		}
+		secIDStr := sec.ID.String()
+		return newAuditLog(ctx, userID, &req.VaultID, &secIDStr, audit.ActionCreate), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	// Log audit
-	secIDStr := sec.ID.String()
-	s.logAudit(ctx, userID, &req.VaultID, &secIDStr, audit.ActionCreate)
+	s.anomaly.Observe(ctx, log)
 	return s.toSecretResponse(sec, meta), nil
 }
 
@@ -59,35 +75,24 @@ func (s *SecretService) GetByID(ctx context.Context, userID, secretID string) (*
 		return nil, fmt.Errorf("failed to get secret: %w", err)
 	}
 	if result == nil {
-		return nil, fmt.Errorf("secret not found")
+		return nil, errs.NewNotFoundError("Secret not found", false, nil)
 	}
-	// Verify vault ownership
-	vault, err := s.repos.Vault.GetByID(ctx, result.Secret.VaultID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get vault: %w", err)
-	}
-	if vault.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access to secret")
+	if _, err := authorizeVault(ctx, s.repos, userID, result.Secret.VaultID, vaultAccessRead); err != nil {
+		return nil, err
 	}
 	// Update last accessed
 	_ = s.repos.Secret.UpdateLastAccessed(ctx, secretID)
-	// Log audit
-	s.logAudit(ctx, userID, &result.Secret.VaultID, &secretID, audit.ActionView)
+	// A reveal is never served without its audit record
+	if err := s.logAudit(ctx, userID, &result.Secret.VaultID, &secretID, audit.ActionView); err != nil {
+		return nil, err
+	}
 	return s.toSecretResponse(result.Secret, result.Metadata), nil
 }
 
 // List - List secrets in a vault
 func (s *SecretService) List(ctx context.Context, userID, vaultID string) ([]*secret.SecretResponse, error) {
-	// Verify vault ownership
-	vault, err := s.repos.Vault.GetByID(ctx, vaultID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get vault: %w", err)
-	}
-	if vault == nil {
-		return nil, fmt.Errorf("vault not found")
-	}
-	if vault.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access to vault")
+	if _, err := authorizeVault(ctx, s.repos, userID, vaultID, vaultAccessRead); err != nil {
+		return nil, err
 	}
 	results, err := s.repos.Secret.ListByVaultID(ctx, vaultID)
 	if err != nil {
@@ -118,7 +123,14 @@ func (s *SecretService) Search(ctx context.Context, userID string, req *secret.S
 	if len(req.Tags) > 0 {
 		filters["tags"] = req.Tags
 	}
-	results, err := s.repos.Secret.Search(ctx, userID, filters)
+	if req.Q != nil {
+		node, err := searchquery.Parse(*req.Q)
+		if err != nil {
+			return nil, errs.NewBadRequestError("Invalid search query: "+err.Error(), false, nil, nil, nil)
+		}
+		filters["query"] = node
+	}
+	results, err := s.repos.Secret.Search(ctx, memberTypeFromContext(ctx), userID, activeOrgID(ctx), filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search secrets: %w", err)
 	}
@@ -136,15 +148,10 @@ func (s *SecretService) Update(ctx context.Context, userID, secretID string, req
 		return nil, fmt.Errorf("failed to get secret: %w", err)
 	}
 	if result == nil {
-		return nil, fmt.Errorf("secret not found")
-	}
-	// Verify vault ownership
-	vault, err := s.repos.Vault.GetByID(ctx, result.Secret.VaultID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get vault: %w", err)
+		return nil, errs.NewNotFoundError("Secret not found", false, nil)
 	}
-	if vault.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access to secret")
+	if _, err := authorizeVault(ctx, s.repos, userID, result.Secret.VaultID, vaultAccessWrite); err != nil {
+		return nil, err
 	}
 	// Update fields if provided
 	if req.EncryptedPayload != nil {
@@ -154,15 +161,28 @@ func (s *SecretService) Update(ctx context.Context, userID, secretID string, req
 		result.Secret.EncryptionVersion = *req.EncryptionVersion
 	}
 	if req.Metadata != nil {
+		if err := s.validateAttributes(ctx, s.repos, result.Secret.Type, req.Metadata.Attributes); err != nil {
+			return nil, err
+		}
 		result.Metadata.Title = req.Metadata.Title
 		result.Metadata.Domain = req.Metadata.Domain
 		result.Metadata.Tags = req.Metadata.Tags
+		result.Metadata.Attributes = req.Metadata.Attributes
+		result.Metadata.CustomFields = req.Metadata.CustomFields
+	}
+	if req.Policy != nil {
+		req.Policy.Apply(result.Secret)
+	}
+	log, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Secret.Update(ctx, result.Secret, result.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to update secret: %w", err)
+		}
+		return newAuditLog(ctx, userID, &result.Secret.VaultID, &secretID, audit.ActionUpdate), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	if err := s.repos.Secret.Update(ctx, result.Secret, result.Metadata); err != nil {
-		return nil, fmt.Errorf("failed to update secret: %w", err)
-	}
-	// Log audit
-	s.logAudit(ctx, userID, &result.Secret.VaultID, &secretID, audit.ActionUpdate)
+	s.anomaly.Observe(ctx, log)
 	return s.toSecretResponse(result.Secret, result.Metadata), nil
 }
 
@@ -173,21 +193,30 @@ func (s *SecretService) Delete(ctx context.Context, userID, secretID string) err
 		return fmt.Errorf("failed to get secret: %w", err)
 	}
 	if result == nil {
-		return fmt.Errorf("secret not found")
-	}
-	// Verify vault ownership
-	vault, err := s.repos.Vault.GetByID(ctx, result.Secret.VaultID)
-	if err != nil {
-		return fmt.Errorf("failed to get vault: %w", err)
+		return errs.NewNotFoundError("Secret not found", false, nil)
 	}
-	if vault.UserID != userID {
-		return fmt.Errorf("unauthorized access to secret")
+	if _, err := authorizeVault(ctx, s.repos, userID, result.Secret.VaultID, vaultAccessWrite); err != nil {
+		return err
 	}
-	if err := s.repos.Secret.Delete(ctx, secretID); err != nil {
-		return fmt.Errorf("failed to delete secret: %w", err)
+	// Attachment rows cascade with the secret; collect their blobs first
+	blobKeys, err := s.repos.Attachment.ListStorageKeysBySecretID(ctx, secretID)
+	if err != nil {
+		return fmt.Errorf("failed to list attachments: %w", err)
+	}
+	log, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Secret.Delete(ctx, secretID); err != nil {
+			return nil, fmt.Errorf("failed to delete secret: %w", err)
+		}
+		// The secret no longer exists to be referenced, so it is named in metadata
+		log := newAuditLog(ctx, userID, &result.Secret.VaultID, nil, audit.ActionDelete)
+		log.Metadata = map[string]any{"secretId": secretID}
+		return log, nil
+	})
+	if err != nil {
+		return err
 	}
-	// Log audit
-	s.logAudit(ctx, userID, &result.Secret.VaultID, &secretID, audit.ActionDelete)
+	deleteBlobs(ctx, s.server, blobKeys)
+	s.anomaly.Observe(ctx, log)
 	return nil
 }
 
@@ -195,26 +224,49 @@ func (s *SecretService) toSecretResponse(sec *secret.Secret, meta *secret.Secret
 	return &secret.SecretResponse{
 		ID:                sec.ID.String(),
 		VaultID:           sec.VaultID,
+		FolderID:          sec.FolderID,
 		Type:              sec.Type,
 		EncryptedPayload:  sec.EncryptedPayload,
 		EncryptionVersion: sec.EncryptionVersion,
 		Metadata: secret.SecretMetadataDTO{
-			Title:  meta.Title,
-			Domain: meta.Domain,
-			Tags:   meta.Tags,
+			Title:        meta.Title,
+			Domain:       meta.Domain,
+			Tags:         meta.Tags,
+			Attributes:   meta.Attributes,
+			CustomFields: meta.CustomFields,
 		},
-		LastAccessedAt: sec.LastAccessedAt,
-		CreatedAt:      sec.CreatedAt,
-		UpdatedAt:      sec.UpdatedAt,
+		LastAccessedAt:  sec.LastAccessedAt,
+		Status:          sec.Status(time.Now(), s.expiryConfig().WarningWindow),
+		ExpiresAt:       sec.ExpiresAt,
+		RotateEveryDays: sec.RotateEveryDays,
+		RotatedAt:       sec.RotatedAt,
+		RotationDueAt:   sec.RotationDueAt(),
+		CreatedAt:       sec.CreatedAt,
+		UpdatedAt:       sec.UpdatedAt,
 	}
 }
 
-func (s *SecretService) logAudit(ctx context.Context, userID string, vaultID, secretID *string, action audit.Action) {
-	log := &audit.AuditLog{
-		UserID:   userID,
-		VaultID:  vaultID,
-		SecretID: secretID,
-		Action:   action,
-	}
-	_ = s.repos.Audit.Log(ctx, log)
+// validateAttributes - Check metadata attributes against the schema of the secret type,
+// looking the type up through repos
+func (s *SecretService) validateAttributes(ctx context.Context, repos *repository.Repositories, secretType secret.SecretType, attrs map[string]any) error {
+	def, err := s.types.Get(ctx, repos, secretType)
+	if err != nil {
+		return err
+	}
+	if def == nil {
+		return errs.NewBadRequestError("Unknown secret type "+string(secretType), false, nil, nil, nil)
+	}
+	return s.types.ValidateAttributes(def, attrs)
+}
+
+// logAudit records a read, which has no change to commit alongside it; mutations use withAudit
+func (s *SecretService) logAudit(ctx context.Context, userID string, vaultID, secretID *string, action audit.Action) error {
+	log := newAuditLog(ctx, userID, vaultID, secretID, action)
+	if err := s.repos.WithTx(ctx, func(repos *repository.Repositories) error {
+		return recordAudit(ctx, repos, log)
+	}); err != nil {
+		return err
+	}
+	s.anomaly.Observe(ctx, log)
+	return nil
 }
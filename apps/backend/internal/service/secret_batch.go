@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/google/uuid"
+)
+
+// batchScope carries the repositories bound to one batch item's savepoint
+// and state shared across the items of a batch
+type batchScope struct {
+	userID   string
+	batchID  string
+	repos    *repository.Repositories
+	vaults   map[string]*batchVault
+	blobKeys []string
+}
+
+// errBatchRolledBack rolls back an atomic batch once one of its items failed
+var errBatchRolledBack = errors.New("atomic batch rolled back")
+
+type batchVault struct {
+	vault  *vault.Vault
+	access vaultAccess
+}
+
+// Batch - Run create/update/move/delete/tag operations in one transaction.
+// Each operation runs in its own savepoint and is authorized on its own; a
+// failed operation is reported and skipped, or aborts the batch when Atomic is set.
+func (s *SecretService) Batch(ctx context.Context, userID string, req *secret.BatchRequest) (*secret.BatchResponse, error) {
+	if len(req.Operations) > secret.MaxBatchOperations {
+		return nil, errs.NewBadRequestError(fmt.Sprintf("A batch is limited to %d operations", secret.MaxBatchOperations), false, nil, nil, nil)
+	}
+
+	scope := &batchScope{
+		userID:  userID,
+		batchID: uuid.NewString(),
+		vaults:  make(map[string]*batchVault),
+	}
+	resp := &secret.BatchResponse{Atomic: req.Atomic, Results: make([]*secret.BatchItemResult, len(req.Operations))}
+	summary := newAuditLog(ctx, userID, nil, nil, audit.ActionSecretBatch)
+	_, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		for i := range req.Operations {
+			op := &req.Operations[i]
+			result := &secret.BatchItemResult{Index: i, Op: op.Op, SecretID: op.SecretID}
+			resp.Results[i] = result
+
+			if err := s.runBatchItem(ctx, repos, scope, op, result); err != nil {
+				var httpErr *errs.HTTPError
+				message := "Internal error"
+				if errors.As(err, &httpErr) {
+					message = httpErr.Message
+				} else {
+					s.server.Logger.Error().Err(err).Str("user_id", userID).Str("batch_id", scope.batchID).Int("index", i).Msg("batch operation failed")
+				}
+				result.Status = secret.BatchStatusError
+				result.Error = &message
+				resp.Failed++
+				if req.Atomic {
+					return nil, errBatchRolledBack
+				}
+				continue
+			}
+			result.Status = secret.BatchStatusOK
+			resp.Succeeded++
+		}
+		summary.Metadata = batchSummary(scope, req, resp, true)
+		return summary, nil
+	})
+	if errors.Is(err, errBatchRolledBack) {
+		for _, result := range resp.Results {
+			if result != nil && result.Status == secret.BatchStatusOK {
+				result.Status = secret.BatchStatusRolledBack
+			}
+		}
+		resp.Results = slices.DeleteFunc(resp.Results, func(r *secret.BatchItemResult) bool { return r == nil })
+		resp.Succeeded = 0
+		// The summary of a rolled back batch is recorded on its own
+		_, err = withAudit(ctx, s.repos, func(*repository.Repositories) (*audit.AuditLog, error) {
+			summary.Metadata = batchSummary(scope, req, resp, false)
+			return summary, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to log batch: %w", err)
+		}
+		return resp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run batch: %w", err)
+	}
+	resp.Committed = true
+	deleteBlobs(ctx, s.server, scope.blobKeys)
+	return resp, nil
+}
+
+// batchSummary is the metadata of the audit entry summarizing a batch
+func batchSummary(scope *batchScope, req *secret.BatchRequest, resp *secret.BatchResponse, committed bool) map[string]any {
+	return map[string]any{
+		"batchId":    scope.batchID,
+		"operations": len(req.Operations),
+		"succeeded":  resp.Succeeded,
+		"failed":     resp.Failed,
+		"atomic":     req.Atomic,
+		"committed":  committed,
+	}
+}
+
+// runBatchItem runs one operation inside a savepoint of repos' transaction
+func (s *SecretService) runBatchItem(ctx context.Context, repos *repository.Repositories, scope *batchScope, op *secret.BatchOperation, result *secret.BatchItemResult) error {
+	var blobKeys []string
+	err := repos.WithTx(ctx, func(repos *repository.Repositories) error {
+		scope.repos = repos
+		var err error
+		switch op.Op {
+		case secret.BatchOpCreate:
+			err = s.batchCreate(ctx, scope, op.Create, result)
+		case secret.BatchOpUpdate:
+			err = s.batchUpdate(ctx, scope, *op.SecretID, op.Update)
+		case secret.BatchOpMove:
+			err = s.batchMove(ctx, scope, *op.SecretID, op.Move)
+		case secret.BatchOpDelete:
+			blobKeys, err = s.batchDelete(ctx, scope, *op.SecretID)
+		case secret.BatchOpTag:
+			err = s.batchTag(ctx, scope, *op.SecretID, op.Tags)
+		default:
+			err = errs.NewBadRequestError("Unknown operation", false, nil, nil, nil)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	scope.blobKeys = append(scope.blobKeys, blobKeys...)
+	return nil
+}
+
+func (s *SecretService) batchCreate(ctx context.Context, scope *batchScope, req *secret.CreateSecretRequest, result *secret.BatchItemResult) error {
+	if _, err := s.authorizeBatchVault(ctx, scope, req.VaultID, vaultAccessWrite); err != nil {
+		return err
+	}
+	if err := s.validateAttributes(ctx, scope.repos, req.Type, req.Metadata.Attributes); err != nil {
+		return err
+	}
+	if err := s.checkFolder(ctx, scope.repos, req.FolderID, req.VaultID); err != nil {
+		return err
+	}
+	sec := &secret.Secret{
+		VaultID:           req.VaultID,
+		FolderID:          req.FolderID,
+		Type:              req.Type,
+		EncryptedPayload:  req.EncryptedPayload,
+		EncryptionVersion: req.EncryptionVersion,
+	}
+	if req.Policy != nil {
+		req.Policy.Apply(sec)
+	}
+	meta := &secret.SecretMetadata{
+		Title:        req.Metadata.Title,
+		Domain:       req.Metadata.Domain,
+		Tags:         req.Metadata.Tags,
+		Attributes:   req.Metadata.Attributes,
+		CustomFields: req.Metadata.CustomFields,
+	}
+	if err := scope.repos.Secret.Create(ctx, sec, meta); err != nil {
+		return err
+	}
+	secretID := sec.ID.String()
+	result.SecretID = &secretID
+	return s.logBatchItem(ctx, scope, req.VaultID, secretID, audit.ActionCreate, nil)
+}
+
+func (s *SecretService) batchUpdate(ctx context.Context, scope *batchScope, secretID string, req *secret.UpdateSecretRequest) error {
+	current, err := s.getBatchSecret(ctx, scope, secretID)
+	if err != nil {
+		return err
+	}
+	if req.EncryptedPayload != nil {
+		current.Secret.EncryptedPayload = *req.EncryptedPayload
+	}
+	if req.EncryptionVersion != nil {
+		current.Secret.EncryptionVersion = *req.EncryptionVersion
+	}
+	if req.Metadata != nil {
+		if err := s.validateAttributes(ctx, scope.repos, current.Secret.Type, req.Metadata.Attributes); err != nil {
+			return err
+		}
+		current.Metadata.Title = req.Metadata.Title
+		current.Metadata.Domain = req.Metadata.Domain
+		current.Metadata.Tags = req.Metadata.Tags
+		current.Metadata.Attributes = req.Metadata.Attributes
+		current.Metadata.CustomFields = req.Metadata.CustomFields
+	}
+	if req.Policy != nil {
+		req.Policy.Apply(current.Secret)
+	}
+	if err := scope.repos.Secret.Update(ctx, current.Secret, current.Metadata); err != nil {
+		return err
+	}
+	return s.logBatchItem(ctx, scope, current.Secret.VaultID, secretID, audit.ActionUpdate, nil)
+}
+
+func (s *SecretService) batchMove(ctx context.Context, scope *batchScope, secretID string, req *secret.BatchMove) error {
+	current, err := s.getBatchSecret(ctx, scope, secretID)
+	if err != nil {
+		return err
+	}
+	sec := current.Secret
+	fromVault, fromFolder := sec.VaultID, sec.FolderID
+
+	targetVault := sec.VaultID
+	if req.VaultID != nil {
+		targetVault = *req.VaultID
+	}
+	if err := s.checkFolder(ctx, scope.repos, req.FolderID, targetVault); err != nil {
+		return err
+	}
+
+	if targetVault == sec.VaultID {
+		if err := scope.repos.Secret.SetFolder(ctx, secretID, req.FolderID); err != nil {
+			return err
+		}
+	} else {
+		target, err := s.authorizeBatchVault(ctx, scope, targetVault, vaultAccessWrite)
+		if err != nil {
+			return err
+		}
+		// The payload is encrypted with the source vault's key
+		if req.EncryptedPayload == nil || req.EncryptionVersion == nil {
+			return errs.NewBadRequestError("Moving to another vault requires the payload re-encrypted for that vault", false, nil, nil, nil)
+		}
+		if err := s.checkTransfer(ctx, target, secretID, *req.EncryptionVersion); err != nil {
+			return err
+		}
+		sec.VaultID = targetVault
+		sec.FolderID = req.FolderID
+		sec.EncryptedPayload = *req.EncryptedPayload
+		sec.EncryptionVersion = *req.EncryptionVersion
+		if err := scope.repos.Secret.MoveToVault(ctx, sec); err != nil {
+			return err
+		}
+	}
+
+	metadata := map[string]any{"fromVaultId": fromVault, "toVaultId": targetVault, "fromFolderId": fromFolder, "toFolderId": req.FolderID}
+	if err := s.logBatchItem(ctx, scope, fromVault, secretID, audit.ActionSecretMove, metadata); err != nil {
+		return err
+	}
+	if targetVault != fromVault {
+		return s.logBatchItem(ctx, scope, targetVault, secretID, audit.ActionSecretMove, metadata)
+	}
+	return nil
+}
+
+func (s *SecretService) batchDelete(ctx context.Context, scope *batchScope, secretID string) ([]string, error) {
+	current, err := s.getBatchSecret(ctx, scope, secretID)
+	if err != nil {
+		return nil, err
+	}
+	blobKeys, err := scope.repos.Attachment.ListStorageKeysBySecretID(ctx, secretID)
+	if err != nil {
+		return nil, err
+	}
+	if err := scope.repos.Secret.Delete(ctx, secretID); err != nil {
+		return nil, err
+	}
+	// The secret row is gone by the time the entry is written, so a reference to
+	// it would fail the audit_logs foreign key; name it in metadata instead
+	return blobKeys, s.logBatchItem(ctx, scope, current.Secret.VaultID, "", audit.ActionDelete, map[string]any{"secretId": secretID})
+}
+
+func (s *SecretService) batchTag(ctx context.Context, scope *batchScope, secretID string, req *secret.BatchTags) error {
+	current, err := s.getBatchSecret(ctx, scope, secretID)
+	if err != nil {
+		return err
+	}
+	tags := slices.Clone(current.Metadata.Tags)
+	for _, tag := range req.Add {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	tags = slices.DeleteFunc(tags, func(tag string) bool { return slices.Contains(req.Remove, tag) })
+	if err := scope.repos.Secret.SetTags(ctx, secretID, tags); err != nil {
+		return err
+	}
+	metadata := map[string]any{"tagsAdded": req.Add, "tagsRemoved": req.Remove}
+	return s.logBatchItem(ctx, scope, current.Secret.VaultID, secretID, audit.ActionUpdate, metadata)
+}
+
+// getBatchSecret - Load a secret inside the batch and require write access to its vault
+func (s *SecretService) getBatchSecret(ctx context.Context, scope *batchScope, secretID string) (*repository.SecretWithMetadata, error) {
+	result, err := scope.repos.Secret.GetByID(ctx, secretID)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errs.NewNotFoundError("Secret not found", false, nil)
+	}
+	if _, err := s.authorizeBatchVault(ctx, scope, result.Secret.VaultID, vaultAccessWrite); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// authorizeBatchVault - Require access to a vault, resolving each vault once per batch
+func (s *SecretService) authorizeBatchVault(ctx context.Context, scope *batchScope, vaultID string, required vaultAccess) (*vault.Vault, error) {
+	bv, ok := scope.vaults[vaultID]
+	if !ok {
+		v, access, err := loadVaultAccess(ctx, s.repos, scope.userID, vaultID)
+		if err != nil {
+			return nil, err
+		}
+		bv = &batchVault{vault: v, access: access}
+		scope.vaults[vaultID] = bv
+	}
+	if err := checkVaultAccess(bv.vault, bv.access, required); err != nil {
+		return nil, err
+	}
+	return bv.vault, nil
+}
+
+// checkFolder - Verify through repos that folderID, if set, is a folder of vaultID
+func (s *SecretService) checkFolder(ctx context.Context, repos *repository.Repositories, folderID *string, vaultID string) error {
+	if folderID == nil {
+		return nil
+	}
+	f, err := repos.Folder.GetByID(ctx, *folderID)
+	if err != nil {
+		return fmt.Errorf("failed to get folder: %w", err)
+	}
+	if f == nil || f.VaultID != vaultID {
+		return errs.NewNotFoundError("Folder not found", false, nil)
+	}
+	return nil
+}
+
+// logBatchItem logs one item of a batch; secretID is empty for deleted secrets
+func (s *SecretService) logBatchItem(ctx context.Context, scope *batchScope, vaultID, secretID string, action audit.Action, metadata map[string]any) error {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadata["batchId"] = scope.batchID
+	var secretRef *string
+	if secretID != "" {
+		secretRef = &secretID
+	}
+	log := newAuditLog(ctx, scope.userID, &vaultID, secretRef, action)
+	log.Metadata = metadata
+	return recordAudit(ctx, scope.repos, log)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper to create a user and a context acting as them
+func createTestActor(t *testing.T, ctx context.Context, repos *repository.Repositories, externalID string) (string, context.Context) {
+	t.Helper()
+	userID, err := repos.User.Resolve(ctx, externalID)
+	require.NoError(t, err, "setup: failed to create user")
+	return userID, actor.WithActor(ctx, &actor.Actor{ID: userID, ExternalID: externalID, Type: actor.TypeUser})
+}
+
+// Helper to create a personal vault
+func createTestVault(t *testing.T, ctx context.Context, repos *repository.Repositories, userID, name string) string {
+	t.Helper()
+	v := &vault.Vault{UserID: userID, Name: name, EncryptedKey: []byte("key")}
+	require.NoError(t, repos.Vault.Create(ctx, v), "setup: failed to create vault")
+	return v.ID.String()
+}
+
+// Helper to create a password secret
+func createTestSecret(t *testing.T, ctx context.Context, repos *repository.Repositories, vaultID, title string) string {
+	t.Helper()
+	sec := &secret.Secret{VaultID: vaultID, Type: secret.SecretTypePassword, EncryptedPayload: []byte("payload"), EncryptionVersion: 1}
+	require.NoError(t, repos.Secret.Create(ctx, sec, &secret.SecretMetadata{Title: title}), "setup: failed to create secret")
+	return sec.ID.String()
+}
+
+func newTestSecretService(srv *server.Server, repos *repository.Repositories) *SecretService {
+	return NewSecretService(srv, repos, NewAnomalyService(srv), NewSecretTypeService(srv, repos))
+}
+
+// Helper to require an errs.HTTPError with the given status
+func assertHTTPStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, status, httpErr.Status)
+}
+
+func batchErrors(resp *secret.BatchResponse) map[int]string {
+	errors := make(map[int]string)
+	for _, r := range resp.Results {
+		if r.Error != nil {
+			errors[r.Index] = *r.Error
+		}
+	}
+	return errors
+}
+
+// Test: Each item is authorized on its own; a failed item is skipped and its
+// partial writes undone while the rest commit, and deleted secrets stay in the audit log
+func TestSecretService_Batch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	testDB, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := newTestSecretService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+
+	aliceID, aliceCtx := createTestActor(t, ctx, repos, "user_alice")
+	bobID, _ := createTestActor(t, ctx, repos, "user_bob")
+	own := createTestVault(t, ctx, repos, aliceID, "Personal")
+	shared := createTestVault(t, ctx, repos, bobID, "Shared read-only")
+	private := createTestVault(t, ctx, repos, bobID, "Private")
+	require.NoError(t, repos.VaultMember.Upsert(ctx, &vault.Member{
+		VaultID: shared, MemberType: audit.ActorTypeUser, MemberID: aliceID, Role: vault.MemberRoleRead, GrantedBy: bobID,
+	}))
+
+	doomed := createTestSecret(t, ctx, repos, own, "old")
+	tagged := createTestSecret(t, ctx, repos, own, "tagged")
+	readOnly := createTestSecret(t, ctx, repos, shared, "bob's")
+	hidden := createTestSecret(t, ctx, repos, private, "bob's private")
+	// The secret being deleted already has audit history referencing it
+	require.NoError(t, repos.Audit.Log(ctx, &audit.AuditLog{UserID: aliceID, ActorType: audit.ActorTypeUser, VaultID: &own, SecretID: &doomed, Action: audit.ActionView}))
+
+	// Reject the audit entry of one item so that it fails after its secret write
+	_, err := testDB.Pool.Exec(ctx, `
+		CREATE FUNCTION reject_tagged_audit() RETURNS trigger AS $$
+		BEGIN
+			IF NEW.secret_id = '`+tagged+`' THEN
+				RAISE EXCEPTION 'audit rejected';
+			END IF;
+			RETURN NEW;
+		END $$ LANGUAGE plpgsql;
+		CREATE TRIGGER reject_tagged_audit BEFORE INSERT ON audit_logs
+		FOR EACH ROW EXECUTE FUNCTION reject_tagged_audit();
+	`)
+	require.NoError(t, err)
+
+	create := &secret.CreateSecretRequest{
+		VaultID: own, Type: secret.SecretTypePassword, EncryptedPayload: []byte("new"), EncryptionVersion: 1,
+		Metadata: secret.SecretMetadataDTO{Title: "new"},
+	}
+	rename := &secret.UpdateSecretRequest{Metadata: &secret.SecretMetadataDTO{Title: "renamed"}}
+	resp, err := svc.Batch(aliceCtx, aliceID, &secret.BatchRequest{Operations: []secret.BatchOperation{
+		{Op: secret.BatchOpCreate, Create: create},
+		{Op: secret.BatchOpUpdate, SecretID: &readOnly, Update: rename},
+		{Op: secret.BatchOpUpdate, SecretID: &hidden, Update: rename},
+		{Op: secret.BatchOpTag, SecretID: &tagged, Tags: &secret.BatchTags{Add: []string{"work"}}},
+		{Op: secret.BatchOpDelete, SecretID: &doomed},
+	}})
+	require.NoError(t, err)
+	assert.True(t, resp.Committed)
+	assert.Equal(t, 2, resp.Succeeded)
+	assert.Equal(t, 3, resp.Failed)
+	assert.Equal(t, map[int]string{
+		1: "Insufficient access to this vault",
+		2: "Secret not found",
+		3: "Internal error",
+	}, batchErrors(resp))
+
+	require.NotNil(t, resp.Results[0].SecretID)
+	created, err := repos.Secret.GetByID(ctx, *resp.Results[0].SecretID)
+	require.NoError(t, err)
+	assert.NotNil(t, created)
+	for _, id := range []string{readOnly, hidden} {
+		unchanged, err := repos.Secret.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.NotEqual(t, "renamed", unchanged.Metadata.Title)
+	}
+	// The tag write was rolled back with its savepoint
+	untagged, err := repos.Secret.GetByID(ctx, tagged)
+	require.NoError(t, err)
+	assert.Empty(t, untagged.Metadata.Tags)
+	deleted, err := repos.Secret.GetByID(ctx, doomed)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+
+	logs, err := repos.Audit.ListByUserID(ctx, aliceID, 20)
+	require.NoError(t, err)
+	var deleteLog, summary *audit.AuditLog
+	for _, log := range logs {
+		switch log.Action {
+		case audit.ActionDelete:
+			deleteLog = log
+		case audit.ActionSecretBatch:
+			summary = log
+		}
+	}
+	require.NotNil(t, deleteLog)
+	assert.Nil(t, deleteLog.SecretID)
+	assert.Equal(t, doomed, deleteLog.Metadata["secretId"])
+	require.NotNil(t, summary)
+	assert.Equal(t, true, summary.Metadata["committed"])
+	assert.EqualValues(t, 2, summary.Metadata["succeeded"])
+}
+
+// Test: A failed item of an atomic batch rolls back the items before it and stops the batch
+func TestSecretService_Batch_Atomic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := newTestSecretService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+
+	aliceID, aliceCtx := createTestActor(t, ctx, repos, "user_alice")
+	bobID, _ := createTestActor(t, ctx, repos, "user_bob")
+	own := createTestVault(t, ctx, repos, aliceID, "Personal")
+	private := createTestVault(t, ctx, repos, bobID, "Private")
+	doomed := createTestSecret(t, ctx, repos, own, "old")
+	hidden := createTestSecret(t, ctx, repos, private, "bob's private")
+
+	before, err := repos.Secret.ListByVaultID(ctx, own)
+	require.NoError(t, err)
+
+	resp, err := svc.Batch(aliceCtx, aliceID, &secret.BatchRequest{Atomic: true, Operations: []secret.BatchOperation{
+		{Op: secret.BatchOpCreate, Create: &secret.CreateSecretRequest{
+			VaultID: own, Type: secret.SecretTypePassword, EncryptedPayload: []byte("new"), EncryptionVersion: 1,
+			Metadata: secret.SecretMetadataDTO{Title: "new"},
+		}},
+		{Op: secret.BatchOpDelete, SecretID: &doomed},
+		{Op: secret.BatchOpDelete, SecretID: &hidden},
+		{Op: secret.BatchOpTag, SecretID: &doomed, Tags: &secret.BatchTags{Add: []string{"never"}}},
+	}})
+	require.NoError(t, err)
+	assert.False(t, resp.Committed)
+	assert.Equal(t, 0, resp.Succeeded)
+	assert.Equal(t, 1, resp.Failed)
+	// Items after the failure never ran
+	require.Len(t, resp.Results, 3)
+	assert.Equal(t, secret.BatchStatusRolledBack, resp.Results[0].Status)
+	assert.Equal(t, secret.BatchStatusRolledBack, resp.Results[1].Status)
+	assert.Equal(t, secret.BatchStatusError, resp.Results[2].Status)
+
+	after, err := repos.Secret.ListByVaultID(ctx, own)
+	require.NoError(t, err)
+	assert.Len(t, after, len(before))
+	for _, id := range []string{doomed, hidden} {
+		kept, err := repos.Secret.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.NotNil(t, kept)
+	}
+
+	// Only the summary of the rolled back batch is recorded
+	logs, err := repos.Audit.ListByUserID(ctx, aliceID, 20)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, audit.ActionSecretBatch, logs[0].Action)
+	assert.Equal(t, false, logs[0].Metadata["committed"])
+
+	// Oversized batches are refused outright
+	_, err = svc.Batch(aliceCtx, aliceID, &secret.BatchRequest{Operations: make([]secret.BatchOperation, secret.MaxBatchOperations+1)})
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/lib/email"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/model/outbox"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/hibiken/asynq"
+)
+
+// ListExpiring - List the secrets visible to the user that are expired, due for rotation,
+// or will be within withinDays (default: the configured warning window)
+func (s *SecretService) ListExpiring(ctx context.Context, userID string, req *secret.ListExpiringRequest) ([]*secret.ExpiringSecretResponse, error) {
+	if req.VaultID != nil {
+		if _, err := authorizeVault(ctx, s.repos, userID, *req.VaultID, vaultAccessRead); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	window := s.expiryConfig().WarningWindow
+	if req.WithinDays != nil {
+		window = time.Duration(*req.WithinDays) * 24 * time.Hour
+	}
+	results, err := s.repos.Secret.ListExpiring(ctx, memberTypeFromContext(ctx), userID, activeOrgID(ctx), req.VaultID, now.Add(window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expiring secrets: %w", err)
+	}
+	responses := make([]*secret.ExpiringSecretResponse, 0, len(results))
+	for _, r := range results {
+		sec := r.Secret
+		resp := &secret.ExpiringSecretResponse{
+			ID:              sec.ID.String(),
+			VaultID:         sec.VaultID,
+			Type:            sec.Type,
+			Status:          sec.Status(now, window),
+			ExpiresAt:       sec.ExpiresAt,
+			RotateEveryDays: sec.RotateEveryDays,
+			RotatedAt:       sec.RotatedAt,
+			RotationDueAt:   sec.RotationDueAt(),
+		}
+		if r.Metadata != nil {
+			resp.Title = r.Metadata.Title
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+func (s *SecretService) expiryConfig() *config.ExpiryConfig {
+	if s.server.Config.Expiry != nil {
+		return s.server.Config.Expiry
+	}
+	return config.DefaultExpiryConfig()
+}
+
+func (s *SecretService) registerJobs() {
+	if s.server.Job == nil {
+		return
+	}
+	cfg := s.expiryConfig()
+	s.server.Job.HandleFunc(job.TaskExpiryDigest, unscopedTask(s.handleExpiryDigestTask))
+	if cfg.DigestDisabled {
+		return
+	}
+	if err := s.server.Job.Schedule(cfg.DigestSchedule, job.NewExpiryDigestTask()); err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to schedule expiry digest")
+	}
+}
+
+// handleExpiryDigestTask enqueues one digest email per vault owner with secrets needing attention
+func (s *SecretService) handleExpiryDigestTask(ctx context.Context, _ *asynq.Task) error {
+	now := time.Now()
+	window := s.expiryConfig().WarningWindow
+	rows, err := s.repos.Secret.ListDigest(ctx, now.Add(window))
+	if err != nil {
+		return fmt.Errorf("failed to list expiring secrets: %w", err)
+	}
+	digests := buildExpiryDigests(rows, now, window)
+	for _, d := range digests {
+		m, err := outbox.NewTask(job.TaskExpiryDigestEmail, d)
+		if err != nil {
+			return fmt.Errorf("failed to create expiry digest task: %w", err)
+		}
+		// One digest per owner and day, however often this task is retried
+		key := fmt.Sprintf("expiry_digest:%s:%s", d.UserID, now.UTC().Format("2006-01-02"))
+		m.IdempotencyKey = &key
+		if err := s.repos.Outbox.Enqueue(ctx, m); err != nil {
+			return fmt.Errorf("failed to enqueue expiry digest: %w", err)
+		}
+	}
+	s.server.Logger.Info().
+		Str("type", "expiry_digest").
+		Int("secrets", len(rows)).
+		Int("owners", len(digests)).
+		Msg("Enqueued expiry digests")
+	return nil
+}
+
+var expiryStatusLabels = map[secret.SecretStatus]string{
+	secret.SecretStatusExpired:     "Expired",
+	secret.SecretStatusRotationDue: "Rotation due",
+	secret.SecretStatusExpiring:    "Due soon",
+}
+
+// buildExpiryDigests - Group digest rows, which arrive ordered by owner, into one payload per owner
+func buildExpiryDigests(rows []*secret.ExpiryDigestRow, now time.Time, window time.Duration) []job.ExpiryDigestEmailPayload {
+	var digests []job.ExpiryDigestEmailPayload
+	for _, row := range rows {
+		status := row.Secret.Status(now, window)
+		label, ok := expiryStatusLabels[status]
+		if !ok {
+			continue
+		}
+		var date *time.Time
+		switch status {
+		case secret.SecretStatusExpired:
+			date = row.Secret.ExpiresAt
+		case secret.SecretStatusRotationDue:
+			date = row.Secret.RotationDueAt()
+		default:
+			date = row.Secret.DueAt()
+		}
+		if n := len(digests); n == 0 || digests[n-1].UserID != row.OwnerID {
+			digests = append(digests, job.ExpiryDigestEmailPayload{UserID: row.OwnerID})
+		}
+		d := &digests[len(digests)-1]
+		d.Items = append(d.Items, email.ExpiryItem{
+			Title:     row.Title,
+			VaultName: row.VaultName,
+			Status:    label,
+			Date:      date.Format("2006-01-02"),
+		})
+	}
+	return digests
+}
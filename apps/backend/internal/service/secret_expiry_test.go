@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/lib/email"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretStatus(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+	at := func(days int) *time.Time { d := now.AddDate(0, 0, days); return &d }
+	every := func(n int) *int { return &n }
+
+	tests := []struct {
+		name   string
+		secret secret.Secret
+		want   secret.SecretStatus
+	}{
+		{"no policy", secret.Secret{RotatedAt: now.AddDate(-5, 0, 0)}, secret.SecretStatusActive},
+		{"expires later", secret.Secret{ExpiresAt: at(60)}, secret.SecretStatusActive},
+		{"expires within window", secret.Secret{ExpiresAt: at(10)}, secret.SecretStatusExpiring},
+		{"rotation due within window", secret.Secret{RotateEveryDays: every(90), RotatedAt: now.AddDate(0, 0, -80)}, secret.SecretStatusExpiring},
+		{"rotation overdue", secret.Secret{RotateEveryDays: every(90), RotatedAt: now.AddDate(0, 0, -91)}, secret.SecretStatusRotationDue},
+		{"expired wins over rotation", secret.Secret{ExpiresAt: at(-1), RotateEveryDays: every(1), RotatedAt: now.AddDate(0, 0, -10)}, secret.SecretStatusExpired},
+		{"expires exactly now", secret.Secret{ExpiresAt: at(0)}, secret.SecretStatusExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.secret.Status(now, window))
+		})
+	}
+}
+
+func TestBuildExpiryDigests(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+	at := func(days int) *time.Time { d := now.AddDate(0, 0, days); return &d }
+	every := func(n int) *int { return &n }
+	rows := []*secret.ExpiryDigestRow{
+		{OwnerID: "u1", VaultName: "Work", Title: "db", Secret: &secret.Secret{ExpiresAt: at(-2)}},
+		{OwnerID: "u1", VaultName: "Home", Title: "wifi", Secret: &secret.Secret{RotateEveryDays: every(30), RotatedAt: now.AddDate(0, 0, -40)}},
+		// Rotated since the query ran: nothing to report
+		{OwnerID: "u2", VaultName: "Work", Title: "fresh", Secret: &secret.Secret{RotateEveryDays: every(365), RotatedAt: now}},
+		{OwnerID: "u3", VaultName: "Work", Title: "api", Secret: &secret.Secret{ExpiresAt: at(5)}},
+	}
+
+	digests := buildExpiryDigests(rows, now, window)
+
+	if assert.Len(t, digests, 2) {
+		assert.Equal(t, "u1", digests[0].UserID)
+		assert.Equal(t, []email.ExpiryItem{
+			{Title: "db", VaultName: "Work", Status: "Expired", Date: "2026-05-30"},
+			{Title: "wifi", VaultName: "Home", Status: "Rotation due", Date: "2026-05-22"},
+		}, digests[0].Items)
+		assert.Equal(t, "u3", digests[1].UserID)
+		assert.Equal(t, []email.ExpiryItem{{Title: "api", VaultName: "Work", Status: "Due soon", Date: "2026-06-06"}}, digests[1].Items)
+	}
+}
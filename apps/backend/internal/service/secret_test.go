@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: Secrets in vaults the caller cannot reach are not found; read-only
+// members can read but not change them
+func TestSecretService_Access(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := newTestSecretService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+
+	ownerID, ownerCtx := createTestActor(t, ctx, repos, "user_owner")
+	readerID, readerCtx := createTestActor(t, ctx, repos, "user_reader")
+	strangerID, strangerCtx := createTestActor(t, ctx, repos, "user_stranger")
+	vaultID := createTestVault(t, ctx, repos, ownerID, "Shared")
+	require.NoError(t, repos.VaultMember.Upsert(ctx, &vault.Member{
+		VaultID: vaultID, MemberType: audit.ActorTypeUser, MemberID: readerID, Role: vault.MemberRoleRead, GrantedBy: ownerID,
+	}))
+	secretID := createTestSecret(t, ctx, repos, vaultID, "GitHub")
+
+	_, err := svc.GetByID(ownerCtx, ownerID, uuid.NewString())
+	assertHTTPStatus(t, err, http.StatusNotFound)
+	_, err = svc.GetByID(strangerCtx, strangerID, secretID)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+	_, err = svc.List(strangerCtx, strangerID, vaultID)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+	assertHTTPStatus(t, svc.Delete(strangerCtx, strangerID, secretID), http.StatusNotFound)
+
+	got, err := svc.GetByID(readerCtx, readerID, secretID)
+	require.NoError(t, err)
+	assert.Equal(t, "GitHub", got.Metadata.Title)
+	listed, err := svc.List(readerCtx, readerID, vaultID)
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+	_, err = svc.Update(readerCtx, readerID, secretID, &secret.UpdateSecretRequest{EncryptionVersion: tt.Ptr(2)})
+	assertHTTPStatus(t, err, http.StatusForbidden)
+	assertHTTPStatus(t, svc.Delete(readerCtx, readerID, secretID), http.StatusForbidden)
+	_, err = svc.Create(readerCtx, readerID, &secret.CreateSecretRequest{
+		VaultID: vaultID, Type: secret.SecretTypePassword, EncryptedPayload: []byte("payload"), EncryptionVersion: 1,
+		Metadata: secret.SecretMetadataDTO{Title: "AWS"},
+	})
+	assertHTTPStatus(t, err, http.StatusForbidden)
+
+	require.NoError(t, svc.Delete(ownerCtx, ownerID, secretID))
+	assertHTTPStatus(t, svc.Delete(ownerCtx, ownerID, secretID), http.StatusNotFound)
+}
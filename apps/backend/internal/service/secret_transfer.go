@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+)
+
+// Transfer - Move or copy a secret to another vault with its payload re-encrypted for that vault.
+// A move keeps the secret's ID, metadata and audit history; a copy creates a new secret
+// with the same metadata. Both vaults are audited in the same transaction as the change.
+func (s *SecretService) Transfer(ctx context.Context, userID, secretID string, req *secret.TransferSecretRequest) (*secret.SecretResponse, error) {
+	result, err := s.repos.Secret.GetByID(ctx, secretID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	if result == nil {
+		return nil, errs.NewNotFoundError("Secret not found", false, nil)
+	}
+	sec, meta := result.Secret, result.Metadata
+	fromVault, fromFolder := sec.VaultID, sec.FolderID
+
+	// Copying only reads the source secret
+	sourceAccess := vaultAccessWrite
+	if req.Mode == secret.TransferModeCopy {
+		sourceAccess = vaultAccessRead
+	}
+	if _, err := authorizeVault(ctx, s.repos, userID, fromVault, sourceAccess); err != nil {
+		return nil, err
+	}
+	if req.Mode == secret.TransferModeMove && req.VaultID == fromVault {
+		return nil, errs.NewBadRequestError("The secret is already in this vault; use PUT /api/secrets/:id/folder to change its folder", false, nil, nil, nil)
+	}
+	target, err := authorizeVault(ctx, s.repos, userID, req.VaultID, vaultAccessWrite)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTransfer(ctx, target, secretID, req.EncryptionVersion); err != nil {
+		return nil, err
+	}
+	if err := s.checkFolder(ctx, s.repos, req.FolderID, req.VaultID); err != nil {
+		return nil, err
+	}
+
+	err = s.repos.WithTx(ctx, func(repos *repository.Repositories) error {
+		var sourceLog, targetLog *audit.AuditLog
+		switch req.Mode {
+		case secret.TransferModeMove:
+			sec.VaultID = req.VaultID
+			sec.FolderID = req.FolderID
+			sec.EncryptedPayload = req.EncryptedPayload
+			sec.EncryptionVersion = req.EncryptionVersion
+			if err := repos.Secret.MoveToVault(ctx, sec); err != nil {
+				return fmt.Errorf("failed to move secret: %w", err)
+			}
+			metadata := map[string]any{"fromVaultId": fromVault, "toVaultId": req.VaultID, "fromFolderId": fromFolder, "toFolderId": req.FolderID}
+			sourceLog = newAuditLog(ctx, userID, &fromVault, &secretID, audit.ActionSecretMove)
+			sourceLog.Metadata = metadata
+			targetLog = newAuditLog(ctx, userID, &req.VaultID, &secretID, audit.ActionSecretMove)
+			targetLog.Metadata = metadata
+		case secret.TransferModeCopy:
+			copied := &secret.Secret{
+				VaultID:           req.VaultID,
+				FolderID:          req.FolderID,
+				Type:              sec.Type,
+				EncryptedPayload:  req.EncryptedPayload,
+				EncryptionVersion: req.EncryptionVersion,
+				ExpiresAt:         sec.ExpiresAt,
+				RotateEveryDays:   sec.RotateEveryDays,
+			}
+			copiedMeta := &secret.SecretMetadata{
+				Title:        meta.Title,
+				Domain:       meta.Domain,
+				Tags:         meta.Tags,
+				Attributes:   meta.Attributes,
+				CustomFields: meta.CustomFields,
+			}
+			if err := repos.Secret.Create(ctx, copied, copiedMeta); err != nil {
+				return fmt.Errorf("failed to copy secret: %w", err)
+			}
+			copyID := copied.ID.String()
+			sourceLog = newAuditLog(ctx, userID, &fromVault, &secretID, audit.ActionSecretCopy)
+			sourceLog.Metadata = map[string]any{"copyId": copyID, "toVaultId": req.VaultID}
+			targetLog = newAuditLog(ctx, userID, &req.VaultID, &copyID, audit.ActionSecretCopy)
+			targetLog.Metadata = map[string]any{"sourceId": secretID, "fromVaultId": fromVault}
+			sec, meta = copied, copiedMeta
+		}
+
+		for _, log := range []*audit.AuditLog{sourceLog, targetLog} {
+			if err := recordAudit(ctx, repos, log); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.toSecretResponse(sec, meta), nil
+}
+
+// checkTransfer - Verify that a secret can be re-encrypted into target: the payload must use
+// the target vault's key version, and attachments (encrypted with the source key) cannot follow
+func (s *SecretService) checkTransfer(ctx context.Context, target *vault.Vault, secretID string, encryptionVersion int) error {
+	if target.KeyEncryptionVersion != nil && *target.KeyEncryptionVersion != encryptionVersion {
+		return errs.NewBadRequestError(fmt.Sprintf("The payload must be encrypted for the target vault's key version %d", *target.KeyEncryptionVersion), false, nil, nil, nil)
+	}
+	keys, err := s.repos.Attachment.ListStorageKeysBySecretID(ctx, secretID)
+	if err != nil {
+		return fmt.Errorf("failed to list attachments: %w", err)
+	}
+	if len(keys) > 0 {
+		return errs.NewBadRequestError("Secrets with attachments cannot be moved or copied to another vault; remove the attachments first", false, nil, nil, nil)
+	}
+	return nil
+}
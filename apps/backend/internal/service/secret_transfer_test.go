@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper to list the audit actions recorded for a vault
+func vaultAuditActions(t *testing.T, ctx context.Context, repos *repository.Repositories, userID, vaultID string) []audit.Action {
+	t.Helper()
+	logs, err := repos.Audit.ListByUserID(ctx, userID, 100)
+	require.NoError(t, err)
+	var actions []audit.Action
+	for _, log := range logs {
+		if log.VaultID != nil && *log.VaultID == vaultID {
+			actions = append(actions, log.Action)
+		}
+	}
+	return actions
+}
+
+// Test: A move keeps the secret's ID and takes the payload re-encrypted for the target vault;
+// a copy leaves the source alone. Both vaults are audited.
+func TestSecretService_Transfer(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := newTestSecretService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+
+	userID, userCtx := createTestActor(t, ctx, repos, "user_alice")
+	sourceID := createTestVault(t, ctx, repos, userID, "Personal")
+	target := &vault.Vault{UserID: userID, Name: "Work", EncryptedKey: []byte("key"), KeyEncryptionVersion: tt.Ptr(2)}
+	require.NoError(t, repos.Vault.Create(ctx, target))
+	targetID := target.ID.String()
+
+	movedID := createTestSecret(t, ctx, repos, sourceID, "GitHub")
+	copiedID := createTestSecret(t, ctx, repos, sourceID, "AWS")
+
+	// The payload must be encrypted for the target vault's key
+	_, err := svc.Transfer(userCtx, userID, movedID, &secret.TransferSecretRequest{
+		Mode: secret.TransferModeMove, VaultID: targetID, EncryptedPayload: []byte("reencrypted"), EncryptionVersion: 1,
+	})
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+	_, err = svc.Transfer(userCtx, userID, movedID, &secret.TransferSecretRequest{
+		Mode: secret.TransferModeMove, VaultID: sourceID, EncryptedPayload: []byte("reencrypted"), EncryptionVersion: 1,
+	})
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+
+	moved, err := svc.Transfer(userCtx, userID, movedID, &secret.TransferSecretRequest{
+		Mode: secret.TransferModeMove, VaultID: targetID, EncryptedPayload: []byte("reencrypted"), EncryptionVersion: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, movedID, moved.ID)
+	stored, err := repos.Secret.GetByID(ctx, movedID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, targetID, stored.Secret.VaultID)
+	assert.Equal(t, []byte("reencrypted"), stored.Secret.EncryptedPayload)
+	assert.Equal(t, 2, stored.Secret.EncryptionVersion)
+	assert.Equal(t, "GitHub", stored.Metadata.Title)
+
+	copied, err := svc.Transfer(userCtx, userID, copiedID, &secret.TransferSecretRequest{
+		Mode: secret.TransferModeCopy, VaultID: targetID, EncryptedPayload: []byte("copy"), EncryptionVersion: 2,
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, copiedID, copied.ID)
+	source, err := repos.Secret.GetByID(ctx, copiedID)
+	require.NoError(t, err)
+	require.NotNil(t, source)
+	assert.Equal(t, sourceID, source.Secret.VaultID)
+	assert.Equal(t, []byte("payload"), source.Secret.EncryptedPayload)
+	dup, err := repos.Secret.GetByID(ctx, copied.ID)
+	require.NoError(t, err)
+	require.NotNil(t, dup)
+	assert.Equal(t, targetID, dup.Secret.VaultID)
+	assert.Equal(t, []byte("copy"), dup.Secret.EncryptedPayload)
+	assert.Equal(t, "AWS", dup.Metadata.Title)
+
+	// Each transfer is recorded in both vaults
+	want := []audit.Action{audit.ActionSecretMove, audit.ActionSecretCopy}
+	assert.ElementsMatch(t, want, vaultAuditActions(t, ctx, repos, userID, sourceID))
+	assert.ElementsMatch(t, want, vaultAuditActions(t, ctx, repos, userID, targetID))
+}
+
+// Test: Transfers need read access to copy from and write access to the target vault
+func TestSecretService_Transfer_Access(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := newTestSecretService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+
+	ownerID, _ := createTestActor(t, ctx, repos, "user_owner")
+	readerID, readerCtx := createTestActor(t, ctx, repos, "user_reader")
+	sharedID := createTestVault(t, ctx, repos, ownerID, "Shared")
+	ownVaultID := createTestVault(t, ctx, repos, readerID, "Own")
+	require.NoError(t, repos.VaultMember.Upsert(ctx, &vault.Member{
+		VaultID: sharedID, MemberType: audit.ActorTypeUser, MemberID: readerID, Role: vault.MemberRoleRead, GrantedBy: ownerID,
+	}))
+	secretID := createTestSecret(t, ctx, repos, sharedID, "GitHub")
+
+	// A reader can copy out but not move out or copy in
+	_, err := svc.Transfer(readerCtx, readerID, secretID, &secret.TransferSecretRequest{
+		Mode: secret.TransferModeMove, VaultID: ownVaultID, EncryptedPayload: []byte("payload"), EncryptionVersion: 1,
+	})
+	assertHTTPStatus(t, err, http.StatusForbidden)
+	copied, err := svc.Transfer(readerCtx, readerID, secretID, &secret.TransferSecretRequest{
+		Mode: secret.TransferModeCopy, VaultID: ownVaultID, EncryptedPayload: []byte("payload"), EncryptionVersion: 1,
+	})
+	require.NoError(t, err)
+	_, err = svc.Transfer(readerCtx, readerID, copied.ID, &secret.TransferSecretRequest{
+		Mode: secret.TransferModeCopy, VaultID: sharedID, EncryptedPayload: []byte("payload"), EncryptionVersion: 1,
+	})
+	assertHTTPStatus(t, err, http.StatusForbidden)
+}
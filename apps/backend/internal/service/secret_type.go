@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/go-playground/validator/v10"
+)
+
+// Types added to the table are picked up within this interval without a restart
+const secretTypeCacheTTL = 5 * time.Minute
+
+var hostnameRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// SecretTypeService is the registry of secret types backed by the secret_types table
+type SecretTypeService struct {
+	server *server.Server
+	repos  *repository.Repositories
+
+	mu       sync.RWMutex
+	types    map[secret.SecretType]*secret.TypeDefinition
+	patterns map[*secret.TypeDefinition]fieldPatterns
+	loadedAt time.Time
+}
+
+// fieldPatterns holds the compiled Pattern of each field of a type that has one;
+// a nil entry marks a pattern that does not compile
+type fieldPatterns map[string]*regexp.Regexp
+
+func NewSecretTypeService(s *server.Server, repos *repository.Repositories) *SecretTypeService {
+	return &SecretTypeService{server: s, repos: repos}
+}
+
+// List - List all registered secret types
+func (s *SecretTypeService) List(ctx context.Context) ([]*secret.TypeDefinition, error) {
+	types, err := s.registry(ctx, s.repos)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*secret.TypeDefinition, 0, len(types))
+	for _, t := range types {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Get - Get a secret type by name, or nil if it is not registered. A stale cache
+// is reloaded through repos, so callers inside a transaction pass its repositories.
+func (s *SecretTypeService) Get(ctx context.Context, repos *repository.Repositories, name secret.SecretType) (*secret.TypeDefinition, error) {
+	types, err := s.registry(ctx, repos)
+	if err != nil {
+		return nil, err
+	}
+	return types[name], nil
+}
+
+// ValidateTag implements the "secret_type" validation tag
+func (s *SecretTypeService) ValidateTag(fl validator.FieldLevel) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	def, err := s.Get(ctx, s.repos, secret.SecretType(fl.Field().String()))
+	if err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to load secret types")
+		return false
+	}
+	return def != nil
+}
+
+// ValidateAttributes - Check metadata attributes against the schema of a secret type
+func (s *SecretTypeService) ValidateAttributes(def *secret.TypeDefinition, attrs map[string]any) error {
+	s.mu.RLock()
+	patterns, ok := s.patterns[def]
+	s.mu.RUnlock()
+	if !ok {
+		// def did not come from the current registry
+		patterns = compilePatterns(def)
+	}
+	if fieldErrors := validateAttributes(def, patterns, attrs); len(fieldErrors) > 0 {
+		return errs.NewBadRequestError("Invalid attributes for secret type "+string(def.Name), true, nil, fieldErrors, nil)
+	}
+	return nil
+}
+
+// registry returns the cached types, reloading them with repos once the cache is
+// stale. A failed reload keeps serving the previous types if there are any.
+func (s *SecretTypeService) registry(ctx context.Context, repos *repository.Repositories) (map[secret.SecretType]*secret.TypeDefinition, error) {
+	s.mu.RLock()
+	types, loadedAt := s.types, s.loadedAt
+	s.mu.RUnlock()
+	if types != nil && time.Since(loadedAt) < secretTypeCacheTTL {
+		return types, nil
+	}
+
+	list, err := repos.SecretType.List(ctx)
+	if err != nil {
+		if types != nil {
+			s.server.Logger.Warn().Err(err).Msg("failed to reload secret types, using cached types")
+			return types, nil
+		}
+		return nil, fmt.Errorf("failed to load secret types: %w", err)
+	}
+	types = make(map[secret.SecretType]*secret.TypeDefinition, len(list))
+	patterns := make(map[*secret.TypeDefinition]fieldPatterns, len(list))
+	for _, t := range list {
+		types[t.Name] = t
+		patterns[t] = compilePatterns(t)
+		for name, re := range patterns[t] {
+			if re == nil {
+				s.server.Logger.Warn().Str("type", string(t.Name)).Str("field", name).Msg("invalid secret type pattern, rejecting all values")
+			}
+		}
+	}
+	s.mu.Lock()
+	s.types, s.patterns, s.loadedAt = types, patterns, time.Now()
+	s.mu.Unlock()
+	return types, nil
+}
+
+// compilePatterns compiles the field patterns of def once, so values are not
+// matched against a freshly compiled regex each time
+func compilePatterns(def *secret.TypeDefinition) fieldPatterns {
+	patterns := make(fieldPatterns)
+	for _, f := range def.Fields {
+		if f.Pattern == "" {
+			continue
+		}
+		// Compile returns nil for an invalid pattern, which then matches nothing
+		re, _ := regexp.Compile(f.Pattern)
+		patterns[f.Name] = re
+	}
+	return patterns
+}
+
+func validateAttributes(def *secret.TypeDefinition, patterns fieldPatterns, attrs map[string]any) []errs.FieldError {
+	var fieldErrors []errs.FieldError
+	addError := func(name, msg string) {
+		fieldErrors = append(fieldErrors, errs.FieldError{Field: "metadata.attributes." + name, Error: msg})
+	}
+
+	known := make(map[string]bool, len(def.Fields))
+	for _, f := range def.Fields {
+		known[f.Name] = true
+		value, ok := attrs[f.Name]
+		if !ok || value == nil || value == "" {
+			if f.Required {
+				addError(f.Name, "is required")
+			}
+			continue
+		}
+		if msg := validateAttribute(&f, patterns, value); msg != "" {
+			addError(f.Name, msg)
+		}
+	}
+	for name := range attrs {
+		if !known[name] {
+			addError(name, fmt.Sprintf("is not an attribute of %s secrets", def.Name))
+		}
+	}
+	sort.Slice(fieldErrors, func(i, j int) bool { return fieldErrors[i].Field < fieldErrors[j].Field })
+	return fieldErrors
+}
+
+// validateAttribute returns a message describing why value does not match f, or ""
+func validateAttribute(f *secret.FieldSchema, patterns fieldPatterns, value any) string {
+	if f.Kind == secret.FieldKindInteger {
+		// JSON numbers decode as float64
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return "must be an integer"
+		}
+		if f.Min != nil && int64(n) < *f.Min {
+			return fmt.Sprintf("must be at least %d", *f.Min)
+		}
+		if f.Max != nil && int64(n) > *f.Max {
+			return fmt.Sprintf("must not exceed %d", *f.Max)
+		}
+		return ""
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return "must be a string"
+	}
+	if f.MaxLength > 0 && len(str) > f.MaxLength {
+		return fmt.Sprintf("must not exceed %d characters", f.MaxLength)
+	}
+	if f.Pattern != "" {
+		if re := patterns[f.Name]; re == nil || !re.MatchString(str) {
+			return "has an invalid format"
+		}
+	}
+	switch f.Kind {
+	case secret.FieldKindURL:
+		u, err := url.ParseRequestURI(str)
+		if err != nil || u.Host == "" {
+			return "must be a valid URL"
+		}
+	case secret.FieldKindHostname:
+		if net.ParseIP(str) == nil && !hostnameRegex.MatchString(str) {
+			return "must be a valid hostname or IP address"
+		}
+	case secret.FieldKindEmail:
+		if _, err := mail.ParseAddress(str); err != nil {
+			return "must be a valid email address"
+		}
+	}
+	return ""
+}
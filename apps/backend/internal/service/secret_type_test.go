@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/model/secret"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAttributes(t *testing.T) {
+	minPort, maxPort := int64(1), int64(65535)
+	def := &secret.TypeDefinition{
+		Name: secret.SecretTypeDatabase,
+		Fields: []secret.FieldSchema{
+			{Name: "host", Kind: secret.FieldKindHostname, Required: true, MaxLength: 255},
+			{Name: "port", Kind: secret.FieldKindInteger, Min: &minPort, Max: &maxPort},
+			{Name: "url", Kind: secret.FieldKindURL},
+			{Name: "last4", Kind: secret.FieldKindText, Pattern: "^[0-9]{4}$"},
+			{Name: "broken", Kind: secret.FieldKindText, Pattern: "(["},
+		},
+	}
+	patterns := compilePatterns(def)
+	assert.Len(t, patterns, 2)
+	assert.Nil(t, patterns["broken"])
+
+	tests := []struct {
+		name   string
+		attrs  map[string]any
+		fields []string
+	}{
+		{"valid", map[string]any{"host": "db.internal", "port": float64(5432), "url": "https://example.com", "last4": "4242"}, nil},
+		{"ip host", map[string]any{"host": "10.0.0.5"}, nil},
+		{"missing required", map[string]any{"port": float64(5432)}, []string{"metadata.attributes.host"}},
+		{"nil attributes", nil, []string{"metadata.attributes.host"}},
+		{"bad values", map[string]any{"host": "not a host", "port": 5432.5, "url": "example", "last4": "42"}, []string{
+			"metadata.attributes.host", "metadata.attributes.last4", "metadata.attributes.port", "metadata.attributes.url",
+		}},
+		{"out of range", map[string]any{"host": "db", "port": float64(70000)}, []string{"metadata.attributes.port"}},
+		{"unknown attribute", map[string]any{"host": "db", "password": "hunter2"}, []string{"metadata.attributes.password"}},
+		{"invalid pattern", map[string]any{"host": "db", "broken": "anything"}, []string{"metadata.attributes.broken"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields []string
+			for _, fe := range validateAttributes(def, patterns, tt.attrs) {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/serviceaccount"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+)
+
+// serviceAccountTokenTTL bounds the lifetime of tokens issued by the client-credentials exchange
+const serviceAccountTokenTTL = 15 * time.Minute
+
+type ServiceAccountService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewServiceAccountService(s *server.Server, repos *repository.Repositories) *ServiceAccountService {
+	return &ServiceAccountService{server: s, repos: repos}
+}
+
+// Create - Create a service account in the caller's active organization.
+// The returned client secret is not stored and cannot be retrieved again.
+func (s *ServiceAccountService) Create(ctx context.Context, userID string, req *serviceaccount.CreateServiceAccountRequest) (*serviceaccount.ServiceAccountCredentialsResponse, error) {
+	a, ok := actor.FromContext(ctx)
+	if !ok || !a.IsOrgAdmin() {
+		return nil, errs.NewForbiddenError("Only organization admins can manage service accounts", false)
+	}
+	clientSecret, err := newOpaqueToken(serviceaccount.ClientSecretPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate client secret: %w", err)
+	}
+	sa := &serviceaccount.ServiceAccount{
+		OrgID:            a.OrgID,
+		Name:             req.Name,
+		Description:      req.Description,
+		CreatedBy:        userID,
+		ClientSecretHash: hashToken(clientSecret),
+	}
+	if err := s.repos.ServiceAccount.Create(ctx, sa); err != nil {
+		return nil, fmt.Errorf("failed to create service account: %w", err)
+	}
+	return &serviceaccount.ServiceAccountCredentialsResponse{
+		ServiceAccountResponse: *serviceaccount.ToServiceAccountResponse(sa),
+		ClientID:               sa.ID.String(),
+		ClientSecret:           clientSecret,
+	}, nil
+}
+
+// List - List service accounts in the caller's active organization
+func (s *ServiceAccountService) List(ctx context.Context) ([]*serviceaccount.ServiceAccountResponse, error) {
+	a, ok := actor.FromContext(ctx)
+	if !ok || !a.IsOrgAdmin() {
+		return nil, errs.NewForbiddenError("Only organization admins can manage service accounts", false)
+	}
+	accounts, err := s.repos.ServiceAccount.ListByOrgID(ctx, a.OrgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	responses := make([]*serviceaccount.ServiceAccountResponse, len(accounts))
+	for i, sa := range accounts {
+		responses[i] = serviceaccount.ToServiceAccountResponse(sa)
+	}
+	return responses, nil
+}
+
+// Disable - Disable a service account, revoking its tokens and vault memberships
+func (s *ServiceAccountService) Disable(ctx context.Context, serviceAccountID string) error {
+	a, ok := actor.FromContext(ctx)
+	if !ok || !a.IsOrgAdmin() {
+		return errs.NewForbiddenError("Only organization admins can manage service accounts", false)
+	}
+	sa, err := s.repos.ServiceAccount.GetByID(ctx, serviceAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get service account: %w", err)
+	}
+	if sa == nil || sa.OrgID != a.OrgID {
+		return errs.NewNotFoundError("Service account not found", false, nil)
+	}
+	if err := s.repos.ServiceAccount.Disable(ctx, serviceAccountID); err != nil {
+		return fmt.Errorf("failed to disable service account: %w", err)
+	}
+	return nil
+}
+
+// IssueToken - Exchange client credentials for a short-lived access token
+func (s *ServiceAccountService) IssueToken(ctx context.Context, req *serviceaccount.TokenRequest) (*serviceaccount.TokenResponse, error) {
+	invalid := errs.NewUnauthorizedError("Invalid client credentials", false)
+	sa, err := s.repos.ServiceAccount.GetByID(ctx, req.ClientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+	if sa == nil || sa.DisabledAt != nil {
+		return nil, invalid
+	}
+	if subtle.ConstantTimeCompare(hashToken(req.ClientSecret), sa.ClientSecretHash) != 1 {
+		return nil, invalid
+	}
+	accessToken, err := newOpaqueToken(serviceaccount.AccessTokenPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	token := &serviceaccount.Token{
+		ServiceAccountID: sa.ID.String(),
+		TokenHash:        hashToken(accessToken),
+		ExpiresAt:        time.Now().Add(serviceAccountTokenTTL),
+	}
+	if err := s.repos.ServiceAccount.CreateToken(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to store access token: %w", err)
+	}
+	return &serviceaccount.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(serviceAccountTokenTTL.Seconds()),
+	}, nil
+}
+
+// Authenticate - Resolve a bearer token to its service account, or nil if invalid
+func (s *ServiceAccountService) Authenticate(ctx context.Context, token string) (*serviceaccount.ServiceAccount, error) {
+	if !strings.HasPrefix(token, serviceaccount.AccessTokenPrefix) {
+		return nil, nil
+	}
+	sa, err := s.repos.ServiceAccount.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up access token: %w", err)
+	}
+	if sa == nil {
+		return nil, nil
+	}
+	_ = s.repos.ServiceAccount.UpdateLastUsed(ctx, sa.ID.String())
+	return sa, nil
+}
+
+// newOpaqueToken returns prefix followed by 32 random bytes, base64url encoded
+func newOpaqueToken(prefix string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken hashes a high-entropy credential for storage and lookup
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
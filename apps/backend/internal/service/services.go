@@ -7,20 +7,49 @@ import (
 )
 
 type Services struct {
-	Auth   *AuthService
-	Job    *job.JobService
-	Vault  *VaultService
-	Secret *SecretService
-	Device *DeviceService
+	Auth           *AuthService
+	User           *UserService
+	Job            *job.JobService
+	Vault          *VaultService
+	Secret         *SecretService
+	Device         *DeviceService
+	ServiceAccount *ServiceAccountService
+	Audit          *AuditService
+	Anomaly        *AnomalyService
+	Share          *ShareService
+	Attachment     *AttachmentService
+	SecretType     *SecretTypeService
+	Folder         *FolderService
+	PasswordHealth *PasswordHealthService
+	Breach         *BreachService
+	Outbox         *OutboxService
+	Webhook        *WebhookService
+	AuditForward   *AuditForwardService
 }
 
 func NewServices(s *server.Server, repos *repository.Repositories) (*Services, error) {
 	authService := NewAuthService(s)
+	userService := NewUserService(s, repos)
+	anomalyService := NewAnomalyService(s)
+	secretTypeService := NewSecretTypeService(s, repos)
 	return &Services{
-		Job:    s.Job,
-		Auth:   authService,
-		Vault:  NewVaultService(s, repos),
-		Secret: NewSecretService(s, repos),
-		Device: NewDeviceService(s, repos),
+		Job:            s.Job,
+		Auth:           authService,
+		User:           userService,
+		Vault:          NewVaultService(s, repos, userService),
+		Secret:         NewSecretService(s, repos, anomalyService, secretTypeService),
+		Device:         NewDeviceService(s, repos),
+		ServiceAccount: NewServiceAccountService(s, repos),
+		Audit:          NewAuditService(s, repos),
+		Anomaly:        anomalyService,
+		Share:          NewShareService(s, repos),
+		Attachment:     NewAttachmentService(s, repos),
+		SecretType:     secretTypeService,
+		Folder:         NewFolderService(s, repos),
+		PasswordHealth: NewPasswordHealthService(s, repos),
+		Breach:         NewBreachService(s, repos),
+		Outbox:         NewOutboxService(s, repos),
+		Webhook:        NewWebhookService(s, repos),
+		AuditForward:   NewAuditForwardService(s, repos),
 	}, nil
 }
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/share"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/hibiken/asynq"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultShareMaxViews = 1
+	defaultShareLifetime = 24 * time.Hour
+	// maxSharePasswordAttempts wrong passwords purge a share
+	maxSharePasswordAttempts = 5
+)
+
+type ShareService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewShareService(s *server.Server, repos *repository.Repositories) *ShareService {
+	svc := &ShareService{server: s, repos: repos}
+	svc.registerJobs()
+	return svc
+}
+
+// Create - Create a share link for a client-encrypted payload
+func (s *ShareService) Create(ctx context.Context, userID string, req *share.CreateShareRequest) (*share.ShareResponse, error) {
+	if actor.IsServiceAccount(ctx) {
+		return nil, errs.NewForbiddenError("Service accounts cannot create share links", false)
+	}
+	sh := &share.Share{
+		OwnerID:          userID,
+		EncryptedPayload: req.EncryptedPayload,
+		MaxViews:         defaultShareMaxViews,
+		ExpiresAt:        time.Now().Add(defaultShareLifetime),
+	}
+	if req.MaxViews != nil {
+		sh.MaxViews = *req.MaxViews
+	}
+	if req.ExpiresIn != nil {
+		sh.ExpiresAt = time.Now().Add(time.Duration(*req.ExpiresIn) * time.Second)
+	}
+	if req.Password != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash share password: %w", err)
+		}
+		sh.PasswordHash = hash
+	}
+	_, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Share.Create(ctx, sh); err != nil {
+			return nil, fmt.Errorf("failed to create share: %w", err)
+		}
+		return shareAuditLog(newAuditLog(ctx, userID, nil, nil, audit.ActionShareCreate), sh), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return share.ToShareResponse(sh), nil
+}
+
+// List - List share links created by the caller
+func (s *ShareService) List(ctx context.Context, userID string) ([]*share.ShareResponse, error) {
+	shares, err := s.repos.Share.ListByOwnerID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shares: %w", err)
+	}
+	responses := make([]*share.ShareResponse, len(shares))
+	for i, sh := range shares {
+		responses[i] = share.ToShareResponse(sh)
+	}
+	return responses, nil
+}
+
+// Revoke - Purge a share link before it is used up
+func (s *ShareService) Revoke(ctx context.Context, userID, shareID string) error {
+	_, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		revoked, err := repos.Share.Revoke(ctx, shareID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to revoke share: %w", err)
+		}
+		if !revoked {
+			return nil, errs.NewNotFoundError("Share not found", false, nil)
+		}
+		log := newAuditLog(ctx, userID, nil, nil, audit.ActionShareRevoke)
+		log.Metadata = map[string]any{"shareId": shareID}
+		return log, nil
+	})
+	return err
+}
+
+// Consume - Use one view of a share link (unauthenticated).
+// Unknown, expired and exhausted shares are indistinguishable to the caller.
+func (s *ShareService) Consume(ctx context.Context, shareID string, password, ipAddress, userAgent string) (*share.ShareContentResponse, error) {
+	notFound := errs.NewNotFoundError("Share not found or no longer available", false, nil)
+	sh, err := s.repos.Share.GetActive(ctx, shareID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share: %w", err)
+	}
+	if sh == nil {
+		return nil, notFound
+	}
+	// Check the password before using up a view. A missing password is how
+	// recipients learn that one is needed, so only wrong ones count.
+	if len(sh.PasswordHash) > 0 {
+		if password == "" {
+			return nil, errs.NewUnauthorizedError("Invalid share password", false)
+		}
+		if bcrypt.CompareHashAndPassword(sh.PasswordHash, []byte(password)) != nil {
+			purged, err := s.repos.Share.RecordFailedPassword(ctx, shareID, maxSharePasswordAttempts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to record share password attempt: %w", err)
+			}
+			if purged {
+				s.server.Logger.Warn().Str("share_id", shareID).Msg("share purged after too many wrong passwords")
+				return nil, notFound
+			}
+			return nil, errs.NewUnauthorizedError("Invalid share password", false)
+		}
+	}
+	// The view is used up and recorded in the owner's audit log as one unit of
+	// work, attributed to the anonymous recipient. The recipient is no principal
+	// of ours, so the entry is written unscoped.
+	var consumed *share.Share
+	err = s.repos.WithTx(ctx, func(repos *repository.Repositories) error {
+		var err error
+		if consumed, err = repos.Share.Consume(ctx, shareID); err != nil {
+			return fmt.Errorf("failed to consume share: %w", err)
+		}
+		if consumed == nil {
+			return nil
+		}
+		log := &audit.AuditLog{
+			UserID:    consumed.OwnerID,
+			ActorType: audit.ActorTypeAnonymous,
+			Action:    audit.ActionShareView,
+		}
+		if ipAddress != "" {
+			log.IPAddress = &ipAddress
+		}
+		if userAgent != "" {
+			log.UserAgent = &userAgent
+		}
+		return recordAudit(repository.Unscoped(ctx), repos, shareAuditLog(log, consumed))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if consumed == nil {
+		return nil, notFound
+	}
+
+	return &share.ShareContentResponse{
+		EncryptedPayload: consumed.EncryptedPayload,
+		ViewsRemaining:   consumed.ViewsRemaining,
+		ExpiresAt:        consumed.ExpiresAt,
+	}, nil
+}
+
+func (s *ShareService) registerJobs() {
+	if s.server.Job == nil {
+		return
+	}
+	s.server.Job.HandleFunc(job.TaskShareSweep, unscopedTask(s.handleShareSweepTask))
+	if err := s.server.Job.Schedule(fmt.Sprintf("@every %s", job.ShareSweepInterval), job.NewShareSweepTask()); err != nil {
+		s.server.Logger.Error().Err(err).Msg("failed to schedule share sweep")
+	}
+}
+
+// handleShareSweepTask purges expired share links
+func (s *ShareService) handleShareSweepTask(ctx context.Context, _ *asynq.Task) error {
+	purged, deleted, err := s.repos.Share.SweepExpired(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to sweep expired shares: %w", err)
+	}
+	s.server.Logger.Info().
+		Str("type", "share_sweep").
+		Int64("purged", purged).
+		Int64("deleted", deleted).
+		Msg("Swept expired shares")
+	return nil
+}
+
+// shareAuditLog adds the share and its remaining views to log
+func shareAuditLog(log *audit.AuditLog, sh *share.Share) *audit.AuditLog {
+	log.Metadata = map[string]any{
+		"shareId":        sh.ID.String(),
+		"viewsRemaining": sh.ViewsRemaining,
+	}
+	return log
+}
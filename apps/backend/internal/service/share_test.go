@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/share"
+	"github.com/Sameer16536/psvault/internal/repository"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: Wrong passwords use up no views and purge the share at the limit; views are audited for the owner
+func TestShareService_Consume_Password(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := NewShareService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+	ownerID, ownerCtx := createTestActor(t, ctx, repos, "user_owner")
+
+	req := &share.CreateShareRequest{EncryptedPayload: []byte("payload"), MaxViews: tt.Ptr(2), Password: tt.Ptr("hunter2")}
+	created, err := svc.Create(ownerCtx, ownerID, req)
+	require.NoError(t, err)
+	assert.True(t, created.PasswordProtected)
+
+	// A missing password is not counted as a guess
+	for range maxSharePasswordAttempts {
+		_, err = svc.Consume(context.Background(), created.ID, "", "198.51.100.7", "curl")
+		assertHTTPStatus(t, err, http.StatusUnauthorized)
+	}
+	for range maxSharePasswordAttempts - 1 {
+		_, err = svc.Consume(context.Background(), created.ID, "wrong", "198.51.100.7", "curl")
+		assertHTTPStatus(t, err, http.StatusUnauthorized)
+	}
+
+	content, err := svc.Consume(context.Background(), created.ID, "hunter2", "198.51.100.7", "curl")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), content.EncryptedPayload)
+	assert.Equal(t, 1, content.ViewsRemaining)
+
+	// The last allowed wrong password purges the share
+	_, err = svc.Consume(context.Background(), created.ID, "wrong", "198.51.100.7", "curl")
+	assertHTTPStatus(t, err, http.StatusNotFound)
+	_, err = svc.Consume(context.Background(), created.ID, "hunter2", "198.51.100.7", "curl")
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	logs, err := repos.Audit.ListByUserID(ctx, ownerID, 10)
+	require.NoError(t, err)
+	var actions []audit.Action
+	for _, log := range logs {
+		actions = append(actions, log.Action)
+		if log.Action == audit.ActionShareView {
+			assert.Equal(t, audit.ActorTypeAnonymous, log.ActorType)
+			require.NotNil(t, log.IPAddress)
+			assert.Equal(t, "198.51.100.7", *log.IPAddress)
+		}
+	}
+	assert.ElementsMatch(t, []audit.Action{audit.ActionShareCreate, audit.ActionShareView}, actions)
+}
+
+// Test: Shares are used up by their views, and revoked shares cannot be viewed
+func TestShareService_Consume_ViewsAndRevoke(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := NewShareService(srv, repos)
+	ctx := repository.Unscoped(context.Background())
+	ownerID, ownerCtx := createTestActor(t, ctx, repos, "user_owner")
+	otherID, otherCtx := createTestActor(t, ctx, repos, "user_other")
+
+	once, err := svc.Create(ownerCtx, ownerID, &share.CreateShareRequest{EncryptedPayload: []byte("once")})
+	require.NoError(t, err)
+	content, err := svc.Consume(context.Background(), once.ID, "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, content.ViewsRemaining)
+	_, err = svc.Consume(context.Background(), once.ID, "", "", "")
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	revoked, err := svc.Create(ownerCtx, ownerID, &share.CreateShareRequest{EncryptedPayload: []byte("revoked")})
+	require.NoError(t, err)
+	// Only the owner can revoke a share
+	assertHTTPStatus(t, svc.Revoke(otherCtx, otherID, revoked.ID), http.StatusNotFound)
+	require.NoError(t, svc.Revoke(ownerCtx, ownerID, revoked.ID))
+	_, err = svc.Consume(context.Background(), revoked.ID, "", "", "")
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	shares, err := svc.List(ownerCtx, ownerID)
+	require.NoError(t, err)
+	require.Len(t, shares, 2)
+	for _, sh := range shares {
+		assert.NotNil(t, sh.PurgedAt)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/model/user"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/google/uuid"
+)
+
+// UserService maps Clerk users to the internal user IDs that everything they
+// own references
+type UserService struct {
+	server *server.Server
+	repos  *repository.Repositories
+}
+
+func NewUserService(s *server.Server, repos *repository.Repositories) *UserService {
+	return &UserService{server: s, repos: repos}
+}
+
+// Resolve - Get the internal ID of an authenticated Clerk user, registering the user on first sign-in
+func (s *UserService) Resolve(ctx context.Context, externalAuthID string) (string, error) {
+	id, err := s.repos.User.Resolve(ctx, externalAuthID)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user: %w", err)
+	}
+	return id, nil
+}
+
+// Find - Get a known user by internal ID or Clerk user ID
+func (s *UserService) Find(ctx context.Context, ref string) (*user.User, error) {
+	var u *user.User
+	var err error
+	if _, parseErr := uuid.Parse(ref); parseErr == nil {
+		u, err = s.repos.User.GetByID(ctx, ref)
+	} else {
+		u, err = s.repos.User.GetByExternalAuthID(ctx, ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if u == nil {
+		return nil, errs.NewNotFoundError("User not found", false, nil)
+	}
+	return u, nil
+}
@@ -6,6 +6,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
 	"github.com/Sameer16536/psvault/internal/model/audit"
 	"github.com/Sameer16536/psvault/internal/model/vault"
 	"github.com/Sameer16536/psvault/internal/repository"
@@ -15,51 +17,109 @@ import (
 type VaultService struct {
 	server *server.Server
 	repos  *repository.Repositories
+	users  *UserService
 }
 
-func NewVaultService(s *server.Server, repos *repository.Repositories) *VaultService {
-	return &VaultService{server: s, repos: repos}
+func NewVaultService(s *server.Server, repos *repository.Repositories, users *UserService) *VaultService {
+	return &VaultService{server: s, repos: repos, users: users}
 }
 
-// Create - Create a new vault
+// Create - Create a new personal vault, or an org vault when req.OrgID is set
 func (s *VaultService) Create(ctx context.Context, userID string, req *vault.CreateVaultRequest) (*vault.VaultResponse, error) {
+	if actor.IsServiceAccount(ctx) {
+		return nil, errs.NewForbiddenError("Service accounts cannot own vaults", false)
+	}
+	if req.OrgID != nil {
+		// Org vaults can only be created inside the active organization, by its admins
+		a, ok := actor.FromContext(ctx)
+		if !ok || a.OrgID != *req.OrgID {
+			return nil, errs.NewForbiddenError("Organization is not active for this session", false)
+		}
+		if !a.IsOrgAdmin() {
+			return nil, errs.NewForbiddenError("Only organization admins can create organization vaults", false)
+		}
+	}
 	v := &vault.Vault{
 		UserID:               userID,
+		OrgID:                req.OrgID,
 		Name:                 *req.Name,
 		Description:          req.Description,
 		EncryptedKey:         req.EncryptedKey,
 		KeyEncryptionVersion: req.KeyEncryptionVersion,
 	}
-	if err := s.repos.Vault.Create(ctx, v); err != nil {
-		return nil, fmt.Errorf("failed to create vault: %w", err)
+	_, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Vault.Create(ctx, v); err != nil {
+			return nil, fmt.Errorf("failed to create vault: %w", err)
+		}
+		vaultIDStr := v.ID.String()
+		return newAuditLog(ctx, userID, &vaultIDStr, nil, audit.ActionCreate), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	// Log audit
-	vaultIDStr := v.ID.String()
-	s.logAudit(ctx, userID, &vaultIDStr, nil, audit.ActionCreate)
 	return vault.ToVaultResponse(v), nil
 }
 
 // GetByID - Get vault by ID with authorization check
 func (s *VaultService) GetByID(ctx context.Context, userID, vaultID string) (*vault.VaultResponse, error) {
-	v, err := s.repos.Vault.GetByID(ctx, vaultID)
+	v, err := authorizeVault(ctx, s.repos, userID, vaultID, vaultAccessRead)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get vault: %w", err)
-	}
-	if v == nil {
-		return nil, fmt.Errorf("vault not found")
+		return nil, err
 	}
-	// Authorization check
-	if v.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access to vault")
+	if actor.IsServiceAccount(ctx) || v.UserID != userID {
+		// Everyone but the creator gets the vault key wrapped for them, never the creator's copy.
+		// Org members without a membership grant see the vault but receive no key.
+		v.EncryptedKey = nil
+		m, err := s.repos.VaultMember.Get(ctx, vaultID, memberTypeFromContext(ctx), userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get vault membership: %w", err)
+		}
+		if m != nil {
+			v.EncryptedKey = m.EncryptedKey
+		}
 	}
 	return vault.ToVaultResponse(v), nil
 }
 
-// List - List all vaults for a user
+// List - List personal vaults, vaults of the active organization and vaults shared with the principal
 func (s *VaultService) List(ctx context.Context, userID string) ([]*vault.VaultResponse, error) {
-	vaults, err := s.repos.Vault.ListByUserID(ctx, userID)
+	var vaults []*vault.Vault
+	if !actor.IsServiceAccount(ctx) {
+		owned, err := s.repos.Vault.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list vaults: %w", err)
+		}
+		for _, v := range owned {
+			// Org vaults this user created are listed with their organization
+			if v.OrgID == nil {
+				vaults = append(vaults, v)
+			}
+		}
+	}
+	// Shared vaults carry the key wrapped for this principal, so they win over org listings
+	shared, err := s.repos.Vault.ListByMember(ctx, memberTypeFromContext(ctx), userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list vaults: %w", err)
+		return nil, fmt.Errorf("failed to list shared vaults: %w", err)
+	}
+	vaults = append(vaults, shared...)
+	if orgID := activeOrgID(ctx); orgID != "" {
+		orgVaults, err := s.repos.Vault.ListByOrgID(ctx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organization vaults: %w", err)
+		}
+		seen := make(map[string]bool, len(shared))
+		for _, v := range shared {
+			seen[v.ID.String()] = true
+		}
+		for _, v := range orgVaults {
+			if seen[v.ID.String()] {
+				continue
+			}
+			if v.UserID != userID {
+				v.EncryptedKey = nil
+			}
+			vaults = append(vaults, v)
+		}
 	}
 	responses := make([]*vault.VaultResponse, len(vaults))
 	for i, v := range vaults {
@@ -70,15 +130,9 @@ func (s *VaultService) List(ctx context.Context, userID string) ([]*vault.VaultR
 
 // Update - Update a vault
 func (s *VaultService) Update(ctx context.Context, userID, vaultID string, req *vault.UpdateVaultRequest) (*vault.VaultResponse, error) {
-	v, err := s.repos.Vault.GetByID(ctx, vaultID)
+	v, err := s.getOwnedVault(ctx, userID, vaultID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get vault: %w", err)
-	}
-	if v == nil {
-		return nil, fmt.Errorf("vault not found")
-	}
-	if v.UserID != userID {
-		return nil, fmt.Errorf("unauthorized access to vault")
+		return nil, err
 	}
 	// Update fields if provided
 	if req.Name != nil {
@@ -87,41 +141,128 @@ func (s *VaultService) Update(ctx context.Context, userID, vaultID string, req *
 	if req.Description != nil {
 		v.Description = req.Description
 	}
-	if err := s.repos.Vault.Update(ctx, v); err != nil {
-		return nil, fmt.Errorf("failed to update vault: %w", err)
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Vault.Update(ctx, v); err != nil {
+			return nil, fmt.Errorf("failed to update vault: %w", err)
+		}
+		return newAuditLog(ctx, userID, &vaultID, nil, audit.ActionUpdate), nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	// Log audit
-	s.logAudit(ctx, userID, &vaultID, nil, audit.ActionUpdate)
 	return vault.ToVaultResponse(v), nil
 }
 
 // Delete - Delete a vault
 func (s *VaultService) Delete(ctx context.Context, userID, vaultID string) error {
-	v, err := s.repos.Vault.GetByID(ctx, vaultID)
+	v, err := s.getOwnedVault(ctx, userID, vaultID)
 	if err != nil {
-		return fmt.Errorf("failed to get vault: %w", err)
-	}
-	if v == nil {
-		return fmt.Errorf("vault not found")
+		return err
 	}
-	if v.UserID != userID {
-		return fmt.Errorf("unauthorized access to vault")
+	// Secrets and their attachment rows cascade with the vault; collect the blobs first
+	blobKeys, err := s.repos.Attachment.ListStorageKeysByVaultID(ctx, vaultID)
+	if err != nil {
+		return fmt.Errorf("failed to list attachments: %w", err)
 	}
-	if err := s.repos.Vault.Delete(ctx, vaultID); err != nil {
-		return fmt.Errorf("failed to delete vault: %w", err)
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.Vault.Delete(ctx, vaultID); err != nil {
+			return nil, fmt.Errorf("failed to delete vault: %w", err)
+		}
+		// The vault no longer exists to be referenced, so it is named in metadata
+		log := newAuditLog(ctx, userID, nil, nil, audit.ActionDelete)
+		log.OrgID = v.OrgID
+		log.Metadata = map[string]any{"vaultId": vaultID, "name": v.Name}
+		return log, nil
+	})
+	if err != nil {
+		return err
 	}
-	// Log audit
-	s.logAudit(ctx, userID, &vaultID, nil, audit.ActionDelete)
+	deleteBlobs(ctx, s.server, blobKeys)
 	return nil
 }
-func (s *VaultService) logAudit(ctx context.Context, userID string, vaultID, secretID *string, action audit.Action) {
-	// Get IP and user agent from context (set by middleware)
-	// For now, we'll pass nil - you can enhance this later
-	log := &audit.AuditLog{
-		UserID:   userID,
-		VaultID:  vaultID,
-		SecretID: secretID,
-		Action:   action,
-	}
-	_ = s.repos.Audit.Log(ctx, log)
+
+// AddMember - Grant a user or service account access to a vault (owner only)
+func (s *VaultService) AddMember(ctx context.Context, userID, vaultID string, req *vault.AddMemberRequest) (*vault.MemberResponse, error) {
+	v, err := s.getOwnedVault(ctx, userID, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if req.MemberType == audit.ActorTypeServiceAccount {
+		// Service accounts can only join vaults of an owner acting within the same organization
+		sa, err := s.repos.ServiceAccount.GetByID(ctx, req.MemberID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service account: %w", err)
+		}
+		a, _ := actor.FromContext(ctx)
+		if sa == nil || sa.DisabledAt != nil || a == nil || sa.OrgID != a.OrgID || (v.OrgID != nil && *v.OrgID != sa.OrgID) {
+			return nil, errs.NewNotFoundError("Service account not found", false, nil)
+		}
+	} else {
+		// Users are granted by internal ID or Clerk user ID, and must have signed in before
+		u, err := s.users.Find(ctx, req.MemberID)
+		if err != nil {
+			return nil, err
+		}
+		req.MemberID = u.ID.String()
+		if v.OrgID == nil && req.MemberID == v.UserID {
+			return nil, errs.NewBadRequestError("The vault owner cannot be added as a member", false, nil, nil, nil)
+		}
+	}
+	m := &vault.Member{
+		VaultID:      vaultID,
+		MemberType:   req.MemberType,
+		MemberID:     req.MemberID,
+		Role:         req.Role,
+		EncryptedKey: req.EncryptedKey,
+		GrantedBy:    userID,
+	}
+	_, err = withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		if err := repos.VaultMember.Upsert(ctx, m); err != nil {
+			return nil, fmt.Errorf("failed to add vault member: %w", err)
+		}
+		return newAuditLog(ctx, userID, &vaultID, nil, audit.ActionUpdate), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return vault.ToMemberResponse(m), nil
+}
+
+// ListMembers - List principals granted access to a vault (owner only)
+func (s *VaultService) ListMembers(ctx context.Context, userID, vaultID string) ([]*vault.MemberResponse, error) {
+	if _, err := s.getOwnedVault(ctx, userID, vaultID); err != nil {
+		return nil, err
+	}
+	members, err := s.repos.VaultMember.ListByVaultID(ctx, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vault members: %w", err)
+	}
+	responses := make([]*vault.MemberResponse, len(members))
+	for i, m := range members {
+		responses[i] = vault.ToMemberResponse(m)
+	}
+	return responses, nil
+}
+
+// RemoveMember - Revoke a vault membership (owner only)
+func (s *VaultService) RemoveMember(ctx context.Context, userID, vaultID, memberID string) error {
+	if _, err := s.getOwnedVault(ctx, userID, vaultID); err != nil {
+		return err
+	}
+	_, err := withAudit(ctx, s.repos, func(repos *repository.Repositories) (*audit.AuditLog, error) {
+		removed, err := repos.VaultMember.Delete(ctx, vaultID, memberID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove vault member: %w", err)
+		}
+		if !removed {
+			return nil, errs.NewNotFoundError("Vault member not found", false, nil)
+		}
+		return newAuditLog(ctx, userID, &vaultID, nil, audit.ActionUpdate), nil
+	})
+	return err
+}
+
+// getOwnedVault loads a vault the caller may manage: its personal owner or an admin of its organization
+func (s *VaultService) getOwnedVault(ctx context.Context, userID, vaultID string) (*vault.Vault, error) {
+	return authorizeVault(ctx, s.repos, userID, vaultID, vaultAccessOwner)
 }
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test: A shared vault is listed for its member with the key wrapped for them;
+// only the owner manages members, and a removed member loses access
+func TestVaultService_Sharing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	repos := repository.NewRepositories(srv)
+	svc := NewVaultService(srv, repos, NewUserService(srv, repos))
+	ctx := repository.Unscoped(context.Background())
+
+	ownerID, ownerCtx := createTestActor(t, ctx, repos, "user_owner")
+	memberID, memberCtx := createTestActor(t, ctx, repos, "user_member")
+	strangerID, strangerCtx := createTestActor(t, ctx, repos, "user_stranger")
+	vaultID := createTestVault(t, ctx, repos, ownerID, "Team")
+
+	// Members are granted by Clerk user ID; the owner cannot be one
+	_, err := svc.AddMember(ownerCtx, ownerID, vaultID, &vault.AddMemberRequest{
+		MemberType: audit.ActorTypeUser, MemberID: "user_owner", Role: vault.MemberRoleRead,
+	})
+	assertHTTPStatus(t, err, http.StatusBadRequest)
+	added, err := svc.AddMember(ownerCtx, ownerID, vaultID, &vault.AddMemberRequest{
+		MemberType: audit.ActorTypeUser, MemberID: "user_member", Role: vault.MemberRoleRead, EncryptedKey: []byte("member-key"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, memberID, added.MemberID)
+
+	vaults, err := svc.List(memberCtx, memberID)
+	require.NoError(t, err)
+	require.Len(t, vaults, 1)
+	assert.Equal(t, vaultID, vaults[0].ID)
+	assert.Equal(t, []byte("member-key"), vaults[0].EncryptedKey)
+	shared, err := svc.GetByID(memberCtx, memberID, vaultID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("member-key"), shared.EncryptedKey)
+
+	// Members and strangers cannot manage the vault
+	_, err = svc.ListMembers(memberCtx, memberID, vaultID)
+	assertHTTPStatus(t, err, http.StatusForbidden)
+	assertHTTPStatus(t, svc.RemoveMember(memberCtx, memberID, vaultID, added.ID), http.StatusForbidden)
+	_, err = svc.ListMembers(strangerCtx, strangerID, vaultID)
+	assertHTTPStatus(t, err, http.StatusNotFound)
+	_, err = svc.AddMember(strangerCtx, strangerID, vaultID, &vault.AddMemberRequest{
+		MemberType: audit.ActorTypeUser, MemberID: "user_stranger", Role: vault.MemberRoleWrite,
+	})
+	assertHTTPStatus(t, err, http.StatusNotFound)
+
+	members, err := svc.ListMembers(ownerCtx, ownerID, vaultID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, added.ID, members[0].ID)
+
+	assertHTTPStatus(t, svc.RemoveMember(ownerCtx, ownerID, vaultID, uuid.NewString()), http.StatusNotFound)
+	require.NoError(t, svc.RemoveMember(ownerCtx, ownerID, vaultID, added.ID))
+	vaults, err = svc.List(memberCtx, memberID)
+	require.NoError(t, err)
+	assert.Empty(t, vaults)
+	_, err = svc.GetByID(memberCtx, memberID, vaultID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	signing "github.com/Sameer16536/psvault/internal/lib/webhook"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/webhook"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	defaultDeliveryListLimit = 50
+	// webhookResponseBodyLimit bounds how much of a receiver's response is kept in the delivery log
+	webhookResponseBodyLimit = 1024
+	webhookUserAgent         = "psvault-webhooks/1"
+)
+
+// WebhookService manages outgoing webhooks and delivers audit events to them.
+// Events reach it through the outbox: every unit of work that records an audit
+// entry also enqueues a fan-out task, which creates one delivery per
+// subscribed webhook.
+type WebhookService struct {
+	server *server.Server
+	repos  *repository.Repositories
+	client *http.Client
+}
+
+func NewWebhookService(s *server.Server, repos *repository.Repositories) *WebhookService {
+	svc := &WebhookService{server: s, repos: repos}
+	svc.client = newWebhookClient(!svc.config().AllowPrivateNetworks)
+	svc.registerJobs()
+	return svc
+}
+
+// newWebhookClient - HTTP client for deliveries. Receivers answer where they
+// are registered: redirects are not followed and no proxy is used. With
+// publicOnly, connections to non-public addresses are refused when dialled.
+func newWebhookClient(publicOnly bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if publicOnly {
+		dialer.Control = signing.DialControl
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+func (s *WebhookService) config() *config.WebhookConfig {
+	if s.server.Config != nil && s.server.Config.Webhook != nil {
+		return s.server.Config.Webhook
+	}
+	return config.DefaultWebhookConfig()
+}
+
+// Create - Create a webhook for the caller's personal vaults, or for the active
+// organization's vaults when req.OrgID is set. The returned signing secret is
+// shown only once.
+func (s *WebhookService) Create(ctx context.Context, userID string, req *webhook.CreateWebhookRequest) (*webhook.WebhookSecretResponse, error) {
+	if actor.IsServiceAccount(ctx) {
+		return nil, errs.NewForbiddenError("Service accounts cannot manage webhooks", false)
+	}
+	if req.OrgID != nil {
+		a, ok := actor.FromContext(ctx)
+		if !ok || a.OrgID != *req.OrgID {
+			return nil, errs.NewForbiddenError("Organization is not active for this session", false)
+		}
+		if !a.IsOrgAdmin() {
+			return nil, errs.NewForbiddenError("Only organization admins can manage organization webhooks", false)
+		}
+	}
+	if err := s.validateSettings(ctx, req.URL, req.EventTypes); err != nil {
+		return nil, err
+	}
+	secret, err := newOpaqueToken(webhook.SecretPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	w := &webhook.Webhook{
+		UserID:      userID,
+		OrgID:       req.OrgID,
+		URL:         req.URL,
+		Description: req.Description,
+		EventTypes:  req.EventTypes,
+		Secret:      secret,
+	}
+	if err := s.repos.Webhook.Create(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return &webhook.WebhookSecretResponse{
+		WebhookResponse: *webhook.ToWebhookResponse(w),
+		Secret:          secret,
+	}, nil
+}
+
+// List - List the caller's personal webhooks and, for organization admins, those of the active organization
+func (s *WebhookService) List(ctx context.Context, userID string) ([]*webhook.WebhookResponse, error) {
+	if actor.IsServiceAccount(ctx) {
+		return nil, errs.NewForbiddenError("Service accounts cannot manage webhooks", false)
+	}
+	webhooks, err := s.repos.Webhook.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if a, ok := actor.FromContext(ctx); ok && a.IsOrgAdmin() {
+		orgWebhooks, err := s.repos.Webhook.ListByOrgID(ctx, a.OrgID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list organization webhooks: %w", err)
+		}
+		webhooks = append(webhooks, orgWebhooks...)
+	}
+	responses := make([]*webhook.WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		responses[i] = webhook.ToWebhookResponse(w)
+	}
+	return responses, nil
+}
+
+// GetByID - Get a webhook the caller manages
+func (s *WebhookService) GetByID(ctx context.Context, userID, webhookID string) (*webhook.WebhookResponse, error) {
+	w, err := s.getManagedWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return webhook.ToWebhookResponse(w), nil
+}
+
+// Update - Change a webhook's endpoint, description or filters, or enable or disable it
+func (s *WebhookService) Update(ctx context.Context, userID, webhookID string, req *webhook.UpdateWebhookRequest) (*webhook.WebhookResponse, error) {
+	w, err := s.getManagedWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL != nil {
+		w.URL = *req.URL
+	}
+	if req.Description != nil {
+		w.Description = req.Description
+	}
+	if req.EventTypes != nil {
+		w.EventTypes = *req.EventTypes
+	}
+	if err := s.validateSettings(ctx, w.URL, w.EventTypes); err != nil {
+		return nil, err
+	}
+	if req.Enabled != nil {
+		if *req.Enabled && w.DisabledAt != nil {
+			w.DisabledAt, w.DisabledReason, w.ConsecutiveFailures = nil, nil, 0
+		} else if !*req.Enabled && w.DisabledAt == nil {
+			now, reason := time.Now(), "Disabled by user"
+			w.DisabledAt, w.DisabledReason = &now, &reason
+		}
+	}
+	if err := s.repos.Webhook.Update(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return webhook.ToWebhookResponse(w), nil
+}
+
+// Delete - Delete a webhook and its delivery log
+func (s *WebhookService) Delete(ctx context.Context, userID, webhookID string) error {
+	if _, err := s.getManagedWebhook(ctx, userID, webhookID); err != nil {
+		return err
+	}
+	if err := s.repos.Webhook.Delete(ctx, webhookID); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries - List a webhook's recent deliveries with their response codes
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID, webhookID string, req *webhook.ListDeliveriesRequest) ([]*webhook.DeliveryResponse, error) {
+	if _, err := s.getManagedWebhook(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+	limit := defaultDeliveryListLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	deliveries, err := s.repos.Webhook.ListDeliveries(ctx, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	responses := make([]*webhook.DeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = webhook.ToDeliveryResponse(d)
+	}
+	return responses, nil
+}
+
+// Redeliver - Send the event of an earlier delivery again, as a new delivery
+func (s *WebhookService) Redeliver(ctx context.Context, userID, webhookID, deliveryID string) (*webhook.DeliveryResponse, error) {
+	w, err := s.getManagedWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if w.DisabledAt != nil {
+		return nil, errs.NewBadRequestError("Webhook is disabled; enable it before redelivering", false, nil, nil, nil)
+	}
+	original, err := s.repos.Webhook.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	if original == nil || original.WebhookID != webhookID {
+		return nil, errs.NewNotFoundError("Delivery not found", false, nil)
+	}
+	originalID := original.ID.String()
+	d := &webhook.Delivery{
+		WebhookID:    webhookID,
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		RedeliveryOf: &originalID,
+	}
+	if err := s.repos.Webhook.CreateDelivery(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	if err := s.enqueueDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	return webhook.ToDeliveryResponse(d), nil
+}
+
+// getManagedWebhook loads a webhook the caller may manage: its owner for
+// personal webhooks, an admin of its organization otherwise
+func (s *WebhookService) getManagedWebhook(ctx context.Context, userID, webhookID string) (*webhook.Webhook, error) {
+	if actor.IsServiceAccount(ctx) {
+		return nil, errs.NewForbiddenError("Service accounts cannot manage webhooks", false)
+	}
+	w, err := s.repos.Webhook.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if w != nil {
+		if w.OrgID == nil && w.UserID == userID {
+			return w, nil
+		}
+		if a, ok := actor.FromContext(ctx); ok && w.OrgID != nil && a.IsOrgAdmin() && a.OrgID == *w.OrgID {
+			return w, nil
+		}
+	}
+	return nil, errs.NewNotFoundError("Webhook not found", false, nil)
+}
+
+// validateSettings checks a webhook's URL and event filters. The URL's host
+// must resolve to public addresses only, unless private networks are allowed;
+// deliveries check the address again when they connect.
+func (s *WebhookService) validateSettings(ctx context.Context, rawURL string, eventTypes []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return errs.NewBadRequestError("Webhook URL must be an http or https URL", false, nil, nil, nil)
+	}
+	if !s.config().AllowPrivateNetworks {
+		err := signing.CheckHost(ctx, u.Hostname())
+		if errors.Is(err, signing.ErrForbiddenAddress) {
+			return errs.NewBadRequestError("Webhook URL must point to a public address", false, nil, nil, nil)
+		}
+		if err != nil {
+			return errs.NewBadRequestError("Webhook URL host could not be resolved", false, nil, nil, nil)
+		}
+	}
+	for _, t := range eventTypes {
+		if !webhook.ValidEventFilter(t) {
+			return errs.NewBadRequestError(fmt.Sprintf("Unknown event type %q", t), false, nil, nil, nil)
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) registerJobs() {
+	if s.server.Job == nil {
+		return
+	}
+	s.server.Job.HandleFunc(job.TaskWebhookFanout, unscopedTask(s.handleFanoutTask))
+	s.server.Job.HandleFunc(job.TaskWebhookDeliver, unscopedTask(s.handleDeliverTask))
+}
+
+// handleFanoutTask creates and enqueues a delivery for each webhook subscribed to an audit entry
+func (s *WebhookService) handleFanoutTask(ctx context.Context, t *asynq.Task) error {
+	var log audit.AuditLog
+	if err := json.Unmarshal(t.Payload(), &log); err != nil {
+		return fmt.Errorf("failed to unmarshal audit entry: %w", err)
+	}
+	event := webhook.NewEvent(&log)
+	webhooks, err := s.repos.Webhook.ListSubscribers(ctx, log.OrgID, log.UserID, log.VaultID)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	var payload []byte
+	for _, w := range webhooks {
+		if !w.Subscribes(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("failed to marshal webhook event: %w", err)
+			}
+		}
+		d := &webhook.Delivery{WebhookID: w.ID.String(), EventID: event.ID, EventType: event.Type, Payload: payload}
+		if err := s.repos.Webhook.CreateDelivery(ctx, d); err != nil {
+			return fmt.Errorf("failed to create webhook delivery: %w", err)
+		}
+		// A fan-out retried after a crash finds its deliveries already recorded
+		if d.Status != webhook.DeliveryStatusPending || d.Attempts > 0 {
+			continue
+		}
+		if err := s.enqueueDelivery(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) enqueueDelivery(ctx context.Context, d *webhook.Delivery) error {
+	cfg := s.config()
+	_, err := s.server.Job.Dispatch(ctx, job.TaskWebhookDeliver, job.WebhookDeliverPayload{DeliveryID: d.ID.String()},
+		asynq.TaskID(d.ID.String()),
+		asynq.MaxRetry(cfg.MaxAttempts-1),
+		asynq.Timeout(cfg.Timeout+10*time.Second))
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// handleDeliverTask POSTs a delivery and records the outcome. Failures are
+// retried by asynq with job.WebhookRetryDelay until the delivery runs out of
+// attempts or the webhook is disabled for failing too often.
+func (s *WebhookService) handleDeliverTask(ctx context.Context, t *asynq.Task) error {
+	var p job.WebhookDeliverPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal webhook delivery payload: %w", err)
+	}
+	retry, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	return s.deliver(ctx, p.DeliveryID, retry >= maxRetry)
+}
+
+// deliver makes one attempt at a delivery. A failed attempt leaves it pending
+// for a retry unless lastAttempt is set or the failure disables the webhook,
+// which also ends the retries with asynq.SkipRetry.
+func (s *WebhookService) deliver(ctx context.Context, deliveryID string, lastAttempt bool) error {
+	d, err := s.repos.Webhook.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	// Deleted with its webhook, or already delivered by an earlier attempt
+	if d == nil || d.Status == webhook.DeliveryStatusSucceeded {
+		return nil
+	}
+	w, err := s.repos.Webhook.GetByID(ctx, d.WebhookID)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if w == nil || w.DisabledAt != nil {
+		return nil
+	}
+
+	attempt := s.send(ctx, w, d)
+	if attempt.Succeeded() {
+		if err := s.repos.Webhook.RecordAttempt(ctx, d, webhook.DeliveryStatusSucceeded, attempt); err != nil {
+			return fmt.Errorf("failed to record webhook delivery: %w", err)
+		}
+		return s.repos.Webhook.RecordSuccess(ctx, w.ID.String())
+	}
+
+	cfg := s.config()
+	failure := deliveryFailure(attempt)
+	disabled, err := s.repos.Webhook.RecordFailure(ctx, w.ID.String(), cfg.DisableAfter,
+		fmt.Sprintf("Disabled after %d failed deliveries in a row; last: %s", cfg.DisableAfter, failure))
+	if err != nil {
+		return fmt.Errorf("failed to record webhook failure: %w", err)
+	}
+	final := disabled || lastAttempt
+	status := webhook.DeliveryStatusPending
+	if final {
+		status = webhook.DeliveryStatusFailed
+	}
+	if err := s.repos.Webhook.RecordAttempt(ctx, d, status, attempt); err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	if disabled {
+		s.server.Logger.Warn().
+			Str("webhook_id", w.ID.String()).
+			Str("url", w.URL).
+			Msg("Disabled failing webhook")
+		return fmt.Errorf("webhook disabled: %s: %w", failure, asynq.SkipRetry)
+	}
+	return fmt.Errorf("webhook delivery failed: %s", failure)
+}
+
+// send POSTs a delivery's payload, signed with the webhook's secret
+func (s *WebhookService) send(ctx context.Context, w *webhook.Webhook, d *webhook.Delivery) *webhook.Attempt {
+	ctx, cancel := context.WithTimeout(ctx, s.config().Timeout)
+	defer cancel()
+	attempt := &webhook.Attempt{}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		attempt.Err = err
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(signing.EventHeader, d.EventType)
+	req.Header.Set(signing.DeliveryHeader, d.ID.String())
+	req.Header.Set(signing.SignatureHeader, signing.Sign(w.Secret, time.Now(), d.Payload))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Err = err
+		return attempt
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	attempt.ResponseCode = resp.StatusCode
+	// Kept as text in the delivery log
+	attempt.ResponseBody = strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", "")
+	return attempt
+}
+
+func deliveryFailure(a *webhook.Attempt) string {
+	if a.Err != nil {
+		return a.Err.Error()
+	}
+	return fmt.Sprintf("HTTP %d", a.ResponseCode)
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/config"
+	"github.com/Sameer16536/psvault/internal/errs"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/lib/job"
+	signing "github.com/Sameer16536/psvault/internal/lib/webhook"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/webhook"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/server"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEventType(t *testing.T) {
+	secretID := "a1b2"
+	tests := []struct {
+		log  audit.AuditLog
+		want string
+	}{
+		{audit.AuditLog{Action: audit.ActionCreate}, "vault.create"},
+		{audit.AuditLog{Action: audit.ActionUpdate, SecretID: &secretID}, "secret.update"},
+		{audit.AuditLog{Action: audit.ActionDelete, Metadata: map[string]any{"secretId": secretID}}, "secret.delete"},
+		{audit.AuditLog{Action: audit.ActionDelete, Metadata: map[string]any{"vaultId": "v1"}}, "vault.delete"},
+		{audit.AuditLog{Action: audit.ActionFolderRename}, "folder.rename"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, webhook.EventType(&tt.log))
+	}
+}
+
+func TestWebhookSubscribes(t *testing.T) {
+	all := &webhook.Webhook{}
+	secrets := &webhook.Webhook{EventTypes: []string{"secret.*", "vault.delete"}}
+
+	assert.True(t, all.Subscribes("vault.create"))
+	assert.True(t, secrets.Subscribes("secret.update"))
+	assert.True(t, secrets.Subscribes("vault.delete"))
+	assert.False(t, secrets.Subscribes("vault.create"))
+
+	assert.True(t, webhook.ValidEventFilter("secret.*"))
+	assert.True(t, webhook.ValidEventFilter("vault.update"))
+	assert.False(t, webhook.ValidEventFilter("secret.read"))
+	assert.False(t, webhook.ValidEventFilter("*"))
+}
+
+// Test: Deliveries are signed, carry their event headers and never include encrypted values
+func TestWebhookSend(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("queued"))
+	}))
+	defer receiver.Close()
+
+	secretID, vaultID := uuid.NewString(), uuid.NewString()
+	event := webhook.NewEvent(&audit.AuditLog{
+		ID:        uuid.NewString(),
+		UserID:    uuid.NewString(),
+		ActorType: audit.ActorTypeUser,
+		VaultID:   &vaultID,
+		SecretID:  &secretID,
+		Action:    audit.ActionUpdate,
+		Metadata: map[string]any{
+			"title":            "Email",
+			"encryptedPayload": "c2VjcmV0",
+			"changes":          map[string]any{"encrypted_payload": "c2VjcmV0", "tags": []any{"work"}},
+		},
+		CreatedAt: time.Now(),
+	})
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+	d := &webhook.Delivery{EventID: event.ID, EventType: event.Type, Payload: payload}
+	d.ID = uuid.New()
+	w := &webhook.Webhook{URL: receiver.URL, Secret: "whsec_test"}
+
+	svc := &WebhookService{server: &server.Server{}, client: receiver.Client()}
+	attempt := svc.send(t.Context(), w, d)
+	require.NoError(t, attempt.Err)
+	assert.True(t, attempt.Succeeded())
+	assert.Equal(t, http.StatusAccepted, attempt.ResponseCode)
+	assert.Equal(t, "queued", attempt.ResponseBody)
+
+	r := <-requests
+	assert.Equal(t, "application/json", r.header.Get("Content-Type"))
+	assert.Equal(t, "secret.update", r.header.Get(signing.EventHeader))
+	assert.Equal(t, d.ID.String(), r.header.Get(signing.DeliveryHeader))
+	assert.NoError(t, signing.Verify("whsec_test", r.header.Get(signing.SignatureHeader), r.body, time.Now(), time.Minute))
+	assert.NotContains(t, string(r.body), "ncrypted")
+	assert.Contains(t, string(r.body), `"tags":["work"]`)
+
+	// Failures are reported with the receiver's status code
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	attempt = svc.send(t.Context(), &webhook.Webhook{URL: failing.URL, Secret: "whsec_test"}, d)
+	assert.False(t, attempt.Succeeded())
+	assert.Equal(t, "HTTP 503", deliveryFailure(attempt))
+}
+
+// Test: Receivers on internal addresses are refused when registered and when dialled
+func TestWebhookPrivateNetworks(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	svc := &WebhookService{server: &server.Server{}, client: newWebhookClient(true)}
+	for _, u := range []string{receiver.URL, "http://169.254.169.254/latest/meta-data", "http://localhost:6379", "http://[::1]/"} {
+		err := svc.validateSettings(t.Context(), u, nil)
+		var httpErr *errs.HTTPError
+		require.ErrorAs(t, err, &httpErr, u)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Status)
+	}
+
+	// A host that passed registration but now resolves to an internal address is refused on connect
+	d := &webhook.Delivery{EventType: "vault.create", Payload: []byte(`{}`)}
+	d.ID = uuid.New()
+	attempt := svc.send(t.Context(), &webhook.Webhook{URL: receiver.URL, Secret: "whsec_test"}, d)
+	assert.ErrorIs(t, attempt.Err, signing.ErrForbiddenAddress)
+	assert.False(t, attempt.Succeeded())
+
+	// Development setups may allow them
+	svc = &WebhookService{
+		server: &server.Server{Config: &config.Config{Webhook: &config.WebhookConfig{Timeout: time.Second, AllowPrivateNetworks: true}}},
+		client: newWebhookClient(false),
+	}
+	require.NoError(t, svc.validateSettings(t.Context(), receiver.URL, nil))
+	attempt = svc.send(t.Context(), &webhook.Webhook{URL: receiver.URL, Secret: "whsec_test"}, d)
+	require.NoError(t, attempt.Err)
+	assert.True(t, attempt.Succeeded())
+}
+
+// Test: Fan-out records each event once per webhook; failed attempts stay pending until
+// the last one, failing too often disables the webhook, and redelivery needs it enabled
+func TestWebhookService_Deliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	defer cleanup()
+	srv.Config.Webhook = &config.WebhookConfig{Timeout: 2 * time.Second, MaxAttempts: 3, DisableAfter: 3, AllowPrivateNetworks: true}
+	inspector := tt.SetupTestJobs(t, srv)
+	repos := repository.NewRepositories(srv)
+	svc := NewWebhookService(srv, repos)
+	// Deliveries run as jobs; the API calls below act as the webhook's owner
+	ctx := repository.Unscoped(t.Context())
+
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	var received atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		received.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer receiver.Close()
+
+	userID, err := repos.User.Resolve(ctx, "user_alice")
+	require.NoError(t, err)
+	userCtx := actor.WithActor(t.Context(), &actor.Actor{ID: userID, ExternalID: "user_alice", Type: actor.TypeUser})
+	w := &webhook.Webhook{UserID: userID, URL: receiver.URL, Secret: "whsec_test", EventTypes: []string{"vault.*"}}
+	require.NoError(t, repos.Webhook.Create(ctx, w))
+	webhookID := w.ID.String()
+
+	fanout := func(log *audit.AuditLog) {
+		t.Helper()
+		task, err := job.NewTask(job.TaskWebhookFanout, log)
+		require.NoError(t, err)
+		require.NoError(t, svc.handleFanoutTask(ctx, task))
+	}
+	pendingTasks := func() []*asynq.TaskInfo {
+		t.Helper()
+		tasks, err := inspector.ListPendingTasks("default")
+		require.NoError(t, err)
+		return tasks
+	}
+	deliveries := func() []*webhook.Delivery {
+		t.Helper()
+		ds, err := repos.Webhook.ListDeliveries(ctx, webhookID, 10)
+		require.NoError(t, err)
+		return ds
+	}
+
+	created := &audit.AuditLog{ID: uuid.NewString(), UserID: userID, ActorType: audit.ActorTypeUser, Action: audit.ActionCreate, CreatedAt: time.Now()}
+	fanout(created)
+	// Secret events are filtered out
+	fanout(&audit.AuditLog{ID: uuid.NewString(), UserID: userID, SecretID: tt.Ptr(uuid.NewString()), Action: audit.ActionUpdate, CreatedAt: time.Now()})
+
+	tasks := pendingTasks()
+	require.Len(t, tasks, 1)
+	assert.Equal(t, job.TaskWebhookDeliver, tasks[0].Type)
+	require.Len(t, deliveries(), 1)
+	deliveryID := deliveries()[0].ID.String()
+	assert.Equal(t, deliveryID, tasks[0].ID)
+
+	// An attempt with retries left leaves the delivery pending and asks asynq to retry
+	err = svc.deliver(ctx, deliveryID, false)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, asynq.SkipRetry)
+	d := deliveries()[0]
+	assert.Equal(t, webhook.DeliveryStatusPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	require.NotNil(t, d.ResponseCode)
+	assert.Equal(t, http.StatusInternalServerError, *d.ResponseCode)
+
+	// The last attempt marks it failed
+	require.Error(t, svc.deliver(ctx, deliveryID, true))
+	d = deliveries()[0]
+	assert.Equal(t, webhook.DeliveryStatusFailed, d.Status)
+	assert.Equal(t, 2, d.Attempts)
+
+	// Fanning the same event out again, e.g. after a crash, neither records nor enqueues it again
+	_, err = inspector.DeleteAllPendingTasks("default")
+	require.NoError(t, err)
+	fanout(created)
+	assert.Empty(t, pendingTasks())
+	assert.Len(t, deliveries(), 1)
+
+	// The third failure in a row disables the webhook and stops the retries
+	err = svc.deliver(ctx, deliveryID, false)
+	assert.ErrorIs(t, err, asynq.SkipRetry)
+	disabled, err := repos.Webhook.GetByID(ctx, webhookID)
+	require.NoError(t, err)
+	require.NotNil(t, disabled.DisabledAt)
+	assert.Equal(t, 3, disabled.ConsecutiveFailures)
+	assert.Equal(t, webhook.DeliveryStatusFailed, deliveries()[0].Status)
+
+	// Disabled webhooks get no deliveries and no redeliveries
+	sent := received.Load()
+	require.NoError(t, svc.deliver(ctx, deliveryID, false))
+	assert.Equal(t, sent, received.Load())
+	fanout(&audit.AuditLog{ID: uuid.NewString(), UserID: userID, Action: audit.ActionUpdate, CreatedAt: time.Now()})
+	assert.Empty(t, pendingTasks())
+
+	_, err = svc.Redeliver(userCtx, userID, webhookID, deliveryID)
+	var httpErr *errs.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Status)
+
+	// Once enabled again, a redelivery is a new delivery of the same event
+	_, err = svc.Update(userCtx, userID, webhookID, &webhook.UpdateWebhookRequest{Enabled: tt.Ptr(true)})
+	require.NoError(t, err)
+	redelivery, err := svc.Redeliver(userCtx, userID, webhookID, deliveryID)
+	require.NoError(t, err)
+	require.NotNil(t, redelivery.RedeliveryOf)
+	assert.Equal(t, deliveryID, *redelivery.RedeliveryOf)
+	assert.Equal(t, created.ID, redelivery.EventID)
+	tasks = pendingTasks()
+	require.Len(t, tasks, 1)
+	assert.Equal(t, redelivery.ID, tasks[0].ID)
+
+	status.Store(http.StatusOK)
+	require.NoError(t, svc.deliver(ctx, redelivery.ID, false))
+	ds := deliveries()
+	require.Len(t, ds, 2)
+	assert.Equal(t, webhook.DeliveryStatusSucceeded, ds[0].Status)
+	assert.NotNil(t, ds[0].DeliveredAt)
+	enabled, err := repos.Webhook.GetByID(ctx, webhookID)
+	require.NoError(t, err)
+	assert.Nil(t, enabled.DisabledAt)
+	assert.Zero(t, enabled.ConsecutiveFailures)
+}
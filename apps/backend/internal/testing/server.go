@@ -1,11 +1,15 @@
 package testing
 
 import (
+	"testing"
 	"time"
 
 	"github.com/Sameer16536/psvault/internal/config"
 	"github.com/Sameer16536/psvault/internal/database"
+	"github.com/Sameer16536/psvault/internal/lib/job"
 	"github.com/Sameer16536/psvault/internal/server"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog"
 )
 
@@ -43,3 +47,21 @@ func CreateTestServer(logger *zerolog.Logger, db *TestDB) *server.Server {
 
 	return testServer
 }
+
+// SetupTestJobs gives server a job service backed by an in-memory Redis, so
+// tasks enqueued by services can be inspected. Task handlers are registered
+// but not run; tests call them directly.
+func SetupTestJobs(t *testing.T, s *server.Server) *asynq.Inspector {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	s.Config.Redis.Address = mr.Addr()
+	s.Job = job.NewJobService(s.Logger, s.Config)
+
+	inspector := asynq.NewInspector(asynq.RedisClientOpt{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		_ = inspector.Close()
+		_ = s.Job.Client.Close()
+	})
+	return inspector
+}
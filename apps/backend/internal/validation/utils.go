@@ -90,6 +90,8 @@ func extractValidationErrors(err error) (string, []errs.FieldError) {
 			msg = "must be a comma-separated list of valid UUIDs"
 		case "dive":
 			msg = "some items are invalid"
+		case "secret_type":
+			msg = "must be a registered secret type"
 		default:
 			if err.Param() != "" {
 				msg = fmt.Sprintf("%s: %s:%s", field, err.Tag(), err.Param())
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var (
+	opListAuditLogs    = newOperation("listAuditLogs", http.MethodGet, "/api/audit-logs")
+	opListOrgAuditLogs = newOperation("listOrgAuditLogs", http.MethodGet, "/api/orgs/audit-logs")
+)
+
+// AuditService covers the audit log endpoints
+type AuditService struct {
+	client *Client
+}
+
+// List lists the most recent audit log entries for the caller, newest first
+func (s *AuditService) List(ctx context.Context, params *ListAuditLogsParams) ([]*AuditLog, error) {
+	return s.list(ctx, opListAuditLogs, params)
+}
+
+// ListOrg lists the most recent audit log entries for the caller's active
+// organization. Only organization admins may call it.
+func (s *AuditService) ListOrg(ctx context.Context, params *ListAuditLogsParams) ([]*AuditLog, error) {
+	return s.list(ctx, opListOrgAuditLogs, params)
+}
+
+func (s *AuditService) list(ctx context.Context, op *operation, params *ListAuditLogsParams) ([]*AuditLog, error) {
+	query := url.Values{}
+	if params != nil && params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	var logs []*AuditLog
+	if err := s.client.call(ctx, op, nil, query, nil, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenPath is the client-credentials exchange for service accounts
+const tokenPath = "/api/auth/token"
+
+// tokenRefreshMargin renews access tokens this long before they expire
+const tokenRefreshMargin = 30 * time.Second
+
+// Authenticator adds credentials to outgoing requests
+type Authenticator interface {
+	Authorize(ctx context.Context, req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to Authenticator
+type AuthenticatorFunc func(ctx context.Context, req *http.Request) error
+
+func (f AuthenticatorFunc) Authorize(ctx context.Context, req *http.Request) error {
+	return f(ctx, req)
+}
+
+// clientBinder is implemented by authenticators that call the API themselves
+type clientBinder interface {
+	bind(c *Client)
+}
+
+// BearerToken authenticates with a fixed token, such as a Clerk session
+// token or a service account access token
+func BearerToken(token string) Authenticator {
+	return AuthenticatorFunc(func(_ context.Context, req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// ClientCredentials authenticates as a service account, exchanging its
+// client secret for short-lived access tokens and renewing them as they expire
+func ClientCredentials(clientID, clientSecret string) Authenticator {
+	return &clientCredentials{clientID: clientID, clientSecret: clientSecret}
+}
+
+type clientCredentials struct {
+	clientID     string
+	clientSecret string
+	client       *Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func (a *clientCredentials) bind(c *Client) {
+	a.client = c
+}
+
+func (a *clientCredentials) Authorize(ctx context.Context, req *http.Request) error {
+	token, err := a.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a *clientCredentials) accessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.token != "" && time.Now().Before(a.expiresAt) {
+		return a.token, nil
+	}
+	if a.client == nil {
+		return "", errors.New("client credentials are not attached to a client")
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     a.clientID,
+		"client_secret": a.clientSecret,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := a.client.send(ctx, http.MethodPost, a.client.baseURL.String()+tokenPath, payload, false)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newError(resp)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	a.token = token.AccessToken
+	a.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenRefreshMargin)
+	return a.token, nil
+}
@@ -0,0 +1,188 @@
+// Package client is a typed Go client for the PSVault API.
+//
+// Every operation in static/openapi.json has a method on one of the
+// services hanging off Client; TestOperationsMatchContract keeps the two in
+// step. Secret payloads and vault keys are opaque to the server: use the
+// envelope helpers in this package to encrypt them the same way the web
+// client does.
+//
+//	c, err := client.New("https://api.psvault.com",
+//		client.WithAuth(client.ClientCredentials(clientID, clientSecret)))
+//	vaults, err := c.Vaults.List(ctx)
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultUserAgent = "psvault-go"
+
+// Client talks to one PSVault server. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	auth       Authenticator
+	retry      RetryPolicy
+	userAgent  string
+
+	Vaults  *VaultsService
+	Secrets *SecretsService
+	Devices *DevicesService
+	Audit   *AuditService
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithAuth sets how requests are authenticated
+func WithAuth(auth Authenticator) Option {
+	return func(c *Client) { c.auth = auth }
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) { c.retry = policy }
+}
+
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// New returns a client for the server at baseURL, e.g. https://api.psvault.com
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: expected scheme and host", baseURL)
+	}
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retry:      DefaultRetryPolicy(),
+		userAgent:  defaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if b, ok := c.auth.(clientBinder); ok {
+		b.bind(c)
+	}
+	c.Vaults = &VaultsService{client: c}
+	c.Secrets = &SecretsService{client: c}
+	c.Devices = &DevicesService{client: c}
+	c.Audit = &AuditService{client: c}
+	return c, nil
+}
+
+// operation is one endpoint of the API contract
+type operation struct {
+	ID     string
+	Method string
+	Path   string
+}
+
+// operations lists every endpoint the client implements
+var operations []*operation
+
+func newOperation(id, method, path string) *operation {
+	op := &operation{ID: id, Method: method, Path: path}
+	operations = append(operations, op)
+	return op
+}
+
+// path fills the {param} segments of the operation path in order
+func (op *operation) path(params ...string) string {
+	segments := strings.Split(op.Path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && len(params) > 0 {
+			segments[i] = url.PathEscape(params[0])
+			params = params[1:]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// call sends a JSON request for op and decodes the response into out
+func (c *Client) call(ctx context.Context, op *operation, params []string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("%s: failed to encode request: %w", op.ID, err)
+		}
+	}
+	u := *c.baseURL
+	u.Path += op.path(params...)
+	u.RawQuery = query.Encode()
+
+	resp, err := c.send(ctx, op.Method, u.String(), payload, true)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op.ID, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return newError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s: failed to decode response: %w", op.ID, err)
+	}
+	return nil
+}
+
+// send performs a request, retrying as the retry policy allows. The body
+// is a byte slice so every attempt can resend it.
+func (c *Client) send(ctx context.Context, method, rawURL string, payload []byte, authenticate bool) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		var body io.Reader
+		if payload != nil {
+			body = bytes.NewReader(payload)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if authenticate && c.auth != nil {
+			if err := c.auth.Authorize(ctx, req); err != nil {
+				return nil, fmt.Errorf("failed to authenticate: %w", err)
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		wait, retry := c.retry.next(attempt, method, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
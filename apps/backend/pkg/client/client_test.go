@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sameer16536/psvault/internal/lib/vaultcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetries keeps retry tests quick
+var fastRetries = RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func TestOperationsMatchContract(t *testing.T) {
+	data, err := os.ReadFile("../../static/openapi.json")
+	require.NoError(t, err)
+	var doc struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	var contract, implemented []string
+	for path, methods := range doc.Paths {
+		// The health check is not part of the client
+		if path == "/status" {
+			continue
+		}
+		for method, op := range methods {
+			contract = append(contract, op.OperationID+" "+strings.ToUpper(method)+" "+path)
+		}
+	}
+	for _, op := range operations {
+		implemented = append(implemented, op.ID+" "+op.Method+" "+op.Path)
+	}
+	sort.Strings(contract)
+	sort.Strings(implemented)
+	assert.Equal(t, contract, implemented)
+}
+
+func TestOperationPath(t *testing.T) {
+	assert.Equal(t, "/api/vaults/a%2Fb/secrets", opListVaultSecrets.path("a/b"))
+	assert.Equal(t, "/api/vaults", opListVaults.path())
+}
+
+func TestRetriesRateLimitedRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(Device{ID: "d1"})
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRetryPolicy(fastRetries))
+	require.NoError(t, err)
+	d, err := c.Devices.Register(context.Background(), "fingerprint-1234")
+	require.NoError(t, err)
+	assert.Equal(t, "d1", d.ID)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetriesServerErrorsOnlyWhenIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-Request-ID", "req-1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"SERVICE_UNAVAILABLE","message":"try later"}`))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithRetryPolicy(fastRetries))
+	require.NoError(t, err)
+
+	_, err = c.Vaults.List(context.Background())
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "try later", apiErr.Message)
+	assert.Equal(t, "req-1", apiErr.RequestID)
+	assert.Equal(t, int32(fastRetries.MaxAttempts), calls.Load())
+
+	calls.Store(0)
+	_, err = c.Vaults.Create(context.Background(), &CreateVaultRequest{Name: "Prod"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClientCredentialsCachesToken(t *testing.T) {
+	var exchanges atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == tokenPath {
+			exchanges.Add(1)
+			var req map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "client_credentials", req["grant_type"])
+			assert.Equal(t, "psv_sk_secret", req["client_secret"])
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "psv_at_token", "token_type": "Bearer", "expires_in": 900})
+			return
+		}
+		assert.Equal(t, "Bearer psv_at_token", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithAuth(ClientCredentials("client", "psv_sk_secret")))
+	require.NoError(t, err)
+	for range 3 {
+		_, err := c.Devices.List(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), exchanges.Load())
+}
+
+func TestEnvelopeMatchesVaultCrypto(t *testing.T) {
+	key, err := NewVaultKey()
+	require.NoError(t, err)
+	encrypted, err := EncryptPayload(map[string]string{"password": "s3cret"}, key)
+	require.NoError(t, err)
+
+	plain, err := vaultcrypto.Decrypt(encrypted, key)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"password":"s3cret"}`, string(plain))
+
+	var payload map[string]string
+	require.NoError(t, DecryptPayload(encrypted, key, &payload))
+	assert.Equal(t, "s3cret", payload["password"])
+
+	other, err := NewVaultKey()
+	require.NoError(t, err)
+	assert.ErrorIs(t, DecryptPayload(encrypted, other, &payload), ErrDecrypt)
+}
@@ -0,0 +1,162 @@
+package client_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sameer16536/psvault/internal/handler"
+	"github.com/Sameer16536/psvault/internal/lib/actor"
+	"github.com/Sameer16536/psvault/internal/model/audit"
+	"github.com/Sameer16536/psvault/internal/model/serviceaccount"
+	"github.com/Sameer16536/psvault/internal/model/vault"
+	"github.com/Sameer16536/psvault/internal/repository"
+	"github.com/Sameer16536/psvault/internal/router"
+	"github.com/Sameer16536/psvault/internal/service"
+	tt "github.com/Sameer16536/psvault/internal/testing"
+	"github.com/Sameer16536/psvault/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOrgID      = "org_contract"
+	testOwnerClerk = "user_contract"
+	testMasterPass = "correct horse battery staple"
+)
+
+// contractEnv is the real router backed by a test database, with a vault
+// owned by a user and shared for writing with a service account
+type contractEnv struct {
+	client   *client.Client
+	vault    *vault.Vault
+	vaultKey []byte
+}
+
+func setupContract(t *testing.T) *contractEnv {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	_, srv, cleanup := tt.SetupTest(t)
+	t.Cleanup(cleanup)
+
+	repos := repository.NewRepositories(srv)
+	services, err := service.NewServices(srv, repos)
+	require.NoError(t, err)
+	r, err := router.NewRouter(srv, handler.NewHandlers(srv, services), services)
+	require.NoError(t, err)
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	ctx := repository.Unscoped(context.Background())
+	ownerID, err := repos.User.Resolve(ctx, testOwnerClerk)
+	require.NoError(t, err)
+	vaultKey, err := client.NewVaultKey()
+	require.NoError(t, err)
+	encryptedKey, err := client.EncryptVaultKey(vaultKey, testMasterPass)
+	require.NoError(t, err)
+	v := &vault.Vault{UserID: ownerID, Name: "Deploy", EncryptedKey: encryptedKey}
+	require.NoError(t, repos.Vault.Create(ctx, v))
+
+	// Service accounts are created by org admins and cannot own vaults
+	adminCtx := actor.WithActor(ctx, &actor.Actor{ID: ownerID, ExternalID: testOwnerClerk, Type: actor.TypeUser, OrgID: testOrgID, OrgRole: actor.OrgRoleAdmin})
+	creds, err := services.ServiceAccount.Create(adminCtx, ownerID, &serviceaccount.CreateServiceAccountRequest{Name: "ci"})
+	require.NoError(t, err)
+	require.NoError(t, repos.VaultMember.Upsert(ctx, &vault.Member{
+		VaultID:      v.ID.String(),
+		MemberType:   audit.ActorTypeServiceAccount,
+		MemberID:     creds.ClientID,
+		Role:         vault.MemberRoleWrite,
+		EncryptedKey: encryptedKey,
+		GrantedBy:    ownerID,
+	}))
+
+	c, err := client.New(ts.URL, client.WithAuth(client.ClientCredentials(creds.ClientID, creds.ClientSecret)))
+	require.NoError(t, err)
+	return &contractEnv{client: c, vault: v, vaultKey: vaultKey}
+}
+
+func TestContract_VaultsAndSecrets(t *testing.T) {
+	env := setupContract(t)
+	ctx := context.Background()
+	vaultID := env.vault.ID.String()
+
+	vaults, err := env.client.Vaults.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, vaults, 1)
+	assert.Equal(t, vaultID, vaults[0].ID)
+
+	v, err := env.client.Vaults.Get(ctx, vaultID)
+	require.NoError(t, err)
+	key, err := client.DecryptVaultKey(v.EncryptedKey, testMasterPass)
+	require.NoError(t, err)
+	assert.Equal(t, env.vaultKey, key)
+
+	// Service accounts cannot own vaults
+	_, err = env.client.Vaults.Create(ctx, &client.CreateVaultRequest{Name: "Mine"})
+	assert.True(t, client.IsForbidden(err), "got %v", err)
+
+	payload, err := client.EncryptPayload(map[string]string{"username": "deploy", "password": "s3cret"}, key)
+	require.NoError(t, err)
+	created, err := env.client.Secrets.Create(ctx, &client.CreateSecretRequest{
+		VaultID:           vaultID,
+		Type:              client.SecretTypePassword,
+		EncryptedPayload:  payload,
+		EncryptionVersion: client.PayloadEncryptionVersion,
+		Metadata:          client.SecretMetadata{Title: "Prod DB", Tags: []string{"prod"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, client.SecretStatusActive, created.Status)
+
+	got, err := env.client.Secrets.Get(ctx, created.ID)
+	require.NoError(t, err)
+	var fields map[string]string
+	require.NoError(t, client.DecryptPayload(got.EncryptedPayload, key, &fields))
+	assert.Equal(t, "s3cret", fields["password"])
+
+	listed, err := env.client.Secrets.List(ctx, vaultID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	found, err := env.client.Secrets.Search(ctx, &client.SearchSecretsParams{VaultID: vaultID, Title: "prod"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, created.ID, found[0].ID)
+
+	rotateEvery := 30
+	updated, err := env.client.Secrets.Update(ctx, created.ID, &client.UpdateSecretRequest{
+		Policy: &client.SecretPolicy{RotateEveryDays: &rotateEvery},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, updated.RotationDueAt)
+
+	expiring, err := env.client.Secrets.ListExpiring(ctx, &client.ListExpiringParams{VaultID: vaultID, WithinDays: &rotateEvery})
+	require.NoError(t, err)
+	require.Len(t, expiring, 1)
+	assert.Equal(t, created.ID, expiring[0].ID)
+
+	require.NoError(t, env.client.Secrets.Delete(ctx, created.ID))
+	listed, err = env.client.Secrets.List(ctx, vaultID)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	logs, err := env.client.Audit.List(ctx, &client.ListAuditLogsParams{Limit: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, logs)
+	assert.Equal(t, client.ActorTypeServiceAccount, logs[0].ActorType)
+}
+
+func TestContract_Devices(t *testing.T) {
+	env := setupContract(t)
+	ctx := context.Background()
+
+	// Devices belong to users; service accounts cannot register them
+	_, err := env.client.Devices.Register(ctx, "ci-runner-fingerprint")
+	assert.True(t, client.IsForbidden(err), "got %v", err)
+
+	devices, err := env.client.Devices.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+var (
+	opRegisterDevice = newOperation("registerDevice", http.MethodPost, "/api/devices")
+	opListDevices    = newOperation("listDevices", http.MethodGet, "/api/devices")
+	opDeleteDevice   = newOperation("deleteDevice", http.MethodDelete, "/api/devices/{id}")
+)
+
+// DevicesService covers the /api/devices endpoints
+type DevicesService struct {
+	client *Client
+}
+
+// Register registers a device, or updates its last seen time if the
+// fingerprint is already known
+func (s *DevicesService) Register(ctx context.Context, fingerprint string) (*Device, error) {
+	req := struct {
+		DeviceFingerprint string `json:"deviceFingerprint"`
+	}{fingerprint}
+	var d Device
+	if err := s.client.call(ctx, opRegisterDevice, nil, nil, req, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (s *DevicesService) List(ctx context.Context) ([]*Device, error) {
+	var devices []*Device
+	if err := s.client.call(ctx, opListDevices, nil, nil, nil, &devices); err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+func (s *DevicesService) Delete(ctx context.Context, id string) error {
+	return s.client.call(ctx, opDeleteDevice, []string{id}, nil, nil, nil)
+}
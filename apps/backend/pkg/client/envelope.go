@@ -0,0 +1,60 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Sameer16536/psvault/internal/lib/vaultcrypto"
+)
+
+// The envelope helpers encrypt vault keys and secret payloads the way the
+// web client does, so vaults written by either can be read by the other.
+// Each vault has a random AES-256-GCM key, stored wrapped under a key
+// derived from the vault's master password with PBKDF2-SHA256.
+
+// ErrDecrypt is returned when a key or payload cannot be decrypted, which
+// usually means the master password is wrong
+var ErrDecrypt = vaultcrypto.ErrDecrypt
+
+// PayloadEncryptionVersion is the EncryptionVersion of payloads written by
+// EncryptPayload
+const PayloadEncryptionVersion = 1
+
+// NewVaultKey returns a random vault key
+func NewVaultKey() ([]byte, error) {
+	return vaultcrypto.GenerateVaultKey()
+}
+
+// EncryptVaultKey wraps a vault key under a master password, for
+// CreateVaultRequest.EncryptedKey
+func EncryptVaultKey(vaultKey []byte, masterPassword string) ([]byte, error) {
+	return vaultcrypto.EncryptVaultKey(vaultKey, masterPassword)
+}
+
+// DecryptVaultKey unwraps Vault.EncryptedKey with the vault's master password
+func DecryptVaultKey(encryptedKey []byte, masterPassword string) ([]byte, error) {
+	return vaultcrypto.DecryptVaultKey(encryptedKey, masterPassword)
+}
+
+// EncryptPayload encodes payload as JSON and encrypts it with the vault
+// key. Payloads are JSON objects such as {"username": "...", "password": "..."}.
+func EncryptPayload(payload any, vaultKey []byte) ([]byte, error) {
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return vaultcrypto.Encrypt(plaintext, vaultKey)
+}
+
+// DecryptPayload decrypts Secret.EncryptedPayload with the vault key and
+// decodes the JSON into out
+func DecryptPayload(encryptedPayload, vaultKey []byte, out any) error {
+	plaintext, err := vaultcrypto.Decrypt(encryptedPayload, vaultKey)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(plaintext, out); err != nil {
+		return fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return nil
+}
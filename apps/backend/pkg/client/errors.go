@@ -0,0 +1,88 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBody bounds how much of an error response is read
+const maxErrorBody = 64 << 10
+
+// codeReauthenticationRequired is sent by endpoints behind step-up
+// verification when the session's last sign-in is too old
+const codeReauthenticationRequired = "REAUTHENTICATION_REQUIRED"
+
+// Error is returned for responses with a non-2xx status
+type Error struct {
+	StatusCode int
+	// Code is the server's error code, e.g. NOT_FOUND
+	Code    string
+	Message string
+	// Fields holds validation errors by field name
+	Fields    map[string]string
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("psvault: %d %s (request %s)", e.StatusCode, msg, e.RequestID)
+	}
+	return fmt.Sprintf("psvault: %d %s", e.StatusCode, msg)
+}
+
+// IsNotFound reports whether err is a 404 from the server
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsForbidden reports whether err is a 403 from the server
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsReauthenticationRequired reports whether the server refused err's request
+// until the user signs in again. Only user sessions are asked to; service
+// accounts are exempt.
+func IsReauthenticationRequired(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == codeReauthenticationRequired
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+func newError(resp *http.Response) *Error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+	var body struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Field string `json:"field"`
+			Error string `json:"error"`
+		} `json:"errors"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if json.Unmarshal(data, &body) != nil {
+		return apiErr
+	}
+	apiErr.Code, apiErr.Message = body.Code, body.Message
+	if len(body.Errors) > 0 {
+		apiErr.Fields = make(map[string]string, len(body.Errors))
+		for _, fe := range body.Errors {
+			apiErr.Fields[fe.Field] = fe.Error
+		}
+	}
+	return apiErr
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides how failed requests are retried. Rate-limited
+// requests (429) are retried for every method since the server rejected
+// them before doing any work; server errors (5xx) and network errors are
+// only retried for idempotent methods.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt; 1 or less disables retries
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry; it doubles for each
+	// retry up to MaxBackoff and is jittered
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy makes up to four attempts, backing off from 250ms to 10s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  250 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+	}
+}
+
+// next reports whether the outcome of attempt should be retried and after
+// how long. A Retry-After header takes precedence over the backoff.
+func (p RetryPolicy) next(attempt int, method string, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	switch {
+	case err != nil:
+		if !idempotent(method) {
+			return 0, false
+		}
+	case resp.StatusCode == http.StatusTooManyRequests:
+		if wait, ok := retryAfter(resp); ok {
+			return min(wait, p.MaxBackoff), true
+		}
+	case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+		if !idempotent(method) {
+			return 0, false
+		}
+		if wait, ok := retryAfter(resp); ok {
+			return min(wait, p.MaxBackoff), true
+		}
+	default:
+		return 0, false
+	}
+	return p.backoff(attempt), true
+}
+
+// backoff is exponential with full jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MinBackoff << (attempt - 1)
+	if wait <= 0 || wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(wait)) + 1)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads a Retry-After header given in seconds, as the server's
+// rate limiter sends it
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+var (
+	opCreateSecret        = newOperation("createSecret", http.MethodPost, "/api/secrets")
+	opSearchSecrets       = newOperation("searchSecrets", http.MethodGet, "/api/secrets/search")
+	opListExpiringSecrets = newOperation("listExpiringSecrets", http.MethodGet, "/api/secrets/expiring")
+	opGetSecret           = newOperation("getSecret", http.MethodGet, "/api/secrets/{id}")
+	opUpdateSecret        = newOperation("updateSecret", http.MethodPut, "/api/secrets/{id}")
+	opDeleteSecret        = newOperation("deleteSecret", http.MethodDelete, "/api/secrets/{id}")
+	opListVaultSecrets    = newOperation("listVaultSecrets", http.MethodGet, "/api/vaults/{vaultId}/secrets")
+)
+
+// SecretsService covers the /api/secrets endpoints
+type SecretsService struct {
+	client *Client
+}
+
+// Create stores a secret. Encrypt the payload with EncryptPayload.
+func (s *SecretsService) Create(ctx context.Context, req *CreateSecretRequest) (*Secret, error) {
+	var sec Secret
+	if err := s.client.call(ctx, opCreateSecret, nil, nil, req, &sec); err != nil {
+		return nil, err
+	}
+	return &sec, nil
+}
+
+// List lists every secret in a vault
+func (s *SecretsService) List(ctx context.Context, vaultID string) ([]*Secret, error) {
+	var secrets []*Secret
+	if err := s.client.call(ctx, opListVaultSecrets, []string{vaultID}, nil, nil, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func (s *SecretsService) Search(ctx context.Context, params *SearchSecretsParams) ([]*Secret, error) {
+	query := url.Values{}
+	if params != nil {
+		setQuery(query, "vaultId", params.VaultID)
+		setQuery(query, "type", string(params.Type))
+		setQuery(query, "title", params.Title)
+		setQuery(query, "domain", params.Domain)
+		setQuery(query, "q", params.Q)
+		for _, tag := range params.Tags {
+			query.Add("tags", tag)
+		}
+	}
+	var secrets []*Secret
+	if err := s.client.call(ctx, opSearchSecrets, nil, query, nil, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// ListExpiring lists secrets that are expired, due for rotation, or will be
+// within the window
+func (s *SecretsService) ListExpiring(ctx context.Context, params *ListExpiringParams) ([]*ExpiringSecret, error) {
+	query := url.Values{}
+	if params != nil {
+		setQuery(query, "vaultId", params.VaultID)
+		if params.WithinDays != nil {
+			query.Set("withinDays", strconv.Itoa(*params.WithinDays))
+		}
+	}
+	var secrets []*ExpiringSecret
+	if err := s.client.call(ctx, opListExpiringSecrets, nil, query, nil, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func (s *SecretsService) Get(ctx context.Context, id string) (*Secret, error) {
+	var sec Secret
+	if err := s.client.call(ctx, opGetSecret, []string{id}, nil, nil, &sec); err != nil {
+		return nil, err
+	}
+	return &sec, nil
+}
+
+func (s *SecretsService) Update(ctx context.Context, id string, req *UpdateSecretRequest) (*Secret, error) {
+	var sec Secret
+	if err := s.client.call(ctx, opUpdateSecret, []string{id}, nil, req, &sec); err != nil {
+		return nil, err
+	}
+	return &sec, nil
+}
+
+func (s *SecretsService) Delete(ctx context.Context, id string) error {
+	return s.client.call(ctx, opDeleteSecret, []string{id}, nil, nil, nil)
+}
+
+func setQuery(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
@@ -0,0 +1,171 @@
+package client
+
+import "time"
+
+// Vault is a container of secrets. EncryptedKey is the vault key wrapped
+// for the caller; it is empty when the caller has no copy of the key.
+type Vault struct {
+	ID                   string    `json:"id"`
+	UserID               string    `json:"userId"`
+	OrgID                *string   `json:"orgId,omitempty"`
+	Name                 string    `json:"name"`
+	Description          *string   `json:"description,omitempty"`
+	EncryptedKey         []byte    `json:"encryptedKey,omitempty"`
+	KeyEncryptionVersion *int      `json:"keyEncryptionVersion,omitempty"`
+	CreatedAt            time.Time `json:"createdAt"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+type CreateVaultRequest struct {
+	Name                 string  `json:"name"`
+	Description          *string `json:"description,omitempty"`
+	EncryptedKey         []byte  `json:"encryptedKey,omitempty"`
+	KeyEncryptionVersion *int    `json:"keyEncryptionVersion,omitempty"`
+	// OrgID creates the vault in the caller's active organization
+	OrgID *string `json:"orgId,omitempty"`
+}
+
+type UpdateVaultRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// SecretType names a registered secret type such as password or api_key
+type SecretType string
+
+const (
+	SecretTypePassword SecretType = "password"
+	SecretTypeNote     SecretType = "note"
+	SecretTypeAPIKey   SecretType = "api_key"
+	SecretTypeCard     SecretType = "card"
+)
+
+// SecretStatus is derived from a secret's expiry and rotation policy
+type SecretStatus string
+
+const (
+	SecretStatusActive      SecretStatus = "active"
+	SecretStatusExpiring    SecretStatus = "expiring"
+	SecretStatusRotationDue SecretStatus = "rotation_due"
+	SecretStatusExpired     SecretStatus = "expired"
+)
+
+// SecretMetadata is stored in plain text so it can be searched
+type SecretMetadata struct {
+	Title        string            `json:"title"`
+	Domain       *string           `json:"domain,omitempty"`
+	Tags         []string          `json:"tags,omitempty"`
+	Attributes   map[string]any    `json:"attributes,omitempty"`
+	CustomFields map[string]string `json:"customFields,omitempty"`
+}
+
+// SecretPolicy sets when a secret expires and how often it must be rotated
+type SecretPolicy struct {
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	RotateEveryDays *int       `json:"rotateEveryDays,omitempty"`
+}
+
+// Secret holds a payload encrypted with its vault key, see DecryptPayload
+type Secret struct {
+	ID                string         `json:"id"`
+	VaultID           string         `json:"vaultId"`
+	FolderID          *string        `json:"folderId,omitempty"`
+	Type              SecretType     `json:"type"`
+	EncryptedPayload  []byte         `json:"encryptedPayload"`
+	EncryptionVersion int            `json:"encryptionVersion"`
+	Metadata          SecretMetadata `json:"metadata"`
+	LastAccessedAt    *time.Time     `json:"lastAccessedAt,omitempty"`
+	Status            SecretStatus   `json:"status"`
+	ExpiresAt         *time.Time     `json:"expiresAt,omitempty"`
+	RotateEveryDays   *int           `json:"rotateEveryDays,omitempty"`
+	RotatedAt         time.Time      `json:"rotatedAt"`
+	RotationDueAt     *time.Time     `json:"rotationDueAt,omitempty"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+}
+
+type CreateSecretRequest struct {
+	VaultID           string         `json:"vaultId"`
+	FolderID          *string        `json:"folderId,omitempty"`
+	Type              SecretType     `json:"type"`
+	EncryptedPayload  []byte         `json:"encryptedPayload"`
+	EncryptionVersion int            `json:"encryptionVersion"`
+	Metadata          SecretMetadata `json:"metadata"`
+	Policy            *SecretPolicy  `json:"policy,omitempty"`
+}
+
+type UpdateSecretRequest struct {
+	EncryptedPayload  *[]byte         `json:"encryptedPayload,omitempty"`
+	EncryptionVersion *int            `json:"encryptionVersion,omitempty"`
+	Metadata          *SecretMetadata `json:"metadata,omitempty"`
+	Policy            *SecretPolicy   `json:"policy,omitempty"`
+}
+
+// SearchSecretsParams filters a secret search; unset fields match everything
+type SearchSecretsParams struct {
+	VaultID string
+	Type    SecretType
+	Title   string
+	Domain  string
+	Tags    []string
+	// Q is a full-text query, e.g. `aws tag:prod`
+	Q string
+}
+
+// ListExpiringParams limits the expiring secrets listed. WithinDays
+// defaults to the server's digest window when nil.
+type ListExpiringParams struct {
+	VaultID    string
+	WithinDays *int
+}
+
+// ExpiringSecret is a secret that is expired, due for rotation, or soon will be
+type ExpiringSecret struct {
+	ID              string       `json:"id"`
+	VaultID         string       `json:"vaultId"`
+	Title           string       `json:"title"`
+	Type            SecretType   `json:"type"`
+	Status          SecretStatus `json:"status"`
+	ExpiresAt       *time.Time   `json:"expiresAt,omitempty"`
+	RotateEveryDays *int         `json:"rotateEveryDays,omitempty"`
+	RotatedAt       time.Time    `json:"rotatedAt"`
+	RotationDueAt   *time.Time   `json:"rotationDueAt,omitempty"`
+}
+
+type Device struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"userId"`
+	DeviceFingerprint string    `json:"deviceFingerprint"`
+	LastSeenAt        time.Time `json:"lastSeenAt"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// ActorType says who performed an audited action
+type ActorType string
+
+const (
+	ActorTypeUser           ActorType = "user"
+	ActorTypeServiceAccount ActorType = "service_account"
+	ActorTypeAnonymous      ActorType = "anonymous"
+)
+
+type AuditLog struct {
+	ID        string         `json:"id"`
+	UserID    string         `json:"userId"`
+	ActorType ActorType      `json:"actorType"`
+	OrgID     *string        `json:"orgId,omitempty"`
+	VaultID   *string        `json:"vaultId,omitempty"`
+	SecretID  *string        `json:"secretId,omitempty"`
+	Action    string         `json:"action"`
+	IPAddress *string        `json:"ipAddress,omitempty"`
+	UserAgent *string        `json:"userAgent,omitempty"`
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// ListAuditLogsParams limits an audit log listing. Limit defaults to the
+// server's page size when zero.
+type ListAuditLogsParams struct {
+	Limit int
+}
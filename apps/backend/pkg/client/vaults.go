@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+var (
+	opCreateVault = newOperation("createVault", http.MethodPost, "/api/vaults")
+	opListVaults  = newOperation("listVaults", http.MethodGet, "/api/vaults")
+	opGetVault    = newOperation("getVault", http.MethodGet, "/api/vaults/{id}")
+	opUpdateVault = newOperation("updateVault", http.MethodPut, "/api/vaults/{id}")
+	opDeleteVault = newOperation("deleteVault", http.MethodDelete, "/api/vaults/{id}")
+)
+
+// VaultsService covers the /api/vaults endpoints
+type VaultsService struct {
+	client *Client
+}
+
+// Create creates a vault. Wrap a new key with NewVaultKey and
+// EncryptVaultKey to fill req.EncryptedKey.
+func (s *VaultsService) Create(ctx context.Context, req *CreateVaultRequest) (*Vault, error) {
+	var v Vault
+	if err := s.client.call(ctx, opCreateVault, nil, nil, req, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// List lists personal vaults, vaults of the active organization and vaults
+// shared with the caller
+func (s *VaultsService) List(ctx context.Context) ([]*Vault, error) {
+	var vaults []*Vault
+	if err := s.client.call(ctx, opListVaults, nil, nil, nil, &vaults); err != nil {
+		return nil, err
+	}
+	return vaults, nil
+}
+
+func (s *VaultsService) Get(ctx context.Context, id string) (*Vault, error) {
+	var v Vault
+	if err := s.client.call(ctx, opGetVault, []string{id}, nil, nil, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (s *VaultsService) Update(ctx context.Context, id string, req *UpdateVaultRequest) (*Vault, error) {
+	var v Vault
+	if err := s.client.call(ctx, opUpdateVault, []string{id}, nil, req, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Delete deletes a vault and its secrets. The server requires a recent
+// sign-in for this operation.
+func (s *VaultsService) Delete(ctx context.Context, id string) error {
+	return s.client.call(ctx, opDeleteVault, []string{id}, nil, nil, nil)
+}
@@ -169,3 +169,26 @@ export async function decryptVaultKey(encryptedBase64: string, masterPassword: s
     // 6. Import as CryptoKey
     return await importKey(decryptedBytes.buffer as ArrayBuffer);
 }
+
+// Breach checks use k-anonymity: only the first 5 hex characters of the
+// password's SHA-1 hash leave the client (GET /api/breach/range/:prefix).
+export const BREACH_PREFIX_LENGTH = 5;
+
+// Split the upper-case hex SHA-1 of a password into range prefix and suffix
+export async function breachHashParts(password: string): Promise<{ prefix: string; suffix: string }> {
+    const digest = await crypto.subtle.digest('SHA-1', new TextEncoder().encode(password));
+    const hex = Array.from(new Uint8Array(digest), b => b.toString(16).padStart(2, '0')).join('').toUpperCase();
+    return { prefix: hex.slice(0, BREACH_PREFIX_LENGTH), suffix: hex.slice(BREACH_PREFIX_LENGTH) };
+}
+
+// Find how often a password appears in a range response ("SUFFIX:COUNT" lines).
+// Returns 0 when it is absent; padding entries have a count of 0.
+export function breachCount(rangeBody: string, suffix: string): number {
+    for (const line of rangeBody.split('\n')) {
+        const [entrySuffix, count] = line.trim().split(':');
+        if (entrySuffix === suffix) {
+            return parseInt(count, 10) || 0;
+        }
+    }
+    return 0;
+}
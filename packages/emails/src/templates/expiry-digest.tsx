@@ -0,0 +1,86 @@
+import {
+  Body,
+  Button,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+
+interface ExpiryDigestEmailProps {
+  title: string;
+  vaultName: string;
+  status: string;
+  date: string;
+}
+
+// The item row is wrapped in a Go template range so the backend can render
+// any number of secrets from one export.
+export const ExpiryDigestEmail = ({
+  title = "{{.Title}}",
+  vaultName = "{{.VaultName}}",
+  status = "{{.Status}}",
+  date = "{{.Date}}",
+}: ExpiryDigestEmailProps) => {
+  return (
+    <Html>
+      <Head />
+      <Preview>Secrets that need your attention</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Heading className="text-2xl font-bold text-gray-800 mt-4">
+              Secrets that need your attention
+            </Heading>
+
+            <Section>
+              <Text className="text-gray-700 text-base">
+                The following secrets in your vaults have expired, are due for
+                rotation or will expire soon:
+              </Text>
+              {"{{range .Items}}"}
+              <Text className="text-gray-700 text-base">
+                <strong>{title}</strong> in {vaultName}
+                <br />
+                {status} ({date})
+              </Text>
+              {"{{end}}"}
+            </Section>
+
+            <Section className="my-8 text-center">
+              <Button
+                className="bg-orange-600 hover:bg-orange-700 text-white font-medium rounded-md px-6 py-3"
+                href={`/dashboard`}
+              >
+                Review Secrets
+              </Button>
+            </Section>
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                You receive this digest once a day while secrets you own need
+                attention.
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+ExpiryDigestEmail.PreviewProps = {
+  title: "Production database",
+  vaultName: "Infrastructure",
+  status: "Rotation due",
+  date: "2026-03-01",
+};
+
+export default ExpiryDigestEmail;
@@ -0,0 +1,88 @@
+import {
+  Body,
+  Button,
+  Container,
+  Head,
+  Heading,
+  Hr,
+  Html,
+  Preview,
+  Section,
+  Text,
+  Tailwind,
+} from "@react-email/components";
+
+interface SecurityAlertEmailProps {
+  reason: string;
+  ipAddress: string;
+  userAgent: string;
+  detectedAt: string;
+  response: string;
+}
+
+export const SecurityAlertEmail = ({
+  reason = "{{.Reason}}",
+  ipAddress = "{{.IPAddress}}",
+  userAgent = "{{.UserAgent}}",
+  detectedAt = "{{.DetectedAt}}",
+  response = "{{.Response}}",
+}: SecurityAlertEmailProps) => {
+  return (
+    <Html>
+      <Head />
+      <Preview>Unusual activity on your vault</Preview>
+      <Tailwind>
+        <Body className="bg-gray-100 font-sans">
+          <Container className="bg-white p-8 rounded-lg shadow-sm my-10 mx-auto max-w-[600px]">
+            <Heading className="text-2xl font-bold text-gray-800 mt-4">
+              Unusual activity detected
+            </Heading>
+
+            <Section>
+              <Text className="text-gray-700 text-base">
+                We noticed activity on your account that doesn't match your
+                usual pattern: {reason}.
+              </Text>
+              <Text className="text-gray-700 text-base">
+                Time: {detectedAt}
+                <br />
+                IP address: {ipAddress}
+                <br />
+                Device: {userAgent}
+              </Text>
+              <Text className="text-gray-700 text-base">{response}</Text>
+            </Section>
+
+            <Section className="my-8 text-center">
+              <Button
+                className="bg-orange-600 hover:bg-orange-700 text-white font-medium rounded-md px-6 py-3"
+                href={`/dashboard`}
+              >
+                Review Activity
+              </Button>
+            </Section>
+
+            <Hr className="border-gray-200 my-6" />
+
+            <Section>
+              <Text className="text-gray-600 text-sm">
+                If this wasn't you, change your master password and sign out of
+                all sessions.
+              </Text>
+            </Section>
+          </Container>
+        </Body>
+      </Tailwind>
+    </Html>
+  );
+};
+
+SecurityAlertEmail.PreviewProps = {
+  reason: "an unusually high number of secrets were revealed",
+  ipAddress: "203.0.113.7",
+  userAgent: "Mozilla/5.0",
+  detectedAt: "2026-02-07 20:00 UTC",
+  response: "No further action was taken.",
+};
+
+export default SecurityAlertEmail;
@@ -0,0 +1,38 @@
+import { initContract, type AppRouter } from "@ts-rest/core";
+import {
+    ZListAuditLogsRequest,
+    ZAuditLogResponse,
+} from "@boilerplate/zod";
+import { getSecurityMetadata } from "@/utils.js";
+
+const c = initContract();
+
+export const auditContract: AppRouter = c.router(
+    {
+        listAuditLogs: {
+            summary: "List Audit Logs",
+            path: "/api/audit-logs",
+            method: "GET",
+            description: "Get the most recent audit log entries for the caller",
+            query: ZListAuditLogsRequest,
+            responses: {
+                200: ZAuditLogResponse.array(),
+            },
+            metadata: getSecurityMetadata(),
+        },
+        listOrgAuditLogs: {
+            summary: "List Organization Audit Logs",
+            path: "/api/orgs/audit-logs",
+            method: "GET",
+            description: "Get the most recent audit log entries for the active organization (admins only)",
+            query: ZListAuditLogsRequest,
+            responses: {
+                200: ZAuditLogResponse.array(),
+            },
+            metadata: getSecurityMetadata(),
+        },
+    },
+    {
+        pathPrefix: "",
+    }
+);
@@ -3,6 +3,7 @@ import { healthContract } from "./health.js";
 import { vaultContract } from "./vault.js";
 import { secretContract } from "./secret.js";
 import { deviceContract } from "./device.js";
+import { auditContract } from "./audit.js";
 
 const c = initContract();
 
@@ -11,4 +12,5 @@ export const apiContract: AppRouter = c.router({
   Vault: vaultContract,
   Secret: secretContract,
   Device: deviceContract,
+  Audit: auditContract,
 });
@@ -4,6 +4,8 @@ import {
     ZUpdateSecretRequest,
     ZSecretResponse,
     ZSearchSecretsRequest,
+    ZListExpiringRequest,
+    ZExpiringSecretResponse,
 } from "@boilerplate/zod";
 import { getSecurityMetadata } from "@/utils.js";
 
@@ -33,6 +35,17 @@ export const secretContract: AppRouter = c.router(
             },
             metadata: getSecurityMetadata(),
         },
+        listExpiringSecrets: {
+            summary: "List Expiring Secrets",
+            path: "/api/secrets/expiring",
+            method: "GET",
+            description: "List secrets that are expired, due for rotation, or will be within the window",
+            query: ZListExpiringRequest,
+            responses: {
+                200: ZExpiringSecretResponse.array(),
+            },
+            metadata: getSecurityMetadata(),
+        },
         getSecret: {
             summary: "Get Secret",
             path: "/api/secrets/:id",
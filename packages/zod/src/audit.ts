@@ -0,0 +1,26 @@
+import { z } from "zod";
+
+// ============================================
+// Audit Log Schemas
+// ============================================
+
+export const ZListAuditLogsRequest = z.object({
+    limit: z.number().int().min(1).max(500).optional(),
+});
+
+export const ZAuditLogResponse = z.object({
+    id: z.string().uuid(),
+    userId: z.string(),
+    actorType: z.enum(["user", "service_account", "anonymous"]),
+    orgId: z.string().optional(),
+    vaultId: z.string().uuid().optional(),
+    secretId: z.string().uuid().optional(),
+    action: z.string(),
+    ipAddress: z.string().optional(),
+    userAgent: z.string().optional(),
+    metadata: z.record(z.unknown()).optional(),
+    createdAt: z.string().datetime(),
+});
+
+export type ListAuditLogsRequest = z.infer<typeof ZListAuditLogsRequest>;
+export type AuditLogResponse = z.infer<typeof ZAuditLogResponse>;
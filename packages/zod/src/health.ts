@@ -6,6 +6,13 @@ const ZHealthCheck = z.object({
   error: z.string().optional(),
 });
 
+const ZMigrationCheck = z.object({
+  status: z.enum(["healthy", "pending", "unhealthy"]),
+  current: z.number().int().optional(),
+  latest: z.number().int().optional(),
+  error: z.string().optional(),
+});
+
 export const ZHealthResponse = z.object({
   status: z.enum(["healthy", "unhealthy"]),
   timestamp: z.string().datetime(),
@@ -13,5 +20,6 @@ export const ZHealthResponse = z.object({
   checks: z.object({
     database: ZHealthCheck,
     redis: ZHealthCheck.optional(),
+    migrations: ZMigrationCheck.optional(),
   }),
 });
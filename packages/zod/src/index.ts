@@ -7,4 +7,5 @@ export * from "./utils.js";
 export * from "./health.js";
 export * from "./vault.js";
 export * from "./secret.js";
-export * from "./device.js";
\ No newline at end of file
+export * from "./device.js";
+export * from "./audit.js";
\ No newline at end of file
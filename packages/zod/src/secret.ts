@@ -4,26 +4,72 @@ import { z } from "zod";
 // Secret Schemas
 // ============================================
 
-export const ZSecretType = z.enum(["password", "note", "api_key", "card"]);
+// Secret types are registered server-side; see GET /api/secret-types
+export const ZSecretType = z.string().regex(/^[a-z][a-z0-9_]{0,49}$/);
+
+export const ZSecretFieldSchema = z.object({
+    name: z.string(),
+    label: z.string(),
+    kind: z.enum(["text", "url", "hostname", "email", "integer"]),
+    required: z.boolean().optional(),
+    maxLength: z.number().int().optional(),
+    pattern: z.string().optional(),
+    min: z.number().int().optional(),
+    max: z.number().int().optional(),
+});
+
+export const ZSecretTypeDefinition = z.object({
+    name: ZSecretType,
+    displayName: z.string(),
+    description: z.string().optional(),
+    fields: z.array(ZSecretFieldSchema),
+});
 
 export const ZSecretMetadata = z.object({
     title: z.string().min(1).max(200),
     domain: z.string().max(255).optional(),
     tags: z.array(z.string()).optional(),
+    attributes: z.record(z.string(), z.union([z.string(), z.number()])).optional(),
+    customFields: z.record(z.string().min(1).max(50), z.string().max(500)).optional(),
+});
+
+// Replaces the whole policy when sent on update
+export const ZSecretPolicy = z.object({
+    expiresAt: z.string().datetime().optional(),
+    rotateEveryDays: z.number().int().min(1).max(3650).optional(),
 });
 
+export const ZSecretStatus = z.enum(["active", "expiring", "rotation_due", "expired"]);
+
 export const ZCreateSecretRequest = z.object({
     vaultId: z.string().uuid(),
+    folderId: z.string().uuid().optional(),
     type: ZSecretType,
     encryptedPayload: z.string(), // Base64 encoded
     encryptionVersion: z.number().int().min(1),
     metadata: ZSecretMetadata,
+    policy: ZSecretPolicy.optional(),
 });
 
 export const ZUpdateSecretRequest = z.object({
     encryptedPayload: z.string().optional(),
     encryptionVersion: z.number().int().min(1).optional(),
     metadata: ZSecretMetadata.optional(),
+    policy: ZSecretPolicy.optional(),
+});
+
+export const ZTransferSecretRequest = z.object({
+    mode: z.enum(["move", "copy"]),
+    vaultId: z.string().uuid(),
+    folderId: z.string().uuid().optional(),
+    encryptedPayload: z.string(), // Base64, re-encrypted for the target vault
+    encryptionVersion: z.number().int().min(1),
+});
+
+export const ZSetPasswordHealthRequest = z.object({
+    strengthScore: z.number().int().min(0).max(4),
+    reuseFingerprint: z.string().regex(/^[0-9a-fA-F]{32,128}$/).optional(), // HMAC under a vault-derived key
+    lastRotatedAt: z.string().datetime().optional(),
 });
 
 export const ZSearchSecretsRequest = z.object({
@@ -32,23 +78,55 @@ export const ZSearchSecretsRequest = z.object({
     title: z.string().optional(),
     domain: z.string().optional(),
     tags: z.array(z.string()).optional(),
+    q: z.string().max(500).optional(),
 });
 
 export const ZSecretResponse = z.object({
     id: z.string().uuid(),
     vaultId: z.string().uuid(),
+    folderId: z.string().uuid().optional(),
     type: ZSecretType,
     encryptedPayload: z.string(),
     encryptionVersion: z.number().int(),
     metadata: ZSecretMetadata,
+    status: ZSecretStatus,
+    expiresAt: z.string().datetime().optional(),
+    rotateEveryDays: z.number().int().optional(),
+    rotatedAt: z.string().datetime(),
+    rotationDueAt: z.string().datetime().optional(),
     lastAccessedAt: z.string().datetime().nullable(),
     createdAt: z.string().datetime(),
     updatedAt: z.string().datetime(),
 });
 
+export const ZListExpiringRequest = z.object({
+    vaultId: z.string().uuid().optional(),
+    withinDays: z.number().int().min(0).max(365).optional(),
+});
+
+export const ZExpiringSecretResponse = z.object({
+    id: z.string().uuid(),
+    vaultId: z.string().uuid(),
+    title: z.string(),
+    type: ZSecretType,
+    status: ZSecretStatus,
+    expiresAt: z.string().datetime().optional(),
+    rotateEveryDays: z.number().int().optional(),
+    rotatedAt: z.string().datetime(),
+    rotationDueAt: z.string().datetime().optional(),
+});
+
 export type SecretType = z.infer<typeof ZSecretType>;
+export type SecretFieldSchema = z.infer<typeof ZSecretFieldSchema>;
+export type SecretTypeDefinition = z.infer<typeof ZSecretTypeDefinition>;
 export type SecretMetadata = z.infer<typeof ZSecretMetadata>;
+export type SecretPolicy = z.infer<typeof ZSecretPolicy>;
+export type SecretStatus = z.infer<typeof ZSecretStatus>;
 export type CreateSecretRequest = z.infer<typeof ZCreateSecretRequest>;
 export type UpdateSecretRequest = z.infer<typeof ZUpdateSecretRequest>;
+export type TransferSecretRequest = z.infer<typeof ZTransferSecretRequest>;
+export type SetPasswordHealthRequest = z.infer<typeof ZSetPasswordHealthRequest>;
 export type SearchSecretsRequest = z.infer<typeof ZSearchSecretsRequest>;
 export type SecretResponse = z.infer<typeof ZSecretResponse>;
+export type ListExpiringRequest = z.infer<typeof ZListExpiringRequest>;
+export type ExpiringSecretResponse = z.infer<typeof ZExpiringSecretResponse>;